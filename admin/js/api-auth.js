@@ -0,0 +1,61 @@
+// API Auth - Sends the API token of the admin with the admin API requests
+
+class ApiAuth {
+  static STORAGE_KEY = "metalFestsApiToken";
+
+  /**
+   * Whether a fetch input targets the API of this server
+   * @param {string|Request|URL} input - The fetch input
+   */
+  static isApiRequest(input) {
+    const href = typeof input === "string" ? input : input.url || input.href;
+    const url = new URL(href, window.location.origin);
+    return url.origin === window.location.origin && url.pathname.startsWith("/api/");
+  }
+
+  /**
+   * Returns the fetch options with the token as bearer credentials
+   * @param {object} init - The fetch options
+   * @param {string|null} token - The API token, if any
+   */
+  static withToken(init = {}, token) {
+    if (!token) return init;
+    const headers = new Headers(init.headers || {});
+    headers.set("Authorization", `Bearer ${token}`);
+    return { ...init, headers };
+  }
+
+  /**
+   * Wraps fetch so the API requests carry the token kept for the session.
+   * When the API asks for credentials, the token is asked for and the
+   * request sent again once.
+   * @param {Window} target - The window whose fetch is wrapped
+   */
+  static install(target = window) {
+    const originalFetch = target.fetch.bind(target);
+    target.fetch = async (input, init) => {
+      if (!ApiAuth.isApiRequest(input)) {
+        return originalFetch(input, init);
+      }
+
+      const response = await originalFetch(input, ApiAuth.withToken(init, sessionStorage.getItem(ApiAuth.STORAGE_KEY)));
+      if (response.status !== 401) {
+        return response;
+      }
+
+      const token = target.prompt("The admin API requires a token. Enter your API token or OIDC access token:")?.trim();
+      if (!token) {
+        return response;
+      }
+      sessionStorage.setItem(ApiAuth.STORAGE_KEY, token);
+      return originalFetch(input, ApiAuth.withToken(init, token));
+    };
+  }
+}
+
+// Export for testing, install in the browser
+if (typeof module !== "undefined" && module.exports) {
+  module.exports = ApiAuth;
+} else {
+  ApiAuth.install();
+}
@@ -0,0 +1,67 @@
+// Unit tests for ApiAuth
+// ApiAuth is loaded globally via vitest.setup.js
+
+describe("ApiAuth", () => {
+  let target;
+  let fetchMock;
+
+  beforeEach(() => {
+    sessionStorage.clear();
+    fetchMock = vi.fn();
+    target = { fetch: fetchMock, prompt: vi.fn() };
+    ApiAuth.install(target);
+  });
+
+  afterEach(() => {
+    vi.restoreAllMocks();
+  });
+
+  it("recognizes the API requests of this server", () => {
+    expect(ApiAuth.isApiRequest("/api/bands/gojira")).toBe(true);
+    expect(ApiAuth.isApiRequest(`${window.location.origin}/api/validate-url`)).toBe(true);
+    expect(ApiAuth.isApiRequest("/db.json")).toBe(false);
+    expect(ApiAuth.isApiRequest("https://example.com/api/bands")).toBe(false);
+  });
+
+  it("leaves other requests alone", async () => {
+    fetchMock.mockResolvedValueOnce({ status: 200 });
+    sessionStorage.setItem(ApiAuth.STORAGE_KEY, "secret");
+
+    await target.fetch("/db.json");
+
+    expect(fetchMock).toHaveBeenCalledWith("/db.json", undefined);
+  });
+
+  it("sends the token kept for the session", async () => {
+    fetchMock.mockResolvedValueOnce({ status: 200 });
+    sessionStorage.setItem(ApiAuth.STORAGE_KEY, "secret");
+
+    await target.fetch("/api/bands/gojira", { method: "PUT", headers: { "Content-Type": "application/json" } });
+
+    const init = fetchMock.mock.calls[0][1];
+    expect(init.headers.get("Authorization")).toBe("Bearer secret");
+    expect(init.headers.get("Content-Type")).toBe("application/json");
+  });
+
+  it("asks for a token and retries once when the API requires one", async () => {
+    fetchMock.mockResolvedValueOnce({ status: 401 }).mockResolvedValueOnce({ status: 200 });
+    target.prompt.mockReturnValueOnce(" new-token ");
+
+    const response = await target.fetch("/api/bands/gojira", { method: "PUT", body: "{}" });
+
+    expect(response.status).toBe(200);
+    expect(fetchMock).toHaveBeenCalledTimes(2);
+    expect(fetchMock.mock.calls[1][1].headers.get("Authorization")).toBe("Bearer new-token");
+    expect(sessionStorage.getItem(ApiAuth.STORAGE_KEY)).toBe("new-token");
+  });
+
+  it("returns the 401 response when no token is given", async () => {
+    fetchMock.mockResolvedValueOnce({ status: 401 });
+    target.prompt.mockReturnValueOnce(null);
+
+    const response = await target.fetch("/api/admin/stats");
+
+    expect(response.status).toBe(401);
+    expect(fetchMock).toHaveBeenCalledTimes(1);
+  });
+});
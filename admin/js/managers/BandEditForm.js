@@ -594,7 +594,9 @@ class BandEditForm {
       }
     });
 
+    // Keep the fields the form does not edit, like provenance, so saving does not drop them
     return {
+      ...this.currentBand,
       key: form.elements.key.value.trim(),
       name: form.elements.name.value.trim(),
       country: form.elements.country.value.trim(),
@@ -603,12 +605,20 @@ class BandEditForm {
       logo: form.elements.logo.value.trim(),
       website: form.elements.website.value.trim(),
       spotify: form.elements.spotify.value.trim(),
+      aliases: this.parseAliases(form.elements.aliases.value),
       genres: this.currentBand?.genres || [],
       reviewed: form.elements.reviewed.checked,
       members,
     };
   }
 
+  parseAliases(value) {
+    return value
+      .split(",")
+      .map((alias) => alias.trim())
+      .filter((alias) => alias.length > 0);
+  }
+
   validate(band) {
     if (!band.key || band.key.length < 2) {
       window.notificationManager?.show("Band key must be at least 2 characters", "error");
@@ -758,6 +768,7 @@ class BandEditForm {
             <!-- Name -->
             <div class="form-group">
               <label for="bandName">Band Name*</label>
+              ${ProvenanceBadge.render(band.provenance, "name")}
               <input
                 type="text"
                 id="bandName"
@@ -769,9 +780,23 @@ class BandEditForm {
               >
             </div>
 
+            <!-- Aliases -->
+            <div class="form-group">
+              <label for="bandAliases">Aliases</label>
+              <input
+                type="text"
+                id="bandAliases"
+                name="aliases"
+                value="${this.escapeHtml((band.aliases || []).join(", "))}"
+                placeholder="Other spellings used on lineups, comma separated"
+                data-field="aliases"
+              >
+            </div>
+
             <!-- Country -->
             <div class="form-group">
               <label for="bandCountry">Country*</label>
+              ${ProvenanceBadge.render(band.provenance, "country")}
               <input
                 type="text"
                 id="bandCountry"
@@ -786,6 +811,7 @@ class BandEditForm {
             <!-- Description -->
             <div class="form-group">
               <label for="bandDescription">Description*</label>
+              ${ProvenanceBadge.render(band.provenance, "description")}
               <textarea
                 id="bandDescription"
                 name="description"
@@ -798,6 +824,7 @@ class BandEditForm {
             <!-- Headline Image -->
             <div class="form-group">
               <label for="bandHeadlineImage">Headline Image URL*</label>
+              ${ProvenanceBadge.render(band.provenance, "headlineImage")}
               <div class="image-preview" id="headlineImagePreview">
                 ${this.renderImagePreview(band.headlineImage)}
               </div>
@@ -823,6 +850,7 @@ class BandEditForm {
             <!-- Logo -->
             <div class="form-group">
               <label for="bandLogo">Logo URL*</label>
+              ${ProvenanceBadge.render(band.provenance, "logo")}
               <div class="image-preview" id="logoPreview">
                 ${this.renderImagePreview(band.logo)}
               </div>
@@ -848,6 +876,7 @@ class BandEditForm {
             <!-- Website -->
             <div class="form-group">
               <label for="bandWebsite">Website*</label>
+              ${ProvenanceBadge.render(band.provenance, "website")}
               <div class="url-field-container">
                 <input
                   type="url"
@@ -869,6 +898,7 @@ class BandEditForm {
             <!-- Spotify -->
             <div class="form-group">
               <label for="bandSpotify">Spotify URL</label>
+              ${ProvenanceBadge.render(band.provenance, "spotify")}
               <div class="url-field-container">
                 <input
                   type="url"
@@ -889,6 +919,7 @@ class BandEditForm {
             <!-- Genres -->
             <div class="form-group">
               <label>Genres*</label>
+              ${ProvenanceBadge.render(band.provenance, "genres")}
               <div class="selected-genres-display">
                 <strong>Selected Genres:</strong> ${band.genres && band.genres.length > 0 ? band.genres.join(", ") : "None"}
               </div>
@@ -913,6 +944,7 @@ class BandEditForm {
             <!-- Members -->
             <div class="form-group">
               <label>Band Members</label>
+              ${ProvenanceBadge.render(band.provenance, "members")}
               <div id="membersContainer">
                 ${band.members && band.members.length > 0 ? "" : '<p style="color: #999; margin: 0.5rem 0;">No members added yet</p>'}
               </div>
@@ -985,3 +1017,8 @@ class BandEditForm {
     }
   }
 }
+
+// Export for use in other modules
+if (typeof module !== "undefined" && module.exports) {
+  module.exports = BandEditForm;
+}
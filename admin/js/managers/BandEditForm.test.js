@@ -0,0 +1,85 @@
+// Unit tests for the admin BandEditForm
+// AdminBandEditForm is loaded globally via vitest.setup.js
+
+describe("AdminBandEditForm", () => {
+  let container;
+  let editForm;
+
+  const band = {
+    key: "gojira",
+    name: "Gojira",
+    country: "France",
+    description: "French progressive death metal band",
+    headlineImage: "https://example.com/gojira.jpg",
+    logo: "https://example.com/gojira-logo.png",
+    website: "https://gojira.com",
+    spotify: "",
+    genres: ["Progressive Metal"],
+    members: [],
+    reviewed: false,
+    provenance: {
+      country: { origin: "ai", model: "gpt-5", updatedAt: "2026-01-01T00:00:00Z" },
+    },
+  };
+
+  // renderForm writes the inputs collectFormData reads, with the values of band
+  const renderForm = (values) => {
+    const inputs = ["key", "name", "country", "headlineImage", "logo", "website", "spotify"]
+      .map((field) => `<input name="${field}" value="${values[field]}">`)
+      .join("");
+    container.innerHTML = `
+      <form id="bandForm">
+        ${inputs}
+        <input name="aliases" value="${(values.aliases || []).join(", ")}">
+        <textarea name="description">${values.description}</textarea>
+        <input type="checkbox" name="reviewed" ${values.reviewed ? "checked" : ""}>
+      </form>
+    `;
+  };
+
+  beforeEach(() => {
+    container = document.createElement("div");
+    container.id = "test-band-form";
+    document.body.appendChild(container);
+    editForm = new AdminBandEditForm("test-band-form");
+  });
+
+  afterEach(() => {
+    container.remove();
+  });
+
+  it("collects the edited fields", () => {
+    editForm.loadBand(band);
+    renderForm({ ...band, name: "Gojira (FR)", reviewed: true });
+
+    const collected = editForm.collectFormData();
+
+    expect(collected.name).toBe("Gojira (FR)");
+    expect(collected.reviewed).toBe(true);
+    expect(collected.genres).toEqual(["Progressive Metal"]);
+  });
+
+  it("keeps the provenance of the loaded band", () => {
+    editForm.loadBand(band);
+    renderForm(band);
+
+    const collected = editForm.collectFormData();
+
+    expect(collected.provenance).toEqual(band.provenance);
+  });
+
+  it("splits the aliases on commas", () => {
+    editForm.loadBand(band);
+    renderForm({ ...band, aliases: ["Godzilla", " ", "Gojira FR"] });
+
+    const collected = editForm.collectFormData();
+
+    expect(collected.aliases).toEqual(["Godzilla", "Gojira FR"]);
+  });
+
+  it("returns null without a form", () => {
+    editForm.loadBand(band);
+
+    expect(editForm.collectFormData()).toBeNull();
+  });
+});
@@ -139,6 +139,7 @@ class FestivalEditForm {
         .map(
           (band) => `
         <span class="selected-tag">
+          ${this.isPossiblyCancelled(band) ? '<span class="possibly-cancelled" title="Missing from the latest official lineup">⚠️</span>' : ""}
           ${this.escapeHtml(band)}
           <button type="button" class="remove-tag" data-band="${this.escapeHtml(band)}" data-tier="${tier}">×</button>
         </span>
@@ -157,6 +158,11 @@ class FestivalEditForm {
     }
   }
 
+  // Bands missing from the latest lineup fetched by the festival updater
+  isPossiblyCancelled(bandName) {
+    return this.currentFestival?.bands?.some((bandRef) => bandRef.name === bandName && bandRef.possiblyCancelled) || false;
+  }
+
   unselectBand(band, tier) {
     const checkbox = this.container.querySelector(`#bandsTier${tier}Options input[value="${band}"]`);
     if (checkbox) {
@@ -576,6 +582,7 @@ class FestivalEditForm {
 
           <div class="form-group">
             <label for="festivalName">Festival Name*</label>
+            ${ProvenanceBadge.render(festival.provenance, "name")}
             <input
               type="text"
               id="festivalName"
@@ -590,6 +597,7 @@ class FestivalEditForm {
           <div class="form-row">
             <div class="form-group">
               <label for="festivalStartDate">Start Date*</label>
+              ${ProvenanceBadge.render(festival.provenance, "dates")}
               <input
                 type="date"
                 id="festivalStartDate"
@@ -615,6 +623,7 @@ class FestivalEditForm {
 
           <div class="form-group">
             <label for="festivalLocation">Location*</label>
+            ${ProvenanceBadge.render(festival.provenance, "location")}
             <div class="url-field-container">
               <input
                 type="text"
@@ -636,6 +645,7 @@ class FestivalEditForm {
           <div class="form-row">
             <div class="form-group">
               <label for="festivalLatitude">Latitude*</label>
+              ${ProvenanceBadge.render(festival.provenance, "coordinates")}
               <input
                 type="number"
                 id="festivalLatitude"
@@ -665,6 +675,7 @@ class FestivalEditForm {
 
           <div class="form-group">
             <label for="festivalPoster">Poster URL*</label>
+            ${ProvenanceBadge.render(festival.provenance, "poster")}
             <div class="image-preview" id="posterPreview">
               ${this.renderImagePreview(festival.poster)}
             </div>
@@ -689,6 +700,7 @@ class FestivalEditForm {
 
           <div class="form-group">
             <label for="festivalWebsite">Website*</label>
+            ${ProvenanceBadge.render(festival.provenance, "website")}
             <div class="url-field-container">
               <input
                 type="url"
@@ -709,6 +721,7 @@ class FestivalEditForm {
 
           <div class="form-group">
             <label for="festivalTicketPrice">Ticket Price (€)*</label>
+            ${ProvenanceBadge.render(festival.provenance, "ticketPrice")}
             <input
               type="number"
               id="festivalTicketPrice"
@@ -724,6 +737,7 @@ class FestivalEditForm {
 
           <div class="form-group">
             <label>Tier 3 Bands (Headliners)</label>
+            ${ProvenanceBadge.render(festival.provenance, "bands")}
             <div class="tier-description">Most important bands - displayed in gold</div>
             <div class="multiselect-container tier-3-container">
               <div class="multiselect-selected" id="selectedBandsTier3">
@@ -0,0 +1,63 @@
+// Provenance Badge - Shows where the value of a band or festival field came from
+
+class ProvenanceBadge {
+  /**
+   * Render the badge for a single field
+   * @param {Object} provenance - The provenance map of the band or festival
+   * @param {string} field - The field JSON name
+   * @returns {string} HTML for the badge, empty when the field has no provenance
+   */
+  static render(provenance, field) {
+    const entry = provenance ? provenance[field] : null;
+    if (!entry) {
+      return "";
+    }
+
+    const isAI = entry.origin === "ai";
+    const details = [];
+    if (isAI && entry.model) {
+      details.push(entry.model);
+    }
+    if (isAI && entry.promptVersion) {
+      details.push(entry.promptVersion);
+    }
+    if (entry.updatedAt) {
+      details.push(new Date(entry.updatedAt).toISOString().slice(0, 10));
+    }
+
+    const sources = (entry.sources || [])
+      .filter((source) => ProvenanceBadge.isHttpUrl(source.url))
+      .map(
+        (source) =>
+          `<a href="${ProvenanceBadge.escapeHtml(source.url)}" target="_blank" rel="noopener noreferrer" title="${ProvenanceBadge.escapeHtml(source.title || source.url)}">source</a>`,
+      )
+      .join(" ");
+
+    return `
+      <span class="provenance-badge ${isAI ? "provenance-ai" : "provenance-manual"}" data-provenance="${ProvenanceBadge.escapeHtml(field)}">
+        ${isAI ? "🤖 AI" : "✍️ Manual"}${details.length > 0 ? ` · ${ProvenanceBadge.escapeHtml(details.join(" · "))}` : ""}
+        ${sources}
+      </span>
+    `;
+  }
+
+  static isHttpUrl(value) {
+    try {
+      const url = new URL(value);
+      return url.protocol === "http:" || url.protocol === "https:";
+    } catch {
+      return false;
+    }
+  }
+
+  static escapeHtml(text) {
+    const div = document.createElement("div");
+    div.textContent = text;
+    return div.innerHTML.replace(/"/g, "&quot;");
+  }
+}
+
+// Export for testing
+if (typeof module !== "undefined" && module.exports) {
+  module.exports = ProvenanceBadge;
+}
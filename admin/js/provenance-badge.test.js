@@ -0,0 +1,52 @@
+// Unit tests for ProvenanceBadge
+// ProvenanceBadge is loaded globally via vitest.setup.js
+
+describe("ProvenanceBadge", () => {
+  it("renders nothing when the field has no provenance", () => {
+    expect(ProvenanceBadge.render(undefined, "country")).toBe("");
+    expect(ProvenanceBadge.render({}, "country")).toBe("");
+  });
+
+  it("renders AI provenance with model, prompt version, date and sources", () => {
+    const html = ProvenanceBadge.render(
+      {
+        country: {
+          origin: "ai",
+          model: "gpt-5",
+          promptVersion: "band-v2",
+          updatedAt: "2026-03-04T10:00:00Z",
+          sources: [
+            { url: "https://example.com/band", title: "Band page" },
+            { url: "javascript:alert(1)", title: "Bad" },
+          ],
+        },
+      },
+      "country",
+    );
+
+    expect(html).toContain("provenance-ai");
+    expect(html).toContain("gpt-5 · band-v2 · 2026-03-04");
+    expect(html).toContain('href="https://example.com/band"');
+    expect(html).not.toContain("javascript:");
+  });
+
+  it("renders manual provenance without model details", () => {
+    const html = ProvenanceBadge.render(
+      { website: { origin: "manual", model: "ignored", updatedAt: "2026-05-06T00:00:00Z" } },
+      "website",
+    );
+
+    expect(html).toContain("provenance-manual");
+    expect(html).toContain("Manual · 2026-05-06");
+    expect(html).not.toContain("ignored");
+  });
+
+  it("escapes source titles", () => {
+    const html = ProvenanceBadge.render(
+      { logo: { origin: "ai", sources: [{ url: "https://example.com", title: '"><script>' }] } },
+      "logo",
+    );
+
+    expect(html).not.toContain("<script>");
+  });
+});
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/neovasili/metal-fests/internal/config"
+)
+
+func setupConfigPrint(fs *flag.FlagSet) func(ctx context.Context, env *env, args []string) error {
+	showEnv := fs.Bool("env", false, "List the environment variable overriding each setting")
+
+	return func(_ context.Context, env *env, args []string) error {
+		if len(args) > 0 {
+			return errUsage
+		}
+
+		if *showEnv {
+			vars := env.cfg.EnvVars()
+			if env.cfg.Output.Format == config.FormatJSON {
+				return writeJSON(env.stdout, vars)
+			}
+			for _, v := range vars {
+				_, _ = fmt.Fprintf(env.stdout, "%s=%s\n", v.Name, v.Value)
+			}
+			return nil
+		}
+
+		content, err := yaml.Marshal(env.cfg)
+		if err != nil {
+			return err
+		}
+		if env.cfg.Output.Format == config.FormatJSON {
+			// Going through YAML keeps the keys and durations as they are written in the file
+			var values map[string]any
+			if err := yaml.Unmarshal(content, &values); err != nil {
+				return err
+			}
+			return writeJSON(env.stdout, values)
+		}
+
+		source := "no configuration file, built-in defaults"
+		if env.cfg.File != "" {
+			source = env.cfg.File
+		}
+		_, _ = fmt.Fprintf(env.stdout, "# Effective configuration (%s, with %s* environment overrides and flags applied)\n", source, config.EnvPrefix)
+		_, err = env.stdout.Write(content)
+		return err
+	}
+}
@@ -0,0 +1,29 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/neovasili/metal-fests/internal/updater/discovery"
+)
+
+func setupDiscoverFestival(fs *flag.FlagSet) func(ctx context.Context, env *env, args []string) error {
+	opts := discovery.Options{}
+	opts.RegisterFlags(fs)
+
+	return func(ctx context.Context, env *env, args []string) error {
+		if len(args) > 0 {
+			return errUsage
+		}
+		files := env.cfg.Updaters.Discovery
+		set := visited(fs)
+		setDefault(set, "prompt", &opts.Prompt, files.Prompt)
+		setDefault(set, "summary", &opts.Summary, files.Summary)
+		setDefault(set, "report", &opts.Report, files.Report)
+		setDefault(set, "model", &opts.Model, env.cfg.AI.PrimaryModel)
+		setDefault(set, "fallback-model", &opts.FallbackModel, env.cfg.AI.FallbackModel)
+		setDefault(set, "request-timeout", &opts.RequestTimeout, env.cfg.AI.RequestTimeout)
+		err := discovery.Run(ctx, opts)
+		return printReport(env, opts.Report, err)
+	}
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/neovasili/metal-fests/internal/config"
+	"github.com/neovasili/metal-fests/internal/data"
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+func setupExport(fs *flag.FlagSet) func(ctx context.Context, env *env, args []string) error {
+	only := fs.String("only", "", "Only export \"festivals\" or \"bands\"")
+
+	return func(ctx context.Context, env *env, args []string) error {
+		if len(args) > 1 {
+			return errUsage
+		}
+
+		db, err := data.GetDatabase(ctx)
+		if err != nil {
+			return err
+		}
+		switch *only {
+		case "":
+		case "festivals":
+			db.Bands = []model.Band{}
+		case "bands":
+			db.Festivals = []model.Festival{}
+		default:
+			return fmt.Errorf("--only must be \"festivals\" or \"bands\", got %q", *only)
+		}
+
+		if len(args) == 0 || args[0] == "-" {
+			return writeJSON(env.stdout, db)
+		}
+		content, err := json.MarshalIndent(db, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(args[0], append(content, '\n'), 0600); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(env.progress(), "📦 Exported %d festivals and %d bands to %s\n", len(db.Festivals), len(db.Bands), args[0])
+		return nil
+	}
+}
+
+func setupImport(fs *flag.FlagSet) func(ctx context.Context, env *env, args []string) error {
+	replace := fs.Bool("replace", false, "Replace the whole database instead of merging into it")
+	dryRun := fs.Bool("dry-run", false, "Report what would change without writing the database")
+
+	return func(ctx context.Context, env *env, args []string) error {
+		if len(args) != 1 {
+			return errUsage
+		}
+
+		var content []byte
+		var err error
+		if args[0] == "-" {
+			content, err = io.ReadAll(os.Stdin)
+		} else {
+			// #nosec G304 -- the import file is given by the user
+			content, err = os.ReadFile(args[0])
+		}
+		if err != nil {
+			return err
+		}
+		var incoming model.Database
+		if err := json.Unmarshal(content, &incoming); err != nil {
+			return fmt.Errorf("%s is not a database export: %w", args[0], err)
+		}
+
+		result, problems, err := data.ImportDatabase(ctx, &incoming, *replace, *dryRun)
+		if err != nil {
+			return err
+		}
+		if len(problems) > 0 {
+			for _, problem := range problems {
+				_, _ = fmt.Fprintf(env.stderr, "✗ %s\n", problem)
+			}
+			return fmt.Errorf("%d invalid record(s), nothing was imported", len(problems))
+		}
+
+		if env.cfg.Output.Format == config.FormatJSON {
+			return writeJSON(env.stdout, result)
+		}
+		verb := "Imported"
+		if *dryRun {
+			verb = "Would import"
+		}
+		_, _ = fmt.Fprintf(env.stdout, "📥 %s %d new and %d updated festivals, %d new and %d updated bands\n",
+			verb, result.AddedFestivals, result.UpdatedFestivals, result.AddedBands, result.UpdatedBands)
+		return nil
+	}
+}
@@ -0,0 +1,297 @@
+// Command metal-fests serves the timeline and maintains its database.
+//
+// Usage:
+//
+//	metal-fests [global flags] <command> [flags] [arguments]
+//
+// Run "metal-fests help" for the list of commands.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/config"
+	"github.com/neovasili/metal-fests/internal/data"
+	"github.com/neovasili/metal-fests/internal/logging"
+	"github.com/neovasili/metal-fests/internal/tracing"
+)
+
+// command is a subcommand of the CLI
+type command struct {
+	// name is what selects the command, e.g. "update bands"
+	name    string
+	usage   string
+	summary string
+	// setup defines the flags of the command and returns the function running it
+	setup func(fs *flag.FlagSet) func(ctx context.Context, env *env, args []string) error
+}
+
+var commands = []command{
+	{name: "serve", summary: "Serve the timeline, the admin and the API", setup: setupServe},
+	{name: "validate", summary: "Check the formatting of the database", setup: setupValidate},
+	{name: "update bands", summary: "Add and complete the bands of the festival lineups", setup: setupUpdateBands},
+	{name: "update festivals", summary: "Update the lineup, price and details of the festivals", setup: setupUpdateFestivals},
+	{name: "discover festival", summary: "Look up a new festival and queue it for review", setup: setupDiscoverFestival},
+	{name: "export", usage: "[file]", summary: "Write the database, or part of it, as JSON", setup: setupExport},
+	{name: "import", usage: "<file>", summary: "Merge festivals and bands from a JSON file into the database", setup: setupImport},
+	{name: "stats", summary: "Count the records in the database", setup: setupStats},
+	{name: "config print", summary: "Show the effective configuration", setup: setupConfigPrint},
+}
+
+// globalOptions are the flags every command accepts, before or after its name
+type globalOptions struct {
+	configPath string
+	dbPath     string
+	output     string
+	quiet      bool
+	verbose    bool
+}
+
+func (g *globalOptions) register(fs *flag.FlagSet) {
+	fs.StringVar(&g.configPath, "config", g.configPath, "Configuration file (defaults to "+config.DefaultFile+" when it exists)")
+	fs.StringVar(&g.dbPath, "db", g.dbPath, "Database file")
+	fs.StringVar(&g.output, "output", g.output, "Output format: text or json")
+	fs.BoolVar(&g.quiet, "quiet", g.quiet, "Only print errors and results")
+	fs.BoolVar(&g.verbose, "verbose", g.verbose, "Print what the command runs with")
+}
+
+// env is what a command runs with
+type env struct {
+	cfg    config.Config
+	stdout io.Writer
+	stderr io.Writer
+}
+
+// progress is where a command reports what it is doing. It stays out of
+// stdout when that carries JSON, and is silenced in quiet mode.
+func (e *env) progress() io.Writer {
+	switch {
+	case e.cfg.Output.Verbosity == config.VerbosityQuiet:
+		return io.Discard
+	case e.cfg.Output.Format == config.FormatJSON:
+		return e.stderr
+	default:
+		return e.stdout
+	}
+}
+
+// logLevel is the configured log level, raised to warnings in quiet mode so
+// the progress the updaters log stays out of the way
+func logLevel(cfg config.Config) string {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.Log.Level)); err != nil || cfg.Output.Verbosity != config.VerbosityQuiet {
+		return cfg.Log.Level
+	}
+	return max(level, slog.LevelWarn).String()
+}
+
+// tracingFlushTimeout bounds how long the spans not exported yet get to reach
+// the collector once the command is done
+const tracingFlushTimeout = 5 * time.Second
+
+// errUsage reports a command line that cannot be run; the usage is printed
+var errUsage = errors.New("invalid usage")
+
+func main() {
+	os.Exit(run(context.Background(), os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line and returns the exit code
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	defaults := config.Default()
+	globals := globalOptions{dbPath: defaults.Database.Path, output: defaults.Output.Format}
+	root := flag.NewFlagSet("metal-fests", flag.ContinueOnError)
+	root.SetOutput(stderr)
+	globals.register(root)
+	root.Usage = func() { printUsage(stderr, root) }
+	if err := root.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	setFlags := visited(root)
+
+	args = root.Args()
+	if len(args) == 0 {
+		printUsage(stderr, root)
+		return 2
+	}
+	if args[0] == "help" {
+		printUsage(stdout, root)
+		return 0
+	}
+
+	cmd, args, ok := findCommand(args)
+	if !ok {
+		_, _ = fmt.Fprintf(stderr, "Error: unknown command %q\n\n", strings.Join(args, " "))
+		printUsage(stderr, root)
+		return 2
+	}
+
+	fs := flag.NewFlagSet("metal-fests "+cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	execute := cmd.setup(fs)
+	globals.register(fs)
+	fs.Usage = func() {
+		_, _ = fmt.Fprintf(fs.Output(), "Usage: metal-fests %s [flags] %s\n\n%s\n\nFlags:\n", cmd.name, cmd.usage, cmd.summary)
+		fs.PrintDefaults()
+	}
+	positional, err := parseInterspersed(fs, args)
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return 0
+		}
+		return 2
+	}
+	for name := range visited(fs) {
+		setFlags[name] = true
+	}
+
+	cfg, err := loadConfig(globals, setFlags)
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	data.SetDBFilePath(cfg.Database.Path)
+	if err := logging.Setup(stderr, cfg.Log.Format, logLevel(cfg)); err != nil {
+		_, _ = fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+
+	// Spans are printed to stderr so they never mix with the JSON output
+	shutdownTracing, err := tracing.Setup(ctx, cfg.Tracing.Options(stderr))
+	if err != nil {
+		_, _ = fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	defer func() {
+		flushCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), tracingFlushTimeout)
+		defer cancel()
+		if err := shutdownTracing(flushCtx); err != nil {
+			slog.Warn("failed to export traces", "error", err)
+		}
+	}()
+
+	e := &env{cfg: cfg, stdout: stdout, stderr: stderr}
+	if cfg.Output.Verbosity == config.VerbosityVerbose {
+		_, _ = fmt.Fprintf(stderr, "Running %q with database %s, %s output\n", cmd.name, cfg.Database.Path, cfg.Output.Format)
+	}
+
+	if err := execute(ctx, e, positional); err != nil {
+		if errors.Is(err, errUsage) {
+			fs.Usage()
+			return 2
+		}
+		var exit exitError
+		if errors.As(err, &exit) {
+			return exit.code
+		}
+		_, _ = fmt.Fprintf(stderr, "Error: %v\n", err)
+		return 1
+	}
+	return 0
+}
+
+// exitError ends a command with a code without printing anything more,
+// the command has already reported why
+type exitError struct {
+	code int
+}
+
+func (e exitError) Error() string {
+	return fmt.Sprintf("exit status %d", e.code)
+}
+
+// findCommand matches the longest command name at the start of args
+func findCommand(args []string) (command, []string, bool) {
+	var found command
+	rest := args
+	for _, cmd := range commands {
+		words := strings.Fields(cmd.name)
+		if len(args) < len(words) || strings.Join(args[:len(words)], " ") != cmd.name {
+			continue
+		}
+		if len(words) > len(strings.Fields(found.name)) {
+			found, rest = cmd, args[len(words):]
+		}
+	}
+	return found, rest, found.name != ""
+}
+
+// loadConfig reads the configuration file and applies the global flags given
+// on the command line over it
+func loadConfig(globals globalOptions, setFlags map[string]bool) (config.Config, error) {
+	cfg, err := config.Load(globals.configPath)
+	if err != nil {
+		return cfg, err
+	}
+	if setFlags["db"] {
+		cfg.Database.Path = globals.dbPath
+	}
+	if setFlags["output"] {
+		cfg.Output.Format = globals.output
+	}
+	if globals.quiet && globals.verbose {
+		return cfg, errors.New("--quiet and --verbose cannot be combined")
+	}
+	if globals.quiet {
+		cfg.Output.Verbosity = config.VerbosityQuiet
+	}
+	if globals.verbose {
+		cfg.Output.Verbosity = config.VerbosityVerbose
+	}
+	return cfg, cfg.Validate()
+}
+
+// parseInterspersed parses the flags of a command wherever they appear among
+// its arguments, and returns the arguments. Everything after "--" is an argument.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var positional []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		rest := fs.Args()
+		if len(rest) == 0 {
+			return positional, nil
+		}
+		if len(args) > len(rest) && args[len(args)-len(rest)-1] == "--" {
+			return append(positional, rest...), nil
+		}
+		positional = append(positional, rest[0])
+		args = rest[1:]
+	}
+}
+
+// visited returns the names of the flags set on the command line
+func visited(fs *flag.FlagSet) map[string]bool {
+	names := make(map[string]bool)
+	fs.Visit(func(f *flag.Flag) { names[f.Name] = true })
+	return names
+}
+
+func printUsage(w io.Writer, root *flag.FlagSet) {
+	_, _ = fmt.Fprintln(w, "🤘 metal-fests: the Metal Festivals Timeline and its database")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "Usage: metal-fests [global flags] <command> [flags] [arguments]")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "Commands:")
+	for _, cmd := range commands {
+		_, _ = fmt.Fprintf(w, "  %-18s %s\n", cmd.name, cmd.summary)
+	}
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "Global flags:")
+	root.SetOutput(w)
+	root.PrintDefaults()
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, `Run "metal-fests <command> -h" for the flags of a command.`)
+}
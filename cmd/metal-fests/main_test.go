@@ -0,0 +1,307 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neovasili/metal-fests/internal/config"
+	"github.com/neovasili/metal-fests/internal/data"
+	"github.com/neovasili/metal-fests/internal/model"
+	"github.com/neovasili/metal-fests/internal/updater/bands"
+)
+
+// writeTestDatabase writes db to a temporary database file and returns its path
+func writeTestDatabase(t *testing.T, db model.Database) string {
+	t.Helper()
+	originalDBFile := data.SetDBFilePathForTesting(data.DBFilePath())
+	t.Cleanup(func() { data.SetDBFilePathForTesting(originalDBFile) })
+
+	path := filepath.Join(t.TempDir(), "db.json")
+	content, err := json.MarshalIndent(db, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to marshal database: %v", err)
+	}
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatalf("failed to write database: %v", err)
+	}
+	return path
+}
+
+func runCommand(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+var testDatabase = model.Database{
+	Festivals: []model.Festival{{Key: "hellfest", Name: "Hellfest", TicketPrice: 329}},
+	Bands: []model.Band{
+		{Key: "gojira", Name: "Gojira", Reviewed: true},
+		{Key: "sepultura", Name: "Sepultura", Genres: []string{"thrash metal"}},
+	},
+}
+
+func TestRun_Usage(t *testing.T) {
+	tests := []struct {
+		name     string
+		args     []string
+		code     int
+		contains string
+	}{
+		{name: "No command", args: nil, code: 2, contains: "Commands:"},
+		{name: "Help", args: []string{"help"}, code: 0, contains: "update festivals"},
+		{name: "Unknown command", args: []string{"deploy"}, code: 2, contains: `unknown command "deploy"`},
+		{name: "Incomplete command", args: []string{"update"}, code: 2, contains: `unknown command "update"`},
+		{name: "Unexpected argument", args: []string{"stats", "extra"}, code: 2, contains: "Usage: metal-fests stats"},
+		{name: "Conflicting verbosity", args: []string{"--quiet", "stats", "--verbose"}, code: 1, contains: "cannot be combined"},
+		{name: "Invalid output format", args: []string{"stats", "--output", "xml"}, code: 1, contains: "output.format"},
+		{name: "Discovery without festival", args: []string{"discover", "festival"}, code: 1, contains: "--festival or --website is required"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeTestDatabase(t, testDatabase)
+			code, stdout, stderr := runCommand(tt.args...)
+			if code != tt.code {
+				t.Errorf("run(%v) = %d, want %d", tt.args, code, tt.code)
+			}
+			if !strings.Contains(stdout+stderr, tt.contains) {
+				t.Errorf("run(%v) output is missing %q:\n%s%s", tt.args, tt.contains, stdout, stderr)
+			}
+		})
+	}
+}
+
+func TestRun_GlobalFlags(t *testing.T) {
+	path := writeTestDatabase(t, testDatabase)
+
+	// Global flags are accepted before and after the command
+	for _, args := range [][]string{
+		{"--db", path, "--output", "json", "stats"},
+		{"stats", "--db", path, "--output", "json"},
+	} {
+		code, stdout, stderr := runCommand(args...)
+		if code != 0 {
+			t.Fatalf("run(%v) = %d: %s", args, code, stderr)
+		}
+		var stats data.Stats
+		if err := json.Unmarshal([]byte(stdout), &stats); err != nil {
+			t.Fatalf("run(%v) did not print JSON: %v\n%s", args, err, stdout)
+		}
+		if stats.Festivals != 1 || stats.Bands != 2 || stats.ReviewedBands != 1 {
+			t.Errorf("run(%v) stats = %+v", args, stats)
+		}
+	}
+}
+
+func TestRun_ConfigFile(t *testing.T) {
+	path := writeTestDatabase(t, testDatabase)
+	configPath := filepath.Join(t.TempDir(), "metal-fests.yaml")
+	content := "database:\n  path: " + path + "\noutput:\n  format: json\n"
+	if err := os.WriteFile(configPath, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+
+	code, stdout, stderr := runCommand("--config", configPath, "stats")
+	if code != 0 {
+		t.Fatalf("run() = %d: %s", code, stderr)
+	}
+	if !strings.Contains(stdout, `"festivals": 1`) {
+		t.Errorf("expected JSON stats of the configured database, got:\n%s", stdout)
+	}
+
+	// Flags win over the configuration file
+	code, stdout, _ = runCommand("--config", configPath, "--output", "text", "stats")
+	if code != 0 || !strings.Contains(stdout, "🎪 Festivals:") {
+		t.Errorf("expected text stats, got %d:\n%s", code, stdout)
+	}
+}
+
+func TestRun_Validate(t *testing.T) {
+	path := writeTestDatabase(t, testDatabase)
+
+	code, stdout, _ := runCommand("validate", "--db", path, "--output", "json")
+	if code != 1 {
+		t.Errorf("validate with errors = %d, want 1", code)
+	}
+	var result struct {
+		Errors int `json:"errors"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("validate did not print JSON: %v\n%s", err, stdout)
+	}
+	if result.Errors != 1 {
+		t.Errorf("validate errors = %d, want 1", result.Errors)
+	}
+
+	if code, _, stderr := runCommand("validate", "--db", path, "--fix", "--quiet"); code != 0 {
+		t.Fatalf("validate --fix = %d: %s", code, stderr)
+	}
+	if code, _, _ := runCommand("validate", "--db", path, "--quiet"); code != 0 {
+		t.Errorf("validate after fixing = %d, want 0", code)
+	}
+}
+
+func TestRun_ExportImport(t *testing.T) {
+	source := writeTestDatabase(t, testDatabase)
+	exported := filepath.Join(t.TempDir(), "festivals.json")
+
+	if code, _, stderr := runCommand("export", "--db", source, "--only", "festivals", exported); code != 0 {
+		t.Fatalf("export = %d: %s", code, stderr)
+	}
+
+	target := filepath.Join(t.TempDir(), "db.json")
+	if err := os.WriteFile(target, []byte(`{"festivals":[],"bands":[]}`), 0600); err != nil {
+		t.Fatalf("failed to write database: %v", err)
+	}
+
+	code, stdout, stderr := runCommand("import", exported, "--db", target, "--output", "json")
+	if code != 0 {
+		t.Fatalf("import = %d: %s", code, stderr)
+	}
+	var result data.ImportResult
+	if err := json.Unmarshal([]byte(stdout), &result); err != nil {
+		t.Fatalf("import did not print JSON: %v\n%s", err, stdout)
+	}
+	if result.AddedFestivals != 1 || result.AddedBands != 0 {
+		t.Errorf("import result = %+v, want one new festival", result)
+	}
+
+	db, err := data.GetDatabase(context.Background())
+	if err != nil {
+		t.Fatalf("GetDatabase failed: %v", err)
+	}
+	if len(db.Festivals) != 1 || db.Festivals[0].Key != "hellfest" {
+		t.Errorf("imported database = %+v", db)
+	}
+}
+
+func TestRun_ImportRejectsInvalidRecords(t *testing.T) {
+	path := writeTestDatabase(t, testDatabase)
+	file := filepath.Join(t.TempDir(), "invalid.json")
+	if err := os.WriteFile(file, []byte(`{"festivals":[{"key":"Bad Key","name":"Bad"}]}`), 0600); err != nil {
+		t.Fatalf("failed to write import file: %v", err)
+	}
+
+	code, _, stderr := runCommand("import", "--db", path, file)
+	if code != 1 || !strings.Contains(stderr, "festivals[0].key") {
+		t.Errorf("import of an invalid record = %d:\n%s", code, stderr)
+	}
+}
+
+func TestRun_ConfigPrint(t *testing.T) {
+	writeTestDatabase(t, testDatabase)
+	configPath := filepath.Join(t.TempDir(), "metal-fests.yaml")
+	if err := os.WriteFile(configPath, []byte("server:\n  port: 9000\n"), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	t.Setenv("METAL_FESTS_AI_PRIMARY_MODEL", "gpt-4.1-mini")
+
+	code, stdout, stderr := runCommand("config", "print", "--config", configPath)
+	if code != 0 {
+		t.Fatalf("config print = %d: %s", code, stderr)
+	}
+	for _, expected := range []string{configPath, "port: 9000", "primaryModel: gpt-4.1-mini", "readTimeout: 10s"} {
+		if !strings.Contains(stdout, expected) {
+			t.Errorf("config print is missing %q:\n%s", expected, stdout)
+		}
+	}
+
+	code, stdout, _ = runCommand("config", "print", "--config", configPath, "--db", "other.json", "--output", "json")
+	if code != 0 {
+		t.Fatalf("config print --output json = %d", code)
+	}
+	var printed struct {
+		Database struct {
+			Path string `json:"path"`
+		} `json:"database"`
+		Server struct {
+			Port        int    `json:"port"`
+			ReadTimeout string `json:"readTimeout"`
+		} `json:"server"`
+	}
+	if err := json.Unmarshal([]byte(stdout), &printed); err != nil {
+		t.Fatalf("config print did not print JSON: %v\n%s", err, stdout)
+	}
+	if printed.Database.Path != "other.json" || printed.Server.Port != 9000 || printed.Server.ReadTimeout != "10s" {
+		t.Errorf("config print = %+v", printed)
+	}
+
+	code, stdout, _ = runCommand("config", "print", "--env")
+	if code != 0 || !strings.Contains(stdout, "METAL_FESTS_AI_PRIMARY_MODEL=gpt-4.1-mini\n") {
+		t.Errorf("config print --env = %d:\n%s", code, stdout)
+	}
+}
+
+func TestRun_DiscoverFestival(t *testing.T) {
+	path := writeTestDatabase(t, testDatabase)
+	dir := filepath.Dir(path)
+	response := filepath.Join(dir, "response.json")
+	content := `{"name":"Test Fest","dates":{"start":"2026-07-02","end":"2026-07-04"},"location":"Nantes, France","coordinates":{"lat":47.2184,"lng":-1.5536},"website":"https://testfest.com","poster":"","ticketPrice":120,"bands":[{"name":"Gojira","size":3}]}`
+	if err := os.WriteFile(response, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write response: %v", err)
+	}
+
+	code, stdout, stderr := runCommand("--db", path, "--quiet", "discover", "festival",
+		"--festival", "Test Fest", "--openai-response", response, "--check-urls=false",
+		"--prompt", "../../scripts/festival_discovery_prompt.md",
+		"--report", filepath.Join(dir, "report.json"), "--summary", filepath.Join(dir, "summary.md"))
+	if code != 0 {
+		t.Fatalf("run() = %d: %s", code, stderr)
+	}
+	if stdout != "" {
+		t.Errorf("quiet mode should print nothing, got:\n%s", stdout)
+	}
+	if strings.Contains(stderr, "festival sent to the review queue") {
+		t.Errorf("quiet mode should not log the progress, got:\n%s", stderr)
+	}
+
+	// The festival is queued next to the database given with --db
+	originalDBFile := data.SetDBFilePathForTesting(path)
+	defer data.SetDBFilePathForTesting(originalDBFile)
+	reviews, err := data.GetPendingReviews(context.Background())
+	if err != nil {
+		t.Fatalf("failed to read the review queue: %v", err)
+	}
+	if len(reviews) != 1 || reviews[0].Key != "test-fest" {
+		t.Errorf("expected test-fest in the review queue, got %+v", reviews)
+	}
+	for _, file := range []string{"report.json", "summary.md"} {
+		if _, err := os.Stat(filepath.Join(dir, file)); err != nil {
+			t.Errorf("expected %s to be written: %v", file, err)
+		}
+	}
+}
+
+func TestApplyUpdaterConfig(t *testing.T) {
+	fs := flag.NewFlagSet("update bands", flag.ContinueOnError)
+	opts := bands.Options{}
+	opts.RegisterFlags(fs)
+	if err := fs.Parse([]string{"--summary", "custom.md", "--rpm", "10"}); err != nil {
+		t.Fatalf("failed to parse flags: %v", err)
+	}
+
+	cfg := config.Default()
+	cfg.AI.PrimaryModel = "gpt-4.1-mini"
+	cfg.AI.RequestsPerMinute = 30
+	cfg.Updaters.Bands.Prompt = "prompts/bands.md"
+	cfg.Updaters.Bands.Summary = "configured.md"
+	applyUpdaterConfig(fs, cfg, cfg.Updaters.Bands, updaterFlags{
+		prompt: &opts.Prompt, summary: &opts.Summary, report: &opts.Report, checkpoint: &opts.Checkpoint,
+		model: &opts.Model, fallbackModel: &opts.FallbackModel,
+		requestTimeout: &opts.RequestTimeout, requestsPerMinute: &opts.RequestsPerMinute,
+	})
+
+	if opts.Prompt != "prompts/bands.md" || opts.Model != "gpt-4.1-mini" {
+		t.Errorf("options without flags should come from the configuration, got %+v", opts)
+	}
+	if opts.Summary != "custom.md" || opts.RequestsPerMinute != 10 {
+		t.Errorf("flags should win over the configuration, got %+v", opts)
+	}
+}
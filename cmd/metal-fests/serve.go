@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/neovasili/metal-fests/internal/server"
+)
+
+func setupServe(fs *flag.FlagSet) func(ctx context.Context, env *env, args []string) error {
+	opts := server.Options{}
+	port := fs.Int("port", 0, "Port to listen on (defaults to server.port of the configuration)")
+	fs.BoolVar(&opts.Build, "build", false, "Serve from the build folder (production mode)")
+
+	return func(ctx context.Context, env *env, args []string) error {
+		if len(args) > 0 {
+			return errUsage
+		}
+		opts.Config = env.cfg.Server
+		if *port != 0 {
+			opts.Config.Port = *port
+		}
+		opts.Out = env.progress()
+		authenticator, err := env.cfg.Auth.Authenticator()
+		if err != nil {
+			return err
+		}
+		opts.Authenticator = authenticator
+		return server.Run(ctx, opts)
+	}
+}
@@ -0,0 +1,34 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+
+	"github.com/neovasili/metal-fests/internal/config"
+	"github.com/neovasili/metal-fests/internal/data"
+)
+
+func setupStats(_ *flag.FlagSet) func(ctx context.Context, env *env, args []string) error {
+	return func(ctx context.Context, env *env, args []string) error {
+		if len(args) > 0 {
+			return errUsage
+		}
+
+		stats, err := data.GetStats(ctx)
+		if err != nil {
+			return err
+		}
+		if env.cfg.Output.Format == config.FormatJSON {
+			return writeJSON(env.stdout, stats)
+		}
+
+		_, _ = fmt.Fprintf(env.stdout, "🎪 Festivals:       %d (%d without ticket price, %d without poster)\n",
+			stats.Festivals, stats.FestivalsWithoutPrice, stats.FestivalsWithoutPoster)
+		_, _ = fmt.Fprintf(env.stdout, "🎸 Bands:           %d (%d reviewed, %d not reviewed, %d incomplete)\n",
+			stats.Bands, stats.ReviewedBands, stats.UnreviewedBands, stats.IncompleteBands)
+		_, _ = fmt.Fprintf(env.stdout, "⏸️  Pending reviews: %d\n", stats.PendingReviews)
+		_, _ = fmt.Fprintf(env.stdout, "🕒 Last modified:   %s\n", stats.LastModified.Format("2006-01-02 15:04:05 UTC"))
+		return nil
+	}
+}
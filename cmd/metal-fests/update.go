@@ -0,0 +1,99 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"io"
+	"os"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/config"
+	"github.com/neovasili/metal-fests/internal/updater/bands"
+	"github.com/neovasili/metal-fests/internal/updater/festivals"
+)
+
+func setupUpdateBands(fs *flag.FlagSet) func(ctx context.Context, env *env, args []string) error {
+	opts := bands.Options{}
+	opts.RegisterFlags(fs)
+
+	return func(ctx context.Context, env *env, args []string) error {
+		if len(args) > 0 {
+			return errUsage
+		}
+		applyUpdaterConfig(fs, env.cfg, env.cfg.Updaters.Bands, updaterFlags{
+			prompt: &opts.Prompt, summary: &opts.Summary, report: &opts.Report, checkpoint: &opts.Checkpoint,
+			model: &opts.Model, fallbackModel: &opts.FallbackModel,
+			requestTimeout: &opts.RequestTimeout, requestsPerMinute: &opts.RequestsPerMinute,
+		})
+		err := bands.Run(ctx, opts)
+		return printReport(env, opts.Report, err)
+	}
+}
+
+func setupUpdateFestivals(fs *flag.FlagSet) func(ctx context.Context, env *env, args []string) error {
+	opts := festivals.Options{}
+	opts.RegisterFlags(fs)
+
+	return func(ctx context.Context, env *env, args []string) error {
+		if len(args) > 0 {
+			return errUsage
+		}
+		applyUpdaterConfig(fs, env.cfg, env.cfg.Updaters.Festivals, updaterFlags{
+			prompt: &opts.Prompt, summary: &opts.Summary, report: &opts.Report, checkpoint: &opts.Checkpoint,
+			model: &opts.Model, fallbackModel: &opts.FallbackModel,
+			requestTimeout: &opts.RequestTimeout, requestsPerMinute: &opts.RequestsPerMinute,
+		})
+		err := festivals.Run(ctx, opts)
+		return printReport(env, opts.Report, err)
+	}
+}
+
+// updaterFlags points at the options of an updater that the configuration sets
+type updaterFlags struct {
+	prompt, summary, report, checkpoint *string
+	model, fallbackModel                *string
+	requestTimeout                      *time.Duration
+	requestsPerMinute                   *int
+}
+
+// applyUpdaterConfig takes the value of every updater option whose flag was
+// not given from the configuration
+func applyUpdaterConfig(fs *flag.FlagSet, cfg config.Config, files config.UpdaterFiles, opts updaterFlags) {
+	set := visited(fs)
+	setDefault(set, "prompt", opts.prompt, files.Prompt)
+	setDefault(set, "summary", opts.summary, files.Summary)
+	setDefault(set, "report", opts.report, files.Report)
+	setDefault(set, "checkpoint", opts.checkpoint, files.Checkpoint)
+	setDefault(set, "model", opts.model, cfg.AI.PrimaryModel)
+	setDefault(set, "fallback-model", opts.fallbackModel, cfg.AI.FallbackModel)
+	setDefault(set, "request-timeout", opts.requestTimeout, cfg.AI.RequestTimeout)
+	setDefault(set, "rpm", opts.requestsPerMinute, cfg.AI.RequestsPerMinute)
+}
+
+// setDefault stores value in dst unless the flag was given
+func setDefault[T any](set map[string]bool, flagName string, dst *T, value T) {
+	if !set[flagName] {
+		*dst = value
+	}
+}
+
+// printReport copies the JSON report of an update run to stdout when JSON
+// output is asked for. Interrupted runs still leave a report behind.
+func printReport(env *env, path string, runErr error) error {
+	if env.cfg.Output.Format != config.FormatJSON {
+		return runErr
+	}
+	// #nosec G304 -- the report path is given by the user
+	report, err := os.Open(path)
+	if err != nil {
+		if runErr != nil {
+			return runErr
+		}
+		return err
+	}
+	defer func() { _ = report.Close() }()
+	if _, err := io.Copy(env.stdout, report); err != nil && runErr == nil {
+		return err
+	}
+	return runErr
+}
@@ -0,0 +1,57 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"io"
+	"os"
+
+	"github.com/neovasili/metal-fests/internal/config"
+	"github.com/neovasili/metal-fests/internal/validate"
+)
+
+func setupValidate(fs *flag.FlagSet) func(ctx context.Context, env *env, args []string) error {
+	opts := validate.Options{}
+	fs.BoolVar(&opts.Fix, "fix", false, "Automatically fix formatting issues")
+	fs.BoolVar(&opts.HideWarnings, "hide-warnings", false, "Hide warning details (e.g., duplicate band list)")
+
+	return func(_ context.Context, env *env, args []string) error {
+		if len(args) > 0 {
+			return errUsage
+		}
+		opts.Out = env.progress()
+		opts.Color = env.cfg.Output.Format == config.FormatText && isTerminal(env.stdout)
+
+		result, err := validate.Run(env.cfg.Database.Path, opts)
+		if env.cfg.Output.Format == config.FormatJSON {
+			if err := writeJSON(env.stdout, result); err != nil {
+				return err
+			}
+		}
+		if err != nil {
+			return err
+		}
+		if result.Errors > 0 && !opts.Fix {
+			return exitError{code: 1}
+		}
+		return nil
+	}
+}
+
+// writeJSON prints a value as indented JSON
+func writeJSON(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(value)
+}
+
+// isTerminal tells whether w is an interactive terminal, where colors help
+func isTerminal(w io.Writer) bool {
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,23 @@
+package api
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+
+	"github.com/neovasili/metal-fests/internal/data"
+)
+
+// Handle GET /api/admin/stats - Summarise the content of the database
+func handleAdminStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := data.GetStats(r.Context())
+	if err != nil {
+		dataError(w, r, "Failed to compute stats", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(stats); err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
@@ -0,0 +1,54 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/neovasili/metal-fests/internal/data"
+)
+
+func TestHandleAdminStats(t *testing.T) {
+	tempFile := filepath.Join(t.TempDir(), "db.json")
+	testData := `{"bands":[{"key":"gojira","name":"Gojira","reviewed":true},{"key":"slayer","name":"Slayer"}],"festivals":[{"key":"hellfest","name":"Hellfest","ticketPrice":329}]}`
+	if err := os.WriteFile(tempFile, []byte(testData), 0600); err != nil {
+		t.Fatalf("failed to create test db: %v", err)
+	}
+	oldPath := data.SetDBFilePathForTesting(tempFile)
+	defer data.SetDBFilePathForTesting(oldPath)
+
+	req := httptest.NewRequest("GET", "/api/admin/stats", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	w := httptest.NewRecorder()
+	newTestRouter(t).ServeHTTP(w, req)
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var stats data.Stats
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("failed to decode stats: %v", err)
+	}
+	if stats.Festivals != 1 || stats.FestivalsWithoutPoster != 1 || stats.Bands != 2 || stats.UnreviewedBands != 1 || stats.IncompleteBands != 2 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+	if stats.LastModified.IsZero() {
+		t.Error("expected the last modification time of the database")
+	}
+}
+
+func TestHandleAdminStats_MissingDatabase(t *testing.T) {
+	oldPath := data.SetDBFilePathForTesting(filepath.Join(t.TempDir(), "missing.json"))
+	defer data.SetDBFilePathForTesting(oldPath)
+
+	req := httptest.NewRequest("GET", "/api/admin/stats", nil)
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	w := httptest.NewRecorder()
+	newTestRouter(t).ServeHTTP(w, req)
+	if w.Code != http.StatusInternalServerError {
+		t.Errorf("expected status 500, got %d", w.Code)
+	}
+}
@@ -0,0 +1,40 @@
+package api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/neovasili/metal-fests/internal/auth"
+	"github.com/neovasili/metal-fests/internal/logging"
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+// requireRole lets only the callers with role, or a higher one, reach
+// handler. The caller is named in every record logged for the request.
+// Without authenticator the route is open.
+func requireRole(authenticator auth.Authenticator, role auth.Role, handler http.HandlerFunc) http.HandlerFunc {
+	if authenticator == nil {
+		return handler
+	}
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := auth.BearerToken(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metal-fests"`)
+			httpError(w, r, http.StatusUnauthorized, model.ErrorCodeUnauthorized, "Authentication required")
+			return
+		}
+		identity, err := authenticator.Authenticate(r.Context(), token)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="metal-fests", error="invalid_token"`)
+			replyError(w, r, http.StatusUnauthorized, model.ErrorResponse{Code: model.ErrorCodeUnauthorized, Message: "Invalid credentials"}, err)
+			return
+		}
+
+		r = r.WithContext(auth.WithIdentity(logging.WithActor(r.Context(), identity.String()), identity))
+		if !identity.Role.Allows(role) {
+			httpError(w, r, http.StatusForbidden, model.ErrorCodeForbidden, fmt.Sprintf("The %s role is required, %s has %s", role, identity.Subject, identity.Role))
+			return
+		}
+		handler(w, r)
+	}
+}
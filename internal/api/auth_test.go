@@ -0,0 +1,133 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/neovasili/metal-fests/internal/auth"
+	"github.com/neovasili/metal-fests/internal/data"
+	"github.com/neovasili/metal-fests/internal/logging"
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+func tokenEntry(name, role, token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return name + ":" + role + ":" + hex.EncodeToString(digest[:])
+}
+
+func TestRegister_Authorization(t *testing.T) {
+	tempFile := filepath.Join(t.TempDir(), "db.json")
+	if err := os.WriteFile(tempFile, []byte(`{"bands":[{"key":"gojira","name":"Gojira"}],"festivals":[]}`), 0600); err != nil {
+		t.Fatalf("failed to create test db: %v", err)
+	}
+	oldPath := data.SetDBFilePathForTesting(tempFile)
+	defer data.SetDBFilePathForTesting(oldPath)
+
+	issuer, err := auth.NewTestIssuer()
+	if err != nil {
+		t.Fatalf("NewTestIssuer() failed: %v", err)
+	}
+	defer issuer.Close()
+	tokens, err := auth.ParseTokens([]string{tokenEntry("ci", "editor", "ci-secret"), tokenEntry("dashboard", "viewer", "viewer-secret")})
+	if err != nil {
+		t.Fatalf("ParseTokens() failed: %v", err)
+	}
+	viewerJWT, err := issuer.Token("alice", "AdminViewer")
+	if err != nil {
+		t.Fatalf("Token() failed: %v", err)
+	}
+	editorJWT, err := issuer.Token("bob", "AdminEditor")
+	if err != nil {
+		t.Fatalf("Token() failed: %v", err)
+	}
+
+	mux := http.NewServeMux()
+	Register(mux, auth.Chain{tokens, auth.NewOIDC(issuer.Options())})
+
+	band := `{"key":"gojira","name":"Gojira"}`
+	tests := []struct {
+		name       string
+		method     string
+		path       string
+		body       string
+		token      string
+		wantStatus int
+	}{
+		{name: "Without token", method: "PUT", path: "/api/bands/gojira", body: band, wantStatus: http.StatusUnauthorized},
+		{name: "Unknown token", method: "PUT", path: "/api/bands/gojira", body: band, token: "guess", wantStatus: http.StatusUnauthorized},
+		{name: "Viewer token editing", method: "PUT", path: "/api/bands/gojira", body: band, token: "viewer-secret", wantStatus: http.StatusForbidden},
+		{name: "Editor token editing", method: "PUT", path: "/api/bands/gojira", body: band, token: "ci-secret", wantStatus: http.StatusOK},
+		{name: "Viewer JWT editing", method: "PUT", path: "/api/bands/gojira", body: band, token: viewerJWT, wantStatus: http.StatusForbidden},
+		{name: "Editor JWT editing", method: "PUT", path: "/api/bands/gojira", body: band, token: editorJWT, wantStatus: http.StatusOK},
+		{name: "Viewer JWT reading stats", method: "GET", path: "/api/admin/stats", token: viewerJWT, wantStatus: http.StatusOK},
+		{name: "Stats without token", method: "GET", path: "/api/admin/stats", wantStatus: http.StatusUnauthorized},
+		{name: "URL validation without token", method: "POST", path: "/api/validate-url", body: `{"url":""}`, wantStatus: http.StatusUnauthorized},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			if tt.token != "" {
+				req.Header.Set("Authorization", "Bearer "+tt.token)
+			}
+			w := httptest.NewRecorder()
+			mux.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d: %s", w.Code, tt.wantStatus, w.Body.String())
+			}
+			if w.Code == http.StatusUnauthorized && !strings.HasPrefix(w.Header().Get("WWW-Authenticate"), "Bearer") {
+				t.Errorf("WWW-Authenticate = %q, want a Bearer challenge", w.Header().Get("WWW-Authenticate"))
+			}
+			if w.Code == http.StatusUnauthorized || w.Code == http.StatusForbidden {
+				var response model.ErrorResponse
+				if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+					t.Fatalf("body is not an error envelope: %v", err)
+				}
+				if response.Code != model.ErrorCodeUnauthorized && response.Code != model.ErrorCodeForbidden {
+					t.Errorf("code = %q, want unauthorized or forbidden", response.Code)
+				}
+			}
+		})
+	}
+}
+
+func TestRequireRole_Identity(t *testing.T) {
+	tokens, err := auth.ParseTokens([]string{tokenEntry("ci", "admin", "ci-secret")})
+	if err != nil {
+		t.Fatalf("ParseTokens() failed: %v", err)
+	}
+
+	var identity auth.Identity
+	var actor string
+	handler := logging.Middleware(requireRole(tokens, auth.RoleEditor, func(_ http.ResponseWriter, r *http.Request) {
+		identity, _ = auth.FromContext(r.Context())
+		actor = logging.Actor(r.Context())
+	}))
+	req := httptest.NewRequest("PUT", "/api/bands/gojira", nil)
+	req.Header.Set("Authorization", "Bearer ci-secret")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	if identity.Subject != "ci" || identity.Role != auth.RoleAdmin {
+		t.Errorf("identity = %+v, want ci as admin", identity)
+	}
+	if actor != "token:ci" {
+		t.Errorf("actor = %q, want %q", actor, "token:ci")
+	}
+}
+
+func TestRequireRole_Open(t *testing.T) {
+	called := false
+	handler := requireRole(nil, auth.RoleAdmin, func(http.ResponseWriter, *http.Request) { called = true })
+	handler(httptest.NewRecorder(), httptest.NewRequest("PUT", "/api/bands/gojira", nil))
+	if !called {
+		t.Error("the handler was not called without authenticator")
+	}
+}
@@ -3,10 +3,10 @@ package api
 import (
 	"encoding/json"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/neovasili/metal-fests/internal/data"
 	"github.com/neovasili/metal-fests/internal/model"
@@ -17,33 +17,47 @@ func handleUpdateBand(w http.ResponseWriter, r *http.Request) {
 	// Extract band key from path
 	pathParts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/bands/"), "/")
 	if len(pathParts) == 0 || pathParts[0] == "" {
-		http.Error(w, "Band key is required", http.StatusBadRequest)
+		httpError(w, r, http.StatusBadRequest, model.ErrorCodeBadRequest, "Band key is required")
 		return
 	}
 	bandKey := pathParts[0]
 
 	// Read request body
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+	body, ok := readBody(w, r)
+	if !ok {
 		return
 	}
-	defer func() {
-		if err := r.Body.Close(); err != nil {
-			log.Printf("Failed to close request body: %v", err)
-		}
-	}()
 
 	// Parse updated band data
 	var updatedBand model.Band
 	if err := json.Unmarshal(body, &updatedBand); err != nil {
-		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		httpError(w, r, http.StatusBadRequest, model.ErrorCodeInvalidJSON, fmt.Sprintf("Invalid JSON: %v", err))
 		return
 	}
 
-	err = data.UpdateBandInDatabase(updatedBand)
+	// The key in the path names the band, the body may leave it out
+	if updatedBand.Key == "" {
+		updatedBand.Key = bandKey
+	} else if updatedBand.Key != bandKey {
+		dataError(w, r, "Failed to update band", data.NewValidationError("key", fmt.Sprintf("does not match %q in the path", bandKey)))
+		return
+	}
+
+	existingBand, err := data.GetBand(r.Context(), updatedBand.Key)
+	if err != nil {
+		dataError(w, r, "Failed to update band", err)
+		return
+	}
+
+	// Keep the stored provenance and mark the fields changed here as manual edits.
+	// The last check belongs to the band updater and is kept as well.
+	manual := model.Provenance{Origin: model.ProvenanceManual, UpdatedAt: time.Now().UTC()}
+	updatedBand.Provenance = existingBand.Provenance.Set(manual, model.ChangedFields(*existingBand, updatedBand)...)
+	updatedBand.LastChecked = existingBand.LastChecked
+
+	err = data.UpdateBandInDatabase(r.Context(), updatedBand)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to update band: %v", err), http.StatusInternalServerError)
+		dataError(w, r, "Failed to update band", err)
 		return
 	}
 
@@ -52,9 +66,10 @@ func handleUpdateBand(w http.ResponseWriter, r *http.Request) {
 	if err := json.NewEncoder(w).Encode(model.UpdateBandResponse{
 		Success: true,
 		Message: "Band updated",
+		Band:    updatedBand,
 	}); err != nil {
-		log.Printf("Failed to encode response: %v", err)
+		slog.ErrorContext(r.Context(), "failed to encode response", "error", err)
 	}
 
-	log.Printf("✅ Updated band: %s (%s)", updatedBand.Name, bandKey)
+	slog.InfoContext(r.Context(), "band updated", "key", bandKey, "name", updatedBand.Name)
 }
@@ -2,12 +2,15 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/neovasili/metal-fests/internal/data"
 	"github.com/neovasili/metal-fests/internal/model"
@@ -63,3 +66,112 @@ func TestHandleUpdateBand_Success(t *testing.T) {
 		t.Errorf("expected response to contain 'Band updated', got %s", string(respBody))
 	}
 }
+
+func TestHandleUpdateBand_RecordsManualProvenance(t *testing.T) {
+	tempFile := "test_db_bands_provenance.json"
+	testData := `{"bands":[{"key":"testkey","name":"Test Band","country":"Spain","description":"","logo":"","headlineImage":"","website":"","spotify":"","genres":[],"members":[],"reviewed":false,"lastChecked":"2026-02-01T00:00:00Z","provenance":{"country":{"origin":"ai","model":"gpt-5","updatedAt":"2026-01-01T00:00:00Z"}}}],"festivals":[]}`
+	if err := os.WriteFile(tempFile, []byte(testData), 0600); err != nil {
+		t.Fatalf("failed to create test db: %v", err)
+	}
+	oldPath := data.SetDBFilePathForTesting(tempFile)
+	defer func() {
+		data.SetDBFilePathForTesting(oldPath)
+		_ = os.Remove(tempFile)
+	}()
+
+	// The client provenance and last check are ignored, only the stored ones are kept
+	band := model.Band{
+		Key:        "testkey",
+		Name:       "Test Band",
+		Country:    "Spain",
+		Website:    "https://example.com",
+		Genres:     []string{},
+		Members:    []model.Member{},
+		Provenance: model.ProvenanceMap{"name": {Origin: model.ProvenanceAI}},
+	}
+	reqData, _ := json.Marshal(band)
+	req := httptest.NewRequest("PUT", "/api/bands/testkey", bytes.NewReader(reqData))
+	w := httptest.NewRecorder()
+	handleUpdateBand(w, req)
+	if w.Result().StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Result().StatusCode)
+	}
+
+	stored, err := data.GetBand(context.Background(), "testkey")
+	if err != nil {
+		t.Fatalf("GetBand failed: %v", err)
+	}
+	if len(stored.Provenance) != 2 {
+		t.Fatalf("expected provenance for country and website, got %+v", stored.Provenance)
+	}
+	if stored.Provenance["country"].Origin != model.ProvenanceAI {
+		t.Errorf("expected unchanged country to keep ai provenance, got %+v", stored.Provenance["country"])
+	}
+	if stored.Provenance["website"].Origin != model.ProvenanceManual {
+		t.Errorf("expected edited website to be manual, got %+v", stored.Provenance["website"])
+	}
+	if stored.LastChecked == nil || stored.LastChecked.Format(time.RFC3339) != "2026-02-01T00:00:00Z" {
+		t.Errorf("expected the stored last check to be kept, got %v", stored.LastChecked)
+	}
+}
+
+func TestHandleUpdateBand_Errors(t *testing.T) {
+	tempFile := filepath.Join(t.TempDir(), "db.json")
+	if err := os.WriteFile(tempFile, []byte(`{"bands":[{"key":"testkey","name":"Test Band"}],"festivals":[]}`), 0600); err != nil {
+		t.Fatalf("failed to create test db: %v", err)
+	}
+	oldPath := data.SetDBFilePathForTesting(tempFile)
+	defer data.SetDBFilePathForTesting(oldPath)
+
+	tests := []struct {
+		name       string
+		path       string
+		band       model.Band
+		wantStatus int
+		wantCode   string
+	}{
+		{name: "Missing band", path: "/api/bands/missing", band: model.Band{Key: "missing", Name: "Missing"}, wantStatus: http.StatusNotFound, wantCode: model.ErrorCodeNotFound},
+		{name: "Key not matching the path", path: "/api/bands/testkey", band: model.Band{Key: "other", Name: "Other"}, wantStatus: http.StatusUnprocessableEntity, wantCode: model.ErrorCodeValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqData, _ := json.Marshal(tt.band)
+			w := httptest.NewRecorder()
+			handleUpdateBand(w, httptest.NewRequest("PUT", tt.path, bytes.NewReader(reqData)))
+
+			var response model.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("body is not an error envelope: %v", err)
+			}
+			if w.Code != tt.wantStatus || response.Code != tt.wantCode {
+				t.Errorf("response = %d %q, want %d %q", w.Code, response.Code, tt.wantStatus, tt.wantCode)
+			}
+		})
+	}
+}
+
+func TestHandleUpdateBand_ReturnsStoredBand(t *testing.T) {
+	tempFile := filepath.Join(t.TempDir(), "db.json")
+	if err := os.WriteFile(tempFile, []byte(`{"bands":[{"key":"testkey","name":"Old Band","lastChecked":"2026-02-01T00:00:00Z"}],"festivals":[]}`), 0600); err != nil {
+		t.Fatalf("failed to create test db: %v", err)
+	}
+	oldPath := data.SetDBFilePathForTesting(tempFile)
+	defer data.SetDBFilePathForTesting(oldPath)
+
+	// The key can be left out of the body
+	reqData, _ := json.Marshal(model.Band{Name: "Test Band"})
+	w := httptest.NewRecorder()
+	handleUpdateBand(w, httptest.NewRequest("PUT", "/api/bands/testkey", bytes.NewReader(reqData)))
+	if w.Code != http.StatusOK {
+		t.Fatalf("expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var response model.UpdateBandResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if response.Band.Key != "testkey" || response.Band.Name != "Test Band" || response.Band.LastChecked == nil {
+		t.Errorf("expected the stored band in the response, got %+v", response.Band)
+	}
+}
@@ -0,0 +1,39 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+// readBody reads the body of r. When it can't, it replies to r and returns
+// false: with a 413 when the body is over the limit the server sets.
+func readBody(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	defer func() {
+		if err := r.Body.Close(); err != nil {
+			slog.WarnContext(r.Context(), "failed to close request body", "error", err)
+		}
+	}()
+
+	body, err := io.ReadAll(r.Body)
+	var tooLarge *http.MaxBytesError
+	switch {
+	case errors.As(err, &tooLarge):
+		replyError(w, r, http.StatusRequestEntityTooLarge, model.ErrorResponse{
+			Code:    model.ErrorCodeTooLarge,
+			Message: fmt.Sprintf("Request body is larger than %d bytes", tooLarge.Limit),
+		}, err)
+		return nil, false
+	case err != nil:
+		replyError(w, r, http.StatusBadRequest, model.ErrorResponse{
+			Code:    model.ErrorCodeBadRequest,
+			Message: "Failed to read request body",
+		}, err)
+		return nil, false
+	}
+	return body, true
+}
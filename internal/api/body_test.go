@@ -0,0 +1,78 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) { return 0, errors.New("connection reset") }
+
+func TestReadBody(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       func(w http.ResponseWriter) io.ReadCloser
+		wantOK     bool
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name: "Within the limit",
+			body: func(w http.ResponseWriter) io.ReadCloser {
+				return http.MaxBytesReader(w, io.NopCloser(strings.NewReader(`{"url":"x"}`)), 64)
+			},
+			wantOK: true,
+		},
+		{
+			name: "Over the limit",
+			body: func(w http.ResponseWriter) io.ReadCloser {
+				return http.MaxBytesReader(w, io.NopCloser(strings.NewReader(`{"url":"x"}`)), 4)
+			},
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   model.ErrorCodeTooLarge,
+		},
+		{
+			name:       "Failed read",
+			body:       func(http.ResponseWriter) io.ReadCloser { return io.NopCloser(failingReader{}) },
+			wantStatus: http.StatusBadRequest,
+			wantCode:   model.ErrorCodeBadRequest,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/api/validate-url", nil)
+			r.Body = tt.body(w)
+
+			body, ok := readBody(w, r)
+			if ok != tt.wantOK {
+				t.Fatalf("readBody() ok = %v, want %v", ok, tt.wantOK)
+			}
+			if ok {
+				if string(body) != `{"url":"x"}` {
+					t.Errorf("readBody() = %q", body)
+				}
+				return
+			}
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			var response model.ErrorResponse
+			if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode the response: %v", err)
+			}
+			if response.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", response.Code, tt.wantCode)
+			}
+		})
+	}
+}
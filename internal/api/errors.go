@@ -0,0 +1,61 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/neovasili/metal-fests/internal/data"
+	"github.com/neovasili/metal-fests/internal/logging"
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+// httpError replies to r with an error envelope
+func httpError(w http.ResponseWriter, r *http.Request, status int, code, message string) {
+	replyError(w, r, status, model.ErrorResponse{Code: code, Message: message}, nil)
+}
+
+// dataError replies to r after the database failed to do action, with the
+// status matching the data error. Unexpected errors are only detailed in the logs.
+func dataError(w http.ResponseWriter, r *http.Request, action string, err error) {
+	response := model.ErrorResponse{Message: fmt.Sprintf("%s: %v", action, err)}
+	var status int
+	var validation *data.ValidationError
+	switch {
+	case errors.As(err, &validation):
+		status, response.Code, response.Fields = http.StatusUnprocessableEntity, model.ErrorCodeValidation, validation.Fields
+	case errors.Is(err, data.ErrNotFound):
+		status, response.Code = http.StatusNotFound, model.ErrorCodeNotFound
+	case errors.Is(err, data.ErrConflict):
+		status, response.Code = http.StatusConflict, model.ErrorCodeConflict
+	case errors.Is(err, data.ErrClosed):
+		status, response.Code, response.Message = http.StatusServiceUnavailable, model.ErrorCodeUnavailable, action+": the server is shutting down"
+	default:
+		status, response.Code, response.Message = http.StatusInternalServerError, model.ErrorCodeInternal, action
+	}
+	replyError(w, r, status, response, err)
+}
+
+// replyError logs why r failed and writes response. The reply carries the
+// request ID, so a failed admin action can be found in the logs.
+func replyError(w http.ResponseWriter, r *http.Request, status int, response model.ErrorResponse, cause error) {
+	level := slog.LevelWarn
+	if status >= http.StatusInternalServerError {
+		level = slog.LevelError
+	}
+	var reason any = response.Message
+	if cause != nil {
+		reason = cause
+	}
+	slog.Log(r.Context(), level, "request failed", "status", status, "code", response.Code, "error", reason)
+
+	response.RequestID = logging.RequestID(r.Context())
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
@@ -0,0 +1,66 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/neovasili/metal-fests/internal/data"
+	"github.com/neovasili/metal-fests/internal/logging"
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+func TestDataError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   string
+		wantFields int
+	}{
+		{name: "Not found", err: fmt.Errorf("band %q: %w", "gojira", data.ErrNotFound), wantStatus: http.StatusNotFound, wantCode: model.ErrorCodeNotFound},
+		{name: "Conflict", err: fmt.Errorf("band %q: %w", "gojira", data.ErrConflict), wantStatus: http.StatusConflict, wantCode: model.ErrorCodeConflict},
+		{name: "Validation", err: data.NewValidationError("key", "is required"), wantStatus: http.StatusUnprocessableEntity, wantCode: model.ErrorCodeValidation, wantFields: 1},
+		{name: "Closed", err: data.ErrClosed, wantStatus: http.StatusServiceUnavailable, wantCode: model.ErrorCodeUnavailable},
+		{name: "Unexpected", err: errors.New("disk on fire"), wantStatus: http.StatusInternalServerError, wantCode: model.ErrorCodeInternal},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/api/bands/gojira", nil)
+			req = req.WithContext(logging.WithRequestID(req.Context(), "req-7"))
+			w := httptest.NewRecorder()
+			dataError(w, req, "Failed to update band", tt.err)
+
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+			if got := w.Header().Get("Content-Type"); got != "application/json" {
+				t.Errorf("Content-Type = %q, want application/json", got)
+			}
+			var response model.ErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("body is not an error envelope: %v", err)
+			}
+			if response.Code != tt.wantCode {
+				t.Errorf("code = %q, want %q", response.Code, tt.wantCode)
+			}
+			if len(response.Fields) != tt.wantFields {
+				t.Errorf("fields = %+v, want %d", response.Fields, tt.wantFields)
+			}
+			if response.RequestID != "req-7" {
+				t.Errorf("requestId = %q, want %q", response.RequestID, "req-7")
+			}
+			if !strings.HasPrefix(response.Message, "Failed to update band") {
+				t.Errorf("message = %q, want the action", response.Message)
+			}
+			if strings.Contains(response.Message, "disk on fire") {
+				t.Errorf("message = %q, want the unexpected error kept out of the reply", response.Message)
+			}
+		})
+	}
+}
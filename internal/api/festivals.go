@@ -3,10 +3,10 @@ package api
 import (
 	"encoding/json"
 	"fmt"
-	"io"
-	"log"
+	"log/slog"
 	"net/http"
 	"strings"
+	"time"
 
 	"github.com/neovasili/metal-fests/internal/data"
 	"github.com/neovasili/metal-fests/internal/model"
@@ -17,44 +17,76 @@ func handleUpdateFestival(w http.ResponseWriter, r *http.Request) {
 	// Extract festival key from path
 	pathParts := strings.Split(strings.TrimPrefix(r.URL.Path, "/api/festivals/"), "/")
 	if len(pathParts) == 0 || pathParts[0] == "" {
-		http.Error(w, "Festival key is required", http.StatusBadRequest)
+		httpError(w, r, http.StatusBadRequest, model.ErrorCodeBadRequest, "Festival key is required")
 		return
 	}
 	festivalKey := pathParts[0]
 
 	// Read request body
-	body, err := io.ReadAll(r.Body)
-	if err != nil {
-		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+	body, ok := readBody(w, r)
+	if !ok {
 		return
 	}
-	defer func() {
-		if err := r.Body.Close(); err != nil {
-			log.Printf("Failed to close request body: %v", err)
-		}
-	}()
 
 	// Parse updated festival data
 	var updatedFestival model.Festival
 	if err := json.Unmarshal(body, &updatedFestival); err != nil {
-		http.Error(w, fmt.Sprintf("Invalid JSON: %v", err), http.StatusBadRequest)
+		httpError(w, r, http.StatusBadRequest, model.ErrorCodeInvalidJSON, fmt.Sprintf("Invalid JSON: %v", err))
+		return
+	}
+
+	// The key in the path names the festival, the body may leave it out
+	if updatedFestival.Key == "" {
+		updatedFestival.Key = festivalKey
+	} else if updatedFestival.Key != festivalKey {
+		dataError(w, r, "Failed to update festival", data.NewValidationError("key", fmt.Sprintf("does not match %q in the path", festivalKey)))
 		return
 	}
 
-	err = data.UpdateFestivalInDatabase(updatedFestival)
+	existingFestival, err := data.GetFestival(r.Context(), updatedFestival.Key)
+	if err != nil {
+		dataError(w, r, "Failed to update festival", err)
+		return
+	}
+
+	// The form only edits the key, name and size of the bands. Their
+	// cancellation state is kept, so the consecutive misses keep counting.
+	storedBands := make(map[string]model.BandRef, len(existingFestival.Bands))
+	for _, band := range existingFestival.Bands {
+		storedBands[band.Key] = band
+	}
+	for i, band := range updatedFestival.Bands {
+		if stored, ok := storedBands[band.Key]; ok {
+			updatedFestival.Bands[i].PossiblyCancelled = stored.PossiblyCancelled
+			updatedFestival.Bands[i].Misses = stored.Misses
+		}
+	}
+
+	// Keep the stored provenance and mark the fields changed here as manual edits.
+	// The price history is kept as well, adding the new price when it changed.
+	now := time.Now().UTC()
+	manual := model.Provenance{Origin: model.ProvenanceManual, UpdatedAt: now}
+	updatedFestival.Provenance = existingFestival.Provenance.Set(manual, model.ChangedFields(*existingFestival, updatedFestival)...)
+	updatedFestival.PriceHistory = existingFestival.PriceHistory
+	if updatedFestival.TicketPrice != existingFestival.TicketPrice && updatedFestival.TicketPrice > 0 {
+		updatedFestival.RecordPrice(updatedFestival.TicketPrice, now)
+	}
+
+	err = data.UpdateFestivalInDatabase(r.Context(), updatedFestival)
 	if err != nil {
-		http.Error(w, fmt.Sprintf("Failed to update festival: %v", err), http.StatusInternalServerError)
+		dataError(w, r, "Failed to update festival", err)
 		return
 	}
 
 	// Send success response
 	w.Header().Set("Content-Type", "application/json")
-	if err := json.NewEncoder(w).Encode(model.UpdateBandResponse{
-		Success: true,
-		Message: "Festival updated",
+	if err := json.NewEncoder(w).Encode(model.UpdateFestivalResponse{
+		Success:  true,
+		Message:  "Festival updated",
+		Festival: updatedFestival,
 	}); err != nil {
-		log.Printf("Failed to encode response: %v", err)
+		slog.ErrorContext(r.Context(), "failed to encode response", "error", err)
 	}
 
-	log.Printf("✅ Updated festival: %s (%s)", updatedFestival.Name, festivalKey)
+	slog.InfoContext(r.Context(), "festival updated", "key", festivalKey, "name", updatedFestival.Name)
 }
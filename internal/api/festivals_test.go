@@ -2,11 +2,14 @@ package api
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 
 	"github.com/neovasili/metal-fests/internal/data"
@@ -62,3 +65,98 @@ func TestHandleUpdateFestival_Success(t *testing.T) {
 		t.Errorf("expected response to contain 'Festival updated', got %s", string(respBody))
 	}
 }
+
+func TestHandleUpdateFestival_KeepsPriceHistory(t *testing.T) {
+	tempFile := "test_db_festivals_price_history.json"
+	testData := `{"bands":[],"festivals":[{"key":"testkey","name":"Test Festival","dates":{"start":"","end":""},"location":"","coordinates":{"lat":0,"lng":0},"poster":"","website":"","bands":[],"ticketPrice":150,"priceHistory":[{"price":150,"observedAt":"2026-01-01T00:00:00Z"}]}]}`
+	if err := os.WriteFile(tempFile, []byte(testData), 0600); err != nil {
+		t.Fatalf("failed to create test db: %v", err)
+	}
+	oldPath := data.SetDBFilePathForTesting(tempFile)
+	defer func() {
+		data.SetDBFilePathForTesting(oldPath)
+		_ = os.Remove(tempFile)
+	}()
+
+	// The admin form does not send the history, only the new price
+	festival := model.Festival{Key: "testkey", Name: "Test Festival", TicketPrice: 180}
+	reqData, _ := json.Marshal(festival)
+	req := httptest.NewRequest("PUT", "/api/festivals/testkey", bytes.NewReader(reqData))
+	w := httptest.NewRecorder()
+	handleUpdateFestival(w, req)
+	if w.Result().StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Result().StatusCode)
+	}
+
+	stored, err := data.GetFestival(context.Background(), "testkey")
+	if err != nil {
+		t.Fatalf("GetFestival failed: %v", err)
+	}
+	if len(stored.PriceHistory) != 2 || stored.PriceHistory[0].Price != 150 || stored.PriceHistory[1].Price != 180 {
+		t.Errorf("expected price history 150 → 180, got %+v", stored.PriceHistory)
+	}
+	if _, tracked := stored.Provenance["priceHistory"]; tracked {
+		t.Errorf("expected no provenance for the price history, got %+v", stored.Provenance)
+	}
+}
+
+func TestHandleUpdateFestival_KeepsCancelledBands(t *testing.T) {
+	tempFile := "test_db_festivals_cancelled_bands.json"
+	testData := `{"bands":[],"festivals":[{"key":"testkey","name":"Test Festival","dates":{"start":"","end":""},"location":"","coordinates":{"lat":0,"lng":0},"poster":"","website":"","bands":[{"key":"gojira","name":"Gojira","size":3},{"key":"slayer","name":"Slayer","size":2,"possiblyCancelled":true,"misses":2}],"ticketPrice":150}]}`
+	if err := os.WriteFile(tempFile, []byte(testData), 0600); err != nil {
+		t.Fatalf("failed to create test db: %v", err)
+	}
+	oldPath := data.SetDBFilePathForTesting(tempFile)
+	defer func() {
+		data.SetDBFilePathForTesting(oldPath)
+		_ = os.Remove(tempFile)
+	}()
+
+	// The admin form sends the key, name and size of the bands only
+	festival := model.Festival{Key: "testkey", Name: "Test Festival", TicketPrice: 150, Bands: []model.BandRef{
+		{Key: "gojira", Name: "Gojira", Size: 3},
+		{Key: "slayer", Name: "Slayer", Size: 3},
+		{Key: "mastodon", Name: "Mastodon", Size: 1},
+	}}
+	reqData, _ := json.Marshal(festival)
+	req := httptest.NewRequest("PUT", "/api/festivals/testkey", bytes.NewReader(reqData))
+	w := httptest.NewRecorder()
+	handleUpdateFestival(w, req)
+	if w.Result().StatusCode != http.StatusOK {
+		t.Fatalf("expected status 200, got %d", w.Result().StatusCode)
+	}
+
+	stored, err := data.GetFestival(context.Background(), "testkey")
+	if err != nil {
+		t.Fatalf("GetFestival failed: %v", err)
+	}
+	want := []model.BandRef{
+		{Key: "gojira", Name: "Gojira", Size: 3},
+		{Key: "slayer", Name: "Slayer", Size: 3, PossiblyCancelled: true, Misses: 2},
+		{Key: "mastodon", Name: "Mastodon", Size: 1},
+	}
+	if !reflect.DeepEqual(stored.Bands, want) {
+		t.Errorf("bands = %+v, want %+v", stored.Bands, want)
+	}
+}
+
+func TestHandleUpdateFestival_NotFound(t *testing.T) {
+	tempFile := filepath.Join(t.TempDir(), "db.json")
+	if err := os.WriteFile(tempFile, []byte(`{"bands":[],"festivals":[]}`), 0600); err != nil {
+		t.Fatalf("failed to create test db: %v", err)
+	}
+	oldPath := data.SetDBFilePathForTesting(tempFile)
+	defer data.SetDBFilePathForTesting(oldPath)
+
+	reqData, _ := json.Marshal(model.Festival{Key: "missing", Name: "Missing"})
+	w := httptest.NewRecorder()
+	handleUpdateFestival(w, httptest.NewRequest("PUT", "/api/festivals/missing", bytes.NewReader(reqData)))
+
+	var response model.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("body is not an error envelope: %v", err)
+	}
+	if w.Code != http.StatusNotFound || response.Code != model.ErrorCodeNotFound {
+		t.Errorf("response = %d %q, want %d %q", w.Code, response.Code, http.StatusNotFound, model.ErrorCodeNotFound)
+	}
+}
@@ -3,18 +3,35 @@ package api
 import (
 	"net/http"
 	"strings"
+
+	"github.com/neovasili/metal-fests/internal/auth"
+	"github.com/neovasili/metal-fests/internal/model"
 )
 
-// API router
-func Router(w http.ResponseWriter, r *http.Request) {
-	switch {
-	case r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/api/bands/"):
-		handleUpdateBand(w, r)
-	case r.Method == "PUT" && strings.HasPrefix(r.URL.Path, "/api/festivals/"):
-		handleUpdateFestival(w, r)
-	case r.Method == "POST" && r.URL.Path == "/api/validate-url":
-		handleValidateURL(w, r)
-	default:
-		http.Error(w, "Not Found", http.StatusNotFound)
+// Register adds the API routes to mux. The patterns name the routes in the
+// metrics, so the keys in the paths don't become labels. The routes of the
+// admin pages require the callers to be authenticated by authenticator, when
+// it is not nil.
+func Register(mux *http.ServeMux, authenticator auth.Authenticator) {
+	mux.HandleFunc("PUT /api/bands/{key}", requireRole(authenticator, auth.RoleEditor, handleUpdateBand))
+	mux.HandleFunc("PUT /api/festivals/{key}", requireRole(authenticator, auth.RoleEditor, handleUpdateFestival))
+	mux.HandleFunc("POST /api/validate-url", requireRole(authenticator, auth.RoleEditor, handleValidateURL))
+	mux.HandleFunc("GET /api/admin/stats", requireRole(authenticator, auth.RoleViewer, handleAdminStats))
+	mux.HandleFunc("/api/", func(w http.ResponseWriter, r *http.Request) {
+		httpError(w, r, http.StatusNotFound, model.ErrorCodeNotFound, "Not Found")
+	})
+}
+
+// adminPaths prefix the routes of the admin pages, the ones Register
+// protects with a role
+var adminPaths = []string{"/api/bands/", "/api/festivals/", "/api/validate-url", "/api/admin/"}
+
+// IsAdminPath reports whether path belongs to the admin API
+func IsAdminPath(path string) bool {
+	for _, prefix := range adminPaths {
+		if strings.HasPrefix(path, prefix) {
+			return true
+		}
 	}
+	return false
 }
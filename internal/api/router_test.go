@@ -1,19 +1,100 @@
 package api
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+
+	"github.com/neovasili/metal-fests/internal/auth"
+	"github.com/neovasili/metal-fests/internal/logging"
+	"github.com/neovasili/metal-fests/internal/model"
 )
 
+// testToken authenticates the requests to the routes of newTestRouter
+const testToken = "test-secret"
+
+// newTestRouter returns the API routes, open to the callers sending testToken
+func newTestRouter(t *testing.T) *http.ServeMux {
+	t.Helper()
+	tokens, err := auth.ParseTokens([]string{tokenEntry("test", "admin", testToken)})
+	if err != nil {
+		t.Fatalf("ParseTokens() failed: %v", err)
+	}
+	mux := http.NewServeMux()
+	Register(mux, tokens)
+	return mux
+}
+
 func TestRouter_NotFound(t *testing.T) {
 	req := httptest.NewRequest("GET", "/api/unknown", nil)
 	w := httptest.NewRecorder()
-	Router(w, req)
+	newTestRouter(t).ServeHTTP(w, req)
 	resp := w.Result()
 	if resp.StatusCode != http.StatusNotFound {
 		t.Errorf("expected status 404, got %d", resp.StatusCode)
 	}
 }
 
-// More router tests can be added for each route if needed.
+func TestRegister_Patterns(t *testing.T) {
+	mux := newTestRouter(t)
+
+	tests := []struct {
+		method  string
+		path    string
+		pattern string
+	}{
+		{method: "PUT", path: "/api/bands/gojira", pattern: "PUT /api/bands/{key}"},
+		{method: "PUT", path: "/api/festivals/hellfest", pattern: "PUT /api/festivals/{key}"},
+		{method: "POST", path: "/api/validate-url", pattern: "POST /api/validate-url"},
+		{method: "GET", path: "/api/admin/stats", pattern: "GET /api/admin/stats"},
+		{method: "GET", path: "/api/bands/gojira", pattern: "/api/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			_, pattern := mux.Handler(httptest.NewRequest(tt.method, tt.path, nil))
+			if pattern != tt.pattern {
+				t.Errorf("pattern = %q, want %q", pattern, tt.pattern)
+			}
+		})
+	}
+}
+
+func TestRouter_ErrorIncludesRequestID(t *testing.T) {
+	req := httptest.NewRequest("GET", "/api/unknown", nil)
+	req.Header.Set(logging.RequestIDHeader, "req-42")
+	w := httptest.NewRecorder()
+	logging.Middleware(newTestRouter(t)).ServeHTTP(w, req)
+
+	var response model.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("body is not an error envelope: %v", err)
+	}
+	if response.Code != model.ErrorCodeNotFound || response.RequestID != "req-42" {
+		t.Errorf("response = %+v, want code %q and request ID %q", response, model.ErrorCodeNotFound, "req-42")
+	}
+}
+
+func TestIsAdminPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want bool
+	}{
+		{path: "/api/bands/gojira", want: true},
+		{path: "/api/festivals/hellfest", want: true},
+		{path: "/api/validate-url", want: true},
+		{path: "/api/admin/stats", want: true},
+		{path: "/api/health", want: false},
+		{path: "/db.json", want: false},
+		{path: "/admin/", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := IsAdminPath(tt.path); got != tt.want {
+				t.Errorf("IsAdminPath(%q) = %v, want %v", tt.path, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,73 @@
+package api
+
+import (
+	"sync"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+const (
+	// urlCacheTTL is how long the answer of a URL is reused. The admin
+	// validates the same image and website URLs over and over while editing.
+	urlCacheTTL = 5 * time.Minute
+	// urlCacheSize bounds the number of URLs remembered
+	urlCacheSize = 1000
+)
+
+type urlCacheEntry struct {
+	response  model.ValidateURLResponse
+	expiresAt time.Time
+}
+
+// urlCache remembers the answers of the URLs validated recently
+type urlCache struct {
+	mu      sync.Mutex
+	entries map[string]urlCacheEntry
+	ttl     time.Duration
+	size    int
+	now     func() time.Time
+}
+
+func newURLCache(ttl time.Duration, size int) *urlCache {
+	return &urlCache{entries: make(map[string]urlCacheEntry), ttl: ttl, size: size, now: time.Now}
+}
+
+func (c *urlCache) get(url string) (model.ValidateURLResponse, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[url]
+	if !ok {
+		return model.ValidateURLResponse{}, false
+	}
+	if c.now().After(entry.expiresAt) {
+		delete(c.entries, url)
+		return model.ValidateURLResponse{}, false
+	}
+	return entry.response, true
+}
+
+// put remembers the response of a URL. Unreachable URLs are not remembered,
+// as network errors are usually transient.
+func (c *urlCache) put(response model.ValidateURLResponse) {
+	if response.Status == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+	if len(c.entries) >= c.size {
+		for url, entry := range c.entries {
+			if now.After(entry.expiresAt) {
+				delete(c.entries, url)
+			}
+		}
+	}
+	if len(c.entries) >= c.size {
+		return
+	}
+	c.entries[response.URL] = urlCacheEntry{response: response, expiresAt: now.Add(c.ttl)}
+}
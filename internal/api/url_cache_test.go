@@ -0,0 +1,40 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+func TestURLCache(t *testing.T) {
+	now := time.Now()
+	cache := newURLCache(time.Minute, 2)
+	cache.now = func() time.Time { return now }
+
+	cache.put(model.ValidateURLResponse{URL: "https://a.example", Valid: true, Status: 200})
+	cache.put(model.ValidateURLResponse{URL: "https://b.example", Error: "timeout"})
+
+	if response, ok := cache.get("https://a.example"); !ok || !response.Valid {
+		t.Errorf("get(a) = %+v, %v, want the cached response", response, ok)
+	}
+	if _, ok := cache.get("https://b.example"); ok {
+		t.Error("get(b) should miss, unreachable URLs are not cached")
+	}
+
+	// The cache is full until the first entry expires
+	cache.put(model.ValidateURLResponse{URL: "https://c.example", Status: 404})
+	cache.put(model.ValidateURLResponse{URL: "https://d.example", Status: 404})
+	if _, ok := cache.get("https://d.example"); ok {
+		t.Error("get(d) should miss, the cache was full")
+	}
+
+	now = now.Add(2 * time.Minute)
+	if _, ok := cache.get("https://a.example"); ok {
+		t.Error("get(a) should miss once expired")
+	}
+	cache.put(model.ValidateURLResponse{URL: "https://d.example", Status: 404})
+	if _, ok := cache.get("https://d.example"); !ok {
+		t.Error("get(d) should hit once expired entries were evicted")
+	}
+}
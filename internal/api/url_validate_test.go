@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"github.com/neovasili/metal-fests/internal/model"
+	"github.com/neovasili/metal-fests/internal/urlcheck"
 )
 
 func TestHandleValidateURL_BadRequest(t *testing.T) {
@@ -42,4 +43,44 @@ func TestHandleValidateURL_EmptyURL(t *testing.T) {
 	}
 }
 
-// Note: A full integration test for a valid URL would require network access and is best done as an integration test.
+func TestHandleValidateURL_Reachable(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ok" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	// The test server listens on the loopback interface
+	originalChecker := urlChecker
+	urlChecker = urlcheck.NewChecker(urlcheck.Options{AllowLoopback: true})
+	t.Cleanup(func() { urlChecker = originalChecker })
+
+	tests := []struct {
+		name      string
+		url       string
+		wantValid bool
+	}{
+		{name: "reachable", url: server.URL + "/ok", wantValid: true},
+		{name: "not found", url: server.URL + "/missing", wantValid: false},
+		{name: "metadata service", url: "http://169.254.169.254/latest/meta-data/", wantValid: false},
+		{name: "file", url: "file:///etc/passwd", wantValid: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reqBody, _ := json.Marshal(model.ValidateURLRequest{URL: tt.url})
+			req := httptest.NewRequest("POST", "/api/validate-url", bytes.NewReader(reqBody))
+			w := httptest.NewRecorder()
+			handleValidateURL(w, req)
+
+			var response model.ValidateURLResponse
+			if err := json.NewDecoder(w.Result().Body).Decode(&response); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if response.Valid != tt.wantValid {
+				t.Errorf("expected valid %v, got %+v", tt.wantValid, response)
+			}
+		})
+	}
+}
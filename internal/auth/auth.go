@@ -0,0 +1,126 @@
+// Package auth authenticates the callers of the admin API and tells what
+// their role lets them do
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Role is what a caller may do. Each role can do everything the roles below
+// it can.
+type Role int
+
+// Roles of the admin API, from the least to the most powerful
+const (
+	// RoleNone is the role of a caller who authenticated but belongs to no
+	// known group. It allows nothing.
+	RoleNone Role = iota
+	// RoleViewer reads the admin endpoints
+	RoleViewer
+	// RoleEditor edits festivals and bands
+	RoleEditor
+	// RoleAdmin does everything, including the destructive operations
+	RoleAdmin
+)
+
+var roleNames = map[Role]string{
+	RoleNone:   "none",
+	RoleViewer: "viewer",
+	RoleEditor: "editor",
+	RoleAdmin:  "admin",
+}
+
+func (r Role) String() string {
+	if name, ok := roleNames[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("Role(%d)", int(r))
+}
+
+// Allows reports whether the role can do what required can
+func (r Role) Allows(required Role) bool {
+	return r >= required
+}
+
+// ParseRole returns the role called name: "viewer", "editor" or "admin"
+func ParseRole(name string) (Role, error) {
+	for role, roleName := range roleNames {
+		if role != RoleNone && roleName == name {
+			return role, nil
+		}
+	}
+	return RoleNone, fmt.Errorf("unknown role %q, want \"viewer\", \"editor\" or \"admin\"", name)
+}
+
+// Identity is the authenticated caller of a request
+type Identity struct {
+	// Subject names the caller: the name of its API token, or the subject
+	// of its JWT
+	Subject string
+	// Method is how the caller authenticated: "token" or "oidc"
+	Method string
+	Role   Role
+}
+
+// String names the caller in the logs, e.g. "token:ci"
+func (i Identity) String() string {
+	return i.Method + ":" + i.Subject
+}
+
+// Errors of the authenticators
+var (
+	// ErrInvalidCredentials is returned for tokens that are not valid: unknown,
+	// expired, badly signed or issued for someone else
+	ErrInvalidCredentials = errors.New("invalid credentials")
+	// ErrUnknownToken is returned by an authenticator for tokens it does not
+	// handle, so that the next one of a Chain gets them
+	ErrUnknownToken = errors.New("unknown token")
+)
+
+// Authenticator finds out who presented a bearer token
+type Authenticator interface {
+	Authenticate(ctx context.Context, token string) (Identity, error)
+}
+
+// Chain tries each of its authenticators in turn, until one handles the token
+type Chain []Authenticator
+
+// Authenticate returns the identity given by the first authenticator handling
+// token, or ErrInvalidCredentials when none does
+func (c Chain) Authenticate(ctx context.Context, token string) (Identity, error) {
+	for _, authenticator := range c {
+		identity, err := authenticator.Authenticate(ctx, token)
+		if errors.Is(err, ErrUnknownToken) {
+			continue
+		}
+		return identity, err
+	}
+	return Identity{}, ErrInvalidCredentials
+}
+
+// BearerToken returns the token of the Authorization header of r, if any
+func BearerToken(r *http.Request) (string, bool) {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return "", false
+	}
+	token = strings.TrimSpace(token)
+	return token, token != ""
+}
+
+type identityKey struct{}
+
+// WithIdentity returns a copy of ctx carrying the caller identity
+func WithIdentity(ctx context.Context, identity Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns the caller identity carried by ctx, if any
+func FromContext(ctx context.Context) (Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(Identity)
+	return identity, ok
+}
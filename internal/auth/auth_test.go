@@ -0,0 +1,117 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseRole(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Role
+		wantErr bool
+	}{
+		{name: "viewer", want: RoleViewer},
+		{name: "editor", want: RoleEditor},
+		{name: "admin", want: RoleAdmin},
+		{name: "none", wantErr: true},
+		{name: "Admin", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRole(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRole(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseRole(%q) = %v, want %v", tt.name, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRoleAllows(t *testing.T) {
+	tests := []struct {
+		role     Role
+		required Role
+		want     bool
+	}{
+		{role: RoleAdmin, required: RoleEditor, want: true},
+		{role: RoleEditor, required: RoleEditor, want: true},
+		{role: RoleViewer, required: RoleEditor, want: false},
+		{role: RoleNone, required: RoleViewer, want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.role.String()+" "+tt.required.String(), func(t *testing.T) {
+			if got := tt.role.Allows(tt.required); got != tt.want {
+				t.Errorf("%v.Allows(%v) = %v, want %v", tt.role, tt.required, got, tt.want)
+			}
+		})
+	}
+}
+
+type staticAuthenticator struct {
+	identity Identity
+	err      error
+}
+
+func (s staticAuthenticator) Authenticate(context.Context, string) (Identity, error) {
+	return s.identity, s.err
+}
+
+func TestChain(t *testing.T) {
+	ci := Identity{Subject: "ci", Method: MethodToken, Role: RoleEditor}
+	tests := []struct {
+		name    string
+		chain   Chain
+		want    Identity
+		wantErr error
+	}{
+		{name: "First handles the token", chain: Chain{staticAuthenticator{identity: ci}, staticAuthenticator{err: ErrInvalidCredentials}}, want: ci},
+		{name: "Skips authenticators not handling the token", chain: Chain{staticAuthenticator{err: ErrUnknownToken}, staticAuthenticator{identity: ci}}, want: ci},
+		{name: "Stops at invalid credentials", chain: Chain{staticAuthenticator{err: ErrInvalidCredentials}, staticAuthenticator{identity: ci}}, wantErr: ErrInvalidCredentials},
+		{name: "Nobody handles the token", chain: Chain{staticAuthenticator{err: ErrUnknownToken}}, wantErr: ErrInvalidCredentials},
+		{name: "Empty", chain: Chain{}, wantErr: ErrInvalidCredentials},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.chain.Authenticate(context.Background(), "token")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Authenticate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBearerToken(t *testing.T) {
+	tests := []struct {
+		header string
+		want   string
+		wantOK bool
+	}{
+		{header: "Bearer abc", want: "abc", wantOK: true},
+		{header: "bearer  abc ", want: "abc", wantOK: true},
+		{header: "Basic abc"},
+		{header: "Bearer "},
+		{header: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			req := httptest.NewRequest("PUT", "/api/bands/gojira", nil)
+			req.Header.Set("Authorization", tt.header)
+			got, ok := BearerToken(req)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("BearerToken() = %q, %v, want %q, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
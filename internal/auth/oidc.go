@@ -0,0 +1,301 @@
+package auth
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/neovasili/metal-fests/internal/tracing"
+)
+
+// MethodOIDC is the Method of the identities given by OIDC bearer tokens
+const MethodOIDC = "oidc"
+
+const (
+	// DefaultRolesClaim holds the groups of a Cognito user
+	DefaultRolesClaim = "cognito:groups"
+	// jwksRefreshInterval is the shortest time between two downloads of the
+	// keys, so tokens with an unknown key ID cannot hammer the issuer
+	jwksRefreshInterval = time.Minute
+	// jwksTimeout bounds a download of the keys
+	jwksTimeout = 10 * time.Second
+	// maxJWKSSize bounds the key set read from the issuer
+	maxJWKSSize = 1 << 20
+	// clockSkew is the leeway given to the expiry and validity times
+	clockSkew = 30 * time.Second
+)
+
+// DefaultGroupRoles maps the groups of ADMIN_API_DESIGN.md to the roles
+var DefaultGroupRoles = map[string]Role{
+	"AdminFull":   RoleAdmin,
+	"AdminEditor": RoleEditor,
+	"AdminViewer": RoleViewer,
+}
+
+// signingMethods are the algorithms accepted in the tokens. Asymmetric ones
+// only: the key set is public, so an HMAC signed with it proves nothing.
+var signingMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512", "EdDSA"}
+
+// OIDCOptions configures an OIDC authenticator
+type OIDCOptions struct {
+	// Issuer must match the iss claim of the tokens
+	Issuer string
+	// Audience must be in the aud claim of the tokens, when set. Cognito
+	// access tokens carry no audience.
+	Audience string
+	// JWKSURL serves the public keys signing the tokens
+	JWKSURL string
+	// RolesClaim holds the groups of the caller, DefaultRolesClaim when empty
+	RolesClaim string
+	// GroupRoles gives the role of each group, DefaultGroupRoles when nil.
+	// Groups it leaves out give no role, whatever their name.
+	GroupRoles map[string]Role
+	// Client downloads the keys, a client with a timeout when nil
+	Client *http.Client
+}
+
+// OIDC authenticates JWT bearer tokens signed by an OpenID Connect issuer
+type OIDC struct {
+	opts OIDCOptions
+	keys *keySet
+}
+
+// NewOIDC returns an authenticator of the tokens issued by opts.Issuer. The
+// keys are downloaded when the first token comes.
+func NewOIDC(opts OIDCOptions) *OIDC {
+	if opts.RolesClaim == "" {
+		opts.RolesClaim = DefaultRolesClaim
+	}
+	if opts.GroupRoles == nil {
+		opts.GroupRoles = DefaultGroupRoles
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: jwksTimeout, Transport: tracing.Transport(nil)}
+	}
+	return &OIDC{opts: opts, keys: &keySet{url: opts.JWKSURL, client: opts.Client}}
+}
+
+// Authenticate verifies the signature, issuer, audience and validity times
+// of token and returns the identity of its subject, with the highest role
+// its groups give. Tokens that are not JWTs are left to the next
+// authenticator of a Chain.
+func (o *OIDC) Authenticate(ctx context.Context, token string) (Identity, error) {
+	if strings.Count(token, ".") != 2 {
+		return Identity{}, ErrUnknownToken
+	}
+
+	parserOptions := []jwt.ParserOption{
+		jwt.WithValidMethods(signingMethods),
+		jwt.WithIssuer(o.opts.Issuer),
+		jwt.WithExpirationRequired(),
+		jwt.WithLeeway(clockSkew),
+	}
+	if o.opts.Audience != "" {
+		parserOptions = append(parserOptions, jwt.WithAudience(o.opts.Audience))
+	}
+	claims := jwt.MapClaims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return o.keys.key(ctx, kid)
+	}, parserOptions...)
+	if err != nil {
+		return Identity{}, fmt.Errorf("%w: %w", ErrInvalidCredentials, err)
+	}
+
+	subject, err := claims.GetSubject()
+	if err != nil || subject == "" {
+		return Identity{}, fmt.Errorf("%w: the token has no subject", ErrInvalidCredentials)
+	}
+	return Identity{Subject: subject, Method: MethodOIDC, Role: o.role(claims[o.opts.RolesClaim])}, nil
+}
+
+// role returns the highest role given by the groups in claim, a string or a
+// list of strings
+func (o *OIDC) role(claim any) Role {
+	var groups []string
+	switch value := claim.(type) {
+	case string:
+		groups = strings.Fields(value)
+	case []any:
+		for _, group := range value {
+			if name, ok := group.(string); ok {
+				groups = append(groups, name)
+			}
+		}
+	}
+
+	best := RoleNone
+	for _, group := range groups {
+		if role := o.opts.GroupRoles[group]; role > best {
+			best = role
+		}
+	}
+	return best
+}
+
+// keySet caches the public keys of an issuer, by key ID
+type keySet struct {
+	url    string
+	client *http.Client
+
+	mu      sync.Mutex
+	keys    map[string]any
+	fetched time.Time
+}
+
+// key returns the public key called kid, downloading the keys again when it
+// is unknown, as the issuer may have rotated them
+func (s *keySet) key(ctx context.Context, kid string) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+	if !s.fetched.IsZero() && time.Since(s.fetched) < jwksRefreshInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	keys, err := s.fetch(ctx)
+	s.fetched = time.Now()
+	if err != nil {
+		return nil, err
+	}
+	s.keys = keys
+	if key, ok := s.lookup(kid); ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// lookup finds kid among the cached keys. Tokens without a key ID are
+// accepted when the issuer has a single key.
+func (s *keySet) lookup(kid string) (any, bool) {
+	if kid == "" && len(s.keys) == 1 {
+		for _, key := range s.keys {
+			return key, true
+		}
+	}
+	key, ok := s.keys[kid]
+	return key, ok
+}
+
+// jsonWebKey is a key of a JWKS document, as described by RFC 7517
+type jsonWebKey struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+func (s *keySet) fetch(ctx context.Context) (map[string]any, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download the signing keys: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download the signing keys: %s", resp.Status)
+	}
+
+	var document struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, maxJWKSSize)).Decode(&document); err != nil {
+		return nil, fmt.Errorf("failed to read the signing keys: %w", err)
+	}
+
+	keys := make(map[string]any, len(document.Keys))
+	for _, jwk := range document.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// A key of an unsupported type doesn't spoil the others
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("the issuer has no usable signing key")
+	}
+	return keys, nil
+}
+
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil || !e.IsInt64() {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	case "OKP":
+		if k.Crv != "Ed25519" {
+			return nil, fmt.Errorf("unsupported curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil || len(x) != ed25519.PublicKeySize {
+			return nil, errors.New("invalid Ed25519 key")
+		}
+		return ed25519.PublicKey(x), nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+func decodeBigInt(value string) (*big.Int, error) {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil || len(decoded) == 0 {
+		return nil, errors.New("invalid key parameter")
+	}
+	return new(big.Int).SetBytes(decoded), nil
+}
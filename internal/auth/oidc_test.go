@@ -0,0 +1,134 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func newTestIssuer(t *testing.T) *TestIssuer {
+	t.Helper()
+	issuer, err := NewTestIssuer()
+	if err != nil {
+		t.Fatalf("NewTestIssuer() failed: %v", err)
+	}
+	t.Cleanup(issuer.Close)
+	return issuer
+}
+
+func TestOIDCAuthenticate(t *testing.T) {
+	issuer := newTestIssuer(t)
+	other := newTestIssuer(t)
+	now := time.Now()
+	claims := func(changes jwt.MapClaims) jwt.MapClaims {
+		claims := jwt.MapClaims{"iss": issuer.URL, "sub": "alice", "aud": "metal-fests-admin", "exp": now.Add(time.Hour).Unix(), DefaultRolesClaim: []string{"AdminEditor"}}
+		for name, value := range changes {
+			if value == nil {
+				delete(claims, name)
+			} else {
+				claims[name] = value
+			}
+		}
+		return claims
+	}
+	sign := func(claims jwt.MapClaims) string {
+		token, err := issuer.Sign(claims)
+		if err != nil {
+			t.Fatalf("Sign() failed: %v", err)
+		}
+		return token
+	}
+	forged, err := other.Sign(claims(nil))
+	if err != nil {
+		t.Fatalf("Sign() failed: %v", err)
+	}
+	hmac, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims(nil)).SignedString([]byte("secret"))
+	if err != nil {
+		t.Fatalf("SignedString() failed: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		token    string
+		want     Role
+		wantErr  error
+		audience string
+	}{
+		{name: "Editor group", token: sign(claims(nil)), want: RoleEditor},
+		{name: "Highest of several groups", token: sign(claims(jwt.MapClaims{DefaultRolesClaim: []string{"AdminViewer", "AdminFull"}})), want: RoleAdmin},
+		{name: "Group named after a role", token: sign(claims(jwt.MapClaims{DefaultRolesClaim: []string{"admin", "editor"}})), want: RoleNone},
+		{name: "Unknown group", token: sign(claims(jwt.MapClaims{DefaultRolesClaim: []string{"Fans"}})), want: RoleNone},
+		{name: "Matching audience", token: sign(claims(nil)), audience: "metal-fests-admin", want: RoleEditor},
+		{name: "Other audience", token: sign(claims(nil)), audience: "other-app", wantErr: ErrInvalidCredentials},
+		{name: "Expired", token: sign(claims(jwt.MapClaims{"exp": now.Add(-time.Hour).Unix()})), wantErr: ErrInvalidCredentials},
+		{name: "Without expiry", token: sign(claims(jwt.MapClaims{"exp": nil})), wantErr: ErrInvalidCredentials},
+		{name: "Other issuer", token: sign(claims(jwt.MapClaims{"iss": "https://evil.example.com"})), wantErr: ErrInvalidCredentials},
+		{name: "Without subject", token: sign(claims(jwt.MapClaims{"sub": nil})), wantErr: ErrInvalidCredentials},
+		{name: "Signed by another key", token: forged, wantErr: ErrInvalidCredentials},
+		{name: "Signed with HMAC", token: hmac, wantErr: ErrInvalidCredentials},
+		{name: "Not a JWT", token: "ci-secret", wantErr: ErrUnknownToken},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := issuer.Options()
+			opts.Audience = tt.audience
+			identity, err := NewOIDC(opts).Authenticate(context.Background(), tt.token)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if identity.Role != tt.want || identity.Subject != "alice" || identity.Method != MethodOIDC {
+				t.Errorf("Authenticate() = %+v, want alice with role %v", identity, tt.want)
+			}
+		})
+	}
+}
+
+type countingTransport struct {
+	requests atomic.Int32
+	base     http.RoundTripper
+}
+
+func (c *countingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	c.requests.Add(1)
+	return c.base.RoundTrip(req)
+}
+
+func TestOIDCAuthenticate_CachesKeys(t *testing.T) {
+	issuer := newTestIssuer(t)
+	opts := issuer.Options()
+	transport := &countingTransport{base: opts.Client.Transport}
+	opts.Client = &http.Client{Transport: transport}
+	authenticator := NewOIDC(opts)
+
+	token, err := issuer.Token("alice", "AdminViewer")
+	if err != nil {
+		t.Fatalf("Token() failed: %v", err)
+	}
+	unknownKey := jwt.NewWithClaims(jwt.SigningMethodRS256, jwt.MapClaims{"iss": issuer.URL, "sub": "alice", "exp": time.Now().Add(time.Hour).Unix()})
+	unknownKey.Header["kid"] = "rotated"
+	unknownKeyToken, err := unknownKey.SignedString(issuer.key)
+	if err != nil {
+		t.Fatalf("SignedString() failed: %v", err)
+	}
+
+	for range 3 {
+		if _, err := authenticator.Authenticate(context.Background(), token); err != nil {
+			t.Fatalf("Authenticate() error = %v", err)
+		}
+		if _, err := authenticator.Authenticate(context.Background(), unknownKeyToken); !errors.Is(err, ErrInvalidCredentials) {
+			t.Fatalf("Authenticate() with an unknown key error = %v, want %v", err, ErrInvalidCredentials)
+		}
+	}
+	if got := transport.requests.Load(); got != 1 {
+		t.Errorf("downloaded the keys %d times, want 1", got)
+	}
+}
@@ -0,0 +1,83 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// testIssuerKeyID names the signing key of a TestIssuer
+const testIssuerKeyID = "test-key"
+
+// TestIssuer is a local OIDC issuer signing tokens with its own key, served
+// as a JWKS document
+// This should only be used in tests
+type TestIssuer struct {
+	// URL is the issuer, the iss claim of its tokens
+	URL string
+	// JWKSURL serves the public key of the issuer
+	JWKSURL string
+
+	server *httptest.Server
+	key    *rsa.PrivateKey
+}
+
+// NewTestIssuer starts a local issuer, to be closed once done
+// This should only be used in tests
+func NewTestIssuer() (*TestIssuer, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		return nil, err
+	}
+	issuer := &TestIssuer{key: key}
+	issuer.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": testIssuerKeyID,
+			"use": "sig",
+			"alg": "RS256",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	}))
+	issuer.URL = issuer.server.URL
+	issuer.JWKSURL = issuer.server.URL + "/.well-known/jwks.json"
+	return issuer, nil
+}
+
+// Options returns the options of an authenticator trusting the issuer
+func (i *TestIssuer) Options() OIDCOptions {
+	return OIDCOptions{Issuer: i.URL, JWKSURL: i.JWKSURL, Client: i.server.Client()}
+}
+
+// Token returns a token of subject in groups, valid for an hour
+func (i *TestIssuer) Token(subject string, groups ...string) (string, error) {
+	now := time.Now()
+	return i.Sign(jwt.MapClaims{
+		"iss":             i.URL,
+		"sub":             subject,
+		"iat":             now.Unix(),
+		"exp":             now.Add(time.Hour).Unix(),
+		DefaultRolesClaim: groups,
+	})
+}
+
+// Sign returns a token with claims, signed by the issuer
+func (i *TestIssuer) Sign(claims jwt.MapClaims) (string, error) {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = testIssuerKeyID
+	return token.SignedString(i.key)
+}
+
+// Close stops the issuer
+func (i *TestIssuer) Close() {
+	i.server.Close()
+}
@@ -0,0 +1,70 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+)
+
+// MethodToken is the Method of the identities given by static API tokens
+const MethodToken = "token"
+
+// Tokens authenticates the static API tokens, meant for scripts and CI
+type Tokens struct {
+	// byHash holds the identity of each token by its SHA-256, so the tokens
+	// themselves never sit in the configuration
+	byHash map[string]Identity
+}
+
+// ParseTokens reads tokens written as "name:role:sha256", where sha256 is the
+// hex SHA-256 of the token, e.g. the output of
+//
+//	printf %s "$TOKEN" | sha256sum
+func ParseTokens(entries []string) (*Tokens, error) {
+	tokens := &Tokens{byHash: make(map[string]Identity, len(entries))}
+	for _, entry := range entries {
+		parts := strings.Split(entry, ":")
+		if len(parts) != 3 || parts[0] == "" {
+			return nil, fmt.Errorf("token %q is not written as name:role:sha256", redactEntry(entry))
+		}
+		name, roleName, hash := parts[0], parts[1], strings.ToLower(parts[2])
+		role, err := ParseRole(roleName)
+		if err != nil {
+			return nil, fmt.Errorf("token %q: %w", name, err)
+		}
+		if digest, err := hex.DecodeString(hash); err != nil || len(digest) != sha256.Size {
+			return nil, fmt.Errorf("token %q: %q is not a hex SHA-256", name, redactEntry(hash))
+		}
+		if _, duplicate := tokens.byHash[hash]; duplicate {
+			return nil, fmt.Errorf("token %q: the same token is listed twice", name)
+		}
+		tokens.byHash[hash] = Identity{Subject: name, Method: MethodToken, Role: role}
+	}
+	return tokens, nil
+}
+
+// Len returns the number of tokens
+func (t *Tokens) Len() int {
+	return len(t.byHash)
+}
+
+// Authenticate returns the identity of token. Tokens that are not listed are
+// left to the next authenticator of a Chain.
+func (t *Tokens) Authenticate(_ context.Context, token string) (Identity, error) {
+	digest := sha256.Sum256([]byte(token))
+	if identity, ok := t.byHash[hex.EncodeToString(digest[:])]; ok {
+		return identity, nil
+	}
+	return Identity{}, ErrUnknownToken
+}
+
+// redactEntry keeps a mistaken entry recognizable in an error without
+// printing it whole, in case it holds a token instead of its hash
+func redactEntry(entry string) string {
+	if len(entry) <= 8 {
+		return entry
+	}
+	return entry[:8] + "…"
+}
@@ -0,0 +1,69 @@
+package auth
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func hashToken(token string) string {
+	digest := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(digest[:])
+}
+
+func TestParseTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries []string
+		wantErr string
+	}{
+		{name: "Valid", entries: []string{"ci:editor:" + hashToken("ci-secret"), "ops:admin:" + strings.ToUpper(hashToken("ops-secret"))}},
+		{name: "Missing part", entries: []string{"ci:" + hashToken("ci-secret")}, wantErr: "name:role:sha256"},
+		{name: "Unknown role", entries: []string{"ci:owner:" + hashToken("ci-secret")}, wantErr: "unknown role"},
+		{name: "Token instead of its hash", entries: []string{"ci:editor:ci-secret-in-plain-text"}, wantErr: "not a hex SHA-256"},
+		{name: "Duplicate", entries: []string{"ci:editor:" + hashToken("same"), "ops:admin:" + hashToken("same")}, wantErr: "twice"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseTokens(tt.entries)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ParseTokens() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ParseTokens() error = %v, want %q", err, tt.wantErr)
+			}
+			if err != nil && strings.Contains(err.Error(), "ci-secret-in-plain-text") {
+				t.Errorf("ParseTokens() error = %v, want the token left out", err)
+			}
+		})
+	}
+}
+
+func TestTokensAuthenticate(t *testing.T) {
+	tokens, err := ParseTokens([]string{"ci:editor:" + hashToken("ci-secret")})
+	if err != nil {
+		t.Fatalf("ParseTokens() failed: %v", err)
+	}
+
+	identity, err := tokens.Authenticate(context.Background(), "ci-secret")
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+	if want := (Identity{Subject: "ci", Method: MethodToken, Role: RoleEditor}); identity != want {
+		t.Errorf("Authenticate() = %+v, want %+v", identity, want)
+	}
+	if identity.String() != "token:ci" {
+		t.Errorf("String() = %q, want %q", identity.String(), "token:ci")
+	}
+
+	if _, err := tokens.Authenticate(context.Background(), "other"); !errors.Is(err, ErrUnknownToken) {
+		t.Errorf("Authenticate(other) error = %v, want %v", err, ErrUnknownToken)
+	}
+}
@@ -0,0 +1,610 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/neovasili/metal-fests/internal/auth"
+	"github.com/neovasili/metal-fests/internal/constants"
+	"github.com/neovasili/metal-fests/internal/logging"
+	"github.com/neovasili/metal-fests/internal/openai"
+	"github.com/neovasili/metal-fests/internal/tracing"
+)
+
+// DefaultFile is the configuration file read when none is given
+const DefaultFile = "metal-fests.yaml"
+
+// Output formats of the commands
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// Verbosity levels of the commands
+const (
+	VerbosityQuiet   = "quiet"
+	VerbosityNormal  = "normal"
+	VerbosityVerbose = "verbose"
+)
+
+// Config holds the settings shared by the server and every command
+type Config struct {
+	Database DatabaseConfig `yaml:"database"`
+	Output   OutputConfig   `yaml:"output"`
+	Server   ServerConfig   `yaml:"server"`
+	AI       AIConfig       `yaml:"ai"`
+	Updaters UpdatersConfig `yaml:"updaters"`
+	Tracing  TracingConfig  `yaml:"tracing"`
+	Log      LogConfig      `yaml:"log"`
+	Auth     AuthConfig     `yaml:"auth"`
+
+	// File is the configuration file the values were read from, if any
+	File string `yaml:"-"`
+}
+
+// DatabaseConfig locates the database file
+type DatabaseConfig struct {
+	Path string `yaml:"path"`
+}
+
+// OutputConfig controls what the commands print
+type OutputConfig struct {
+	Format    string `yaml:"format"`
+	Verbosity string `yaml:"verbosity"`
+}
+
+// ServerConfig configures the HTTP server
+type ServerConfig struct {
+	Port           int           `yaml:"port"`
+	ReadTimeout    time.Duration `yaml:"readTimeout"`
+	WriteTimeout   time.Duration `yaml:"writeTimeout"`
+	IdleTimeout    time.Duration `yaml:"idleTimeout"`
+	MaxHeaderBytes int           `yaml:"maxHeaderBytes"`
+	// MaxBodyBytes bounds the body of every request
+	MaxBodyBytes int `yaml:"maxBodyBytes"`
+	// DrainDelay is how long the server keeps serving once it reports not
+	// ready, so load balancers stop sending it requests before it stops
+	DrainDelay time.Duration `yaml:"drainDelay"`
+	// ShutdownTimeout bounds how long in-flight requests and database
+	// writes get to finish when the server stops
+	ShutdownTimeout time.Duration   `yaml:"shutdownTimeout"`
+	CORS            CORSConfig      `yaml:"cors"`
+	Security        SecurityConfig  `yaml:"security"`
+	TLS             TLSConfig       `yaml:"tls"`
+	RateLimit       RateLimitConfig `yaml:"rateLimit"`
+}
+
+// CORSConfig sets which browser origins can call the server. The admin API
+// has a policy of its own, so opening the public data to any site doesn't
+// open the admin API as well.
+type CORSConfig struct {
+	// Public applies to the pages, the database and the public API routes
+	Public CORSPolicy `yaml:"public"`
+	// Admin applies to the routes of the admin pages
+	Admin CORSPolicy `yaml:"admin"`
+}
+
+// CORSPolicy lists what cross-origin requests may do
+type CORSPolicy struct {
+	// AllowedOrigins holds origins such as "https://metal-fests.com", or "*"
+	// for any. Only same-origin requests are allowed when empty.
+	AllowedOrigins []string `yaml:"allowedOrigins"`
+	AllowedMethods []string `yaml:"allowedMethods"`
+	AllowedHeaders []string `yaml:"allowedHeaders"`
+	// MaxAge is how long browsers may cache the answer to a preflight request
+	MaxAge time.Duration `yaml:"maxAge"`
+}
+
+// SecurityConfig sets the security headers sent with every response
+type SecurityConfig struct {
+	// ContentSecurityPolicy replaces the default policy of the pages when set
+	ContentSecurityPolicy string `yaml:"contentSecurityPolicy"`
+	// FrameAncestors lists the sources allowed to embed the pages, e.g.
+	// "'self'" or "https://metal-fests.com". None can when empty.
+	FrameAncestors []string `yaml:"frameAncestors"`
+	ReferrerPolicy string   `yaml:"referrerPolicy"`
+	// HSTSMaxAge is how long browsers must keep using HTTPS once they reached
+	// the server over it. No Strict-Transport-Security header is sent when 0.
+	HSTSMaxAge time.Duration `yaml:"hstsMaxAge"`
+	// TrustForwardedProto takes requests with "X-Forwarded-Proto: https" as
+	// HTTPS ones, for servers behind a proxy terminating TLS
+	TrustForwardedProto bool `yaml:"trustForwardedProto"`
+}
+
+// TLSConfig makes the server terminate TLS itself
+type TLSConfig struct {
+	CertFile string `yaml:"certFile"`
+	KeyFile  string `yaml:"keyFile"`
+}
+
+// Enabled reports whether the server serves HTTPS
+func (t TLSConfig) Enabled() bool {
+	return t.CertFile != "" && t.KeyFile != ""
+}
+
+// RateLimitConfig sets how many requests each client, told apart by its IP
+// address, may send. Each group of routes has a budget of its own.
+type RateLimitConfig struct {
+	// Public applies to the pages, the database and the API reads
+	Public RateLimit `yaml:"public"`
+	// AdminWrites applies to the edits of festivals and bands
+	AdminWrites RateLimit `yaml:"adminWrites"`
+	// ValidateURL applies to the URL validations, each of which makes an
+	// outbound request
+	ValidateURL RateLimit `yaml:"validateUrl"`
+	// TrustForwardedFor takes the client address from the X-Forwarded-For
+	// header, for servers behind a proxy. Without a proxy adding it, clients
+	// could pick any address and escape their budget.
+	TrustForwardedFor bool `yaml:"trustForwardedFor"`
+}
+
+// RateLimit is a token bucket: a client may send Burst requests at once,
+// then RequestsPerMinute. Requests are not limited when RequestsPerMinute is 0.
+type RateLimit struct {
+	RequestsPerMinute int `yaml:"requestsPerMinute"`
+	Burst             int `yaml:"burst"`
+}
+
+// AIConfig configures the OpenAI requests of the updaters
+type AIConfig struct {
+	PrimaryModel      string        `yaml:"primaryModel"`
+	FallbackModel     string        `yaml:"fallbackModel"`
+	RequestTimeout    time.Duration `yaml:"requestTimeout"`
+	RequestsPerMinute int           `yaml:"requestsPerMinute"`
+}
+
+// UpdatersConfig holds the files of each updater
+type UpdatersConfig struct {
+	Bands     UpdaterFiles   `yaml:"bands"`
+	Festivals UpdaterFiles   `yaml:"festivals"`
+	Discovery DiscoveryFiles `yaml:"discovery"`
+}
+
+// UpdaterFiles locates the prompt an updater reads and the files it writes
+type UpdaterFiles struct {
+	Prompt     string `yaml:"prompt"`
+	Summary    string `yaml:"summary"`
+	Report     string `yaml:"report"`
+	Checkpoint string `yaml:"checkpoint"`
+}
+
+// DiscoveryFiles locates the prompt of the festival discovery and the files
+// it writes. A discovery looks up a single festival, so it keeps no checkpoint.
+type DiscoveryFiles struct {
+	Prompt  string `yaml:"prompt"`
+	Summary string `yaml:"summary"`
+	Report  string `yaml:"report"`
+}
+
+// TracingConfig selects where the OpenTelemetry traces go
+type TracingConfig struct {
+	// Exporter is "none", "stdout" to print the spans, or "otlp" to send
+	// them to a collector over gRPC
+	Exporter string `yaml:"exporter"`
+	// Endpoint is the URL of the OTLP collector, e.g. "http://localhost:4317".
+	// When empty, OTEL_EXPORTER_OTLP_ENDPOINT or the OTLP default is used.
+	Endpoint    string `yaml:"endpoint"`
+	ServiceName string `yaml:"serviceName"`
+}
+
+// Options returns the tracing options of the configuration. The stdout
+// exporter prints to out.
+func (t TracingConfig) Options(out io.Writer) tracing.Options {
+	return tracing.Options{Exporter: t.Exporter, Endpoint: t.Endpoint, ServiceName: t.ServiceName, Out: out}
+}
+
+// LogConfig controls the structured logs
+type LogConfig struct {
+	// Format is "text" for key=value pairs or "json"
+	Format string `yaml:"format"`
+	// Level is the lowest level logged: "debug", "info", "warn" or "error"
+	Level string `yaml:"level"`
+}
+
+// AuthConfig sets who can call the admin API. The API is open when neither
+// tokens nor an OIDC issuer are configured.
+type AuthConfig struct {
+	// Tokens are static API tokens, for scripts and CI, written as
+	// "name:role:sha256" where sha256 is the hex SHA-256 of the token
+	Tokens []string   `yaml:"tokens"`
+	OIDC   OIDCConfig `yaml:"oidc"`
+}
+
+// OIDCConfig trusts the JWT bearer tokens of an OpenID Connect issuer, such
+// as a Cognito user pool
+type OIDCConfig struct {
+	// Issuer must match the iss claim of the tokens. OIDC is off when empty.
+	Issuer string `yaml:"issuer"`
+	// Audience must be in the aud claim of the tokens, when set
+	Audience string `yaml:"audience"`
+	// JWKSURL serves the public keys of the issuer
+	JWKSURL string `yaml:"jwksUrl"`
+	// RolesClaim holds the groups of the caller
+	RolesClaim string `yaml:"rolesClaim"`
+	// GroupRoles maps groups to roles as "group:role", e.g. "AdminEditor:editor"
+	GroupRoles []string `yaml:"groupRoles"`
+}
+
+// Enabled reports whether the admin API requires authentication
+func (a AuthConfig) Enabled() bool {
+	return len(a.Tokens) > 0 || a.OIDC.Issuer != ""
+}
+
+// Authenticator returns the authenticator of the configured tokens and
+// issuer, or nil when the admin API is open
+func (a AuthConfig) Authenticator() (auth.Authenticator, error) {
+	if !a.Enabled() {
+		return nil, nil
+	}
+	var chain auth.Chain
+	if len(a.Tokens) > 0 {
+		tokens, err := auth.ParseTokens(a.Tokens)
+		if err != nil {
+			return nil, fmt.Errorf("auth.tokens: %w", err)
+		}
+		chain = append(chain, tokens)
+	}
+	if a.OIDC.Issuer != "" {
+		groupRoles, err := a.OIDC.groupRoles()
+		if err != nil {
+			return nil, err
+		}
+		chain = append(chain, auth.NewOIDC(auth.OIDCOptions{
+			Issuer:     a.OIDC.Issuer,
+			Audience:   a.OIDC.Audience,
+			JWKSURL:    a.OIDC.JWKSURL,
+			RolesClaim: a.OIDC.RolesClaim,
+			GroupRoles: groupRoles,
+		}))
+	}
+	return chain, nil
+}
+
+// groupRoles parses GroupRoles, returning the default mapping when empty
+func (o OIDCConfig) groupRoles() (map[string]auth.Role, error) {
+	if len(o.GroupRoles) == 0 {
+		return auth.DefaultGroupRoles, nil
+	}
+	roles := make(map[string]auth.Role, len(o.GroupRoles))
+	for _, entry := range o.GroupRoles {
+		group, name, ok := strings.Cut(entry, ":")
+		if !ok || group == "" {
+			return nil, fmt.Errorf("auth.oidc.groupRoles: %q is not written as group:role", entry)
+		}
+		role, err := auth.ParseRole(name)
+		if err != nil {
+			return nil, fmt.Errorf("auth.oidc.groupRoles: %w", err)
+		}
+		roles[group] = role
+	}
+	return roles, nil
+}
+
+// Default returns the configuration used when neither a file nor the
+// environment sets a value
+func Default() Config {
+	return Config{
+		Database: DatabaseConfig{Path: constants.DBFile},
+		Output:   OutputConfig{Format: FormatText, Verbosity: VerbosityNormal},
+		Server: ServerConfig{
+			Port:            constants.PORT,
+			ReadTimeout:     constants.ReadTimeout,
+			WriteTimeout:    constants.WriteTimeout,
+			IdleTimeout:     constants.IdleTimeout,
+			MaxHeaderBytes:  constants.MaxHeaderBytes,
+			MaxBodyBytes:    constants.MaxBodyBytes,
+			ShutdownTimeout: constants.ShutdownTimeout,
+			CORS: CORSConfig{
+				Public: CORSPolicy{
+					AllowedOrigins: []string{"*"},
+					AllowedMethods: []string{http.MethodGet, http.MethodHead},
+					AllowedHeaders: []string{"Content-Type", logging.RequestIDHeader},
+					MaxAge:         10 * time.Minute,
+				},
+				Admin: CORSPolicy{
+					AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodPut},
+					AllowedHeaders: []string{"Authorization", "Content-Type", logging.RequestIDHeader},
+					MaxAge:         10 * time.Minute,
+				},
+			},
+			Security: SecurityConfig{
+				ReferrerPolicy: "strict-origin-when-cross-origin",
+				HSTSMaxAge:     365 * 24 * time.Hour,
+			},
+			RateLimit: RateLimitConfig{
+				Public:      RateLimit{RequestsPerMinute: 600, Burst: 100},
+				AdminWrites: RateLimit{RequestsPerMinute: 60, Burst: 20},
+				ValidateURL: RateLimit{RequestsPerMinute: 30, Burst: 10},
+			},
+		},
+		AI: AIConfig{
+			PrimaryModel:      openai.PrimaryModel,
+			FallbackModel:     openai.FallbackModel,
+			RequestTimeout:    openai.DefaultRequestTimeout,
+			RequestsPerMinute: 60,
+		},
+		Updaters: UpdatersConfig{
+			Bands: UpdaterFiles{
+				Prompt:     "scripts/band_prompt.md",
+				Summary:    "band_update_summary.md",
+				Report:     "band_update_report.json",
+				Checkpoint: "band_update_checkpoint.json",
+			},
+			Festivals: UpdaterFiles{
+				Prompt:     "scripts/festival_prompt.md",
+				Summary:    "festival_update_summary.md",
+				Report:     "festival_update_report.json",
+				Checkpoint: "festival_update_checkpoint.json",
+			},
+			Discovery: DiscoveryFiles{
+				Prompt:  "scripts/festival_discovery_prompt.md",
+				Summary: "festival_discovery_summary.md",
+				Report:  "festival_discovery_report.json",
+			},
+		},
+		Tracing: TracingConfig{Exporter: tracing.ExporterNone, ServiceName: "metal-fests"},
+		Log:     LogConfig{Format: logging.FormatText, Level: "info"},
+		Auth:    AuthConfig{OIDC: OIDCConfig{RolesClaim: auth.DefaultRolesClaim}},
+	}
+}
+
+// Load reads the configuration file at path on top of the defaults, then
+// applies the environment overrides. An empty path reads DefaultFile when it
+// exists.
+func Load(path string) (Config, error) {
+	cfg := Default()
+
+	explicit := path != ""
+	if !explicit {
+		path = DefaultFile
+	}
+	// #nosec G304 -- the configuration path is given by the user
+	content, err := os.ReadFile(path)
+	switch {
+	case errors.Is(err, fs.ErrNotExist) && !explicit:
+	case err != nil:
+		return cfg, err
+	default:
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+			return cfg, fmt.Errorf("%s: %w", path, err)
+		}
+		cfg.File = path
+	}
+
+	if err := applyEnv(&cfg, os.LookupEnv); err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// Validate checks that every value is one the server and the commands
+// understand, and reports all the invalid ones
+func (c Config) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Database.Path == "" {
+		invalid("database.path cannot be empty")
+	}
+	switch c.Output.Format {
+	case FormatText, FormatJSON:
+	default:
+		invalid("output.format must be %q or %q, got %q", FormatText, FormatJSON, c.Output.Format)
+	}
+	switch c.Output.Verbosity {
+	case VerbosityQuiet, VerbosityNormal, VerbosityVerbose:
+	default:
+		invalid("output.verbosity must be %q, %q or %q, got %q", VerbosityQuiet, VerbosityNormal, VerbosityVerbose, c.Output.Verbosity)
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		invalid("server.port must be between 1 and 65535, got %d", c.Server.Port)
+	}
+	for _, timeout := range []struct {
+		name  string
+		value time.Duration
+	}{
+		{"server.readTimeout", c.Server.ReadTimeout},
+		{"server.writeTimeout", c.Server.WriteTimeout},
+		{"server.idleTimeout", c.Server.IdleTimeout},
+		{"server.shutdownTimeout", c.Server.ShutdownTimeout},
+	} {
+		if timeout.value <= 0 {
+			invalid("%s must be positive, got %s", timeout.name, timeout.value)
+		}
+	}
+	if c.Server.DrainDelay < 0 {
+		invalid("server.drainDelay cannot be negative, got %s", c.Server.DrainDelay)
+	}
+	if c.Server.MaxHeaderBytes <= 0 {
+		invalid("server.maxHeaderBytes must be positive, got %d", c.Server.MaxHeaderBytes)
+	}
+	if c.Server.MaxBodyBytes <= 0 {
+		invalid("server.maxBodyBytes must be positive, got %d", c.Server.MaxBodyBytes)
+	}
+	for _, policy := range []struct {
+		name   string
+		policy CORSPolicy
+	}{
+		{"server.cors.public", c.Server.CORS.Public},
+		{"server.cors.admin", c.Server.CORS.Admin},
+	} {
+		for _, origin := range policy.policy.AllowedOrigins {
+			if err := validateOrigin(origin); err != nil {
+				invalid("%s.allowedOrigins: %v", policy.name, err)
+			}
+		}
+		for _, method := range policy.policy.AllowedMethods {
+			if !slices.Contains(corsMethods, method) {
+				invalid("%s.allowedMethods: %q is not one of %s", policy.name, method, strings.Join(corsMethods, ", "))
+			}
+		}
+		for _, header := range policy.policy.AllowedHeaders {
+			if !headerName.MatchString(header) {
+				invalid("%s.allowedHeaders: %q is not a header name", policy.name, header)
+			}
+		}
+		if policy.policy.MaxAge < 0 {
+			invalid("%s.maxAge cannot be negative, got %s", policy.name, policy.policy.MaxAge)
+		}
+	}
+	if slices.Contains(c.Server.CORS.Admin.AllowedOrigins, "*") {
+		invalid("server.cors.admin.allowedOrigins cannot be \"*\", list the origins of the admin pages")
+	}
+	for _, source := range c.Server.Security.FrameAncestors {
+		if err := validateOrigin(source); source != "'self'" && (err != nil || source == "*") {
+			invalid("server.security.frameAncestors: %q is not an origin such as https://example.com", source)
+		}
+	}
+	if c.Server.Security.HSTSMaxAge < 0 {
+		invalid("server.security.hstsMaxAge cannot be negative, got %s", c.Server.Security.HSTSMaxAge)
+	}
+	if (c.Server.TLS.CertFile == "") != (c.Server.TLS.KeyFile == "") {
+		invalid("server.tls needs both a certFile and a keyFile")
+	}
+	for _, budget := range []struct {
+		name  string
+		limit RateLimit
+	}{
+		{"server.rateLimit.public", c.Server.RateLimit.Public},
+		{"server.rateLimit.adminWrites", c.Server.RateLimit.AdminWrites},
+		{"server.rateLimit.validateUrl", c.Server.RateLimit.ValidateURL},
+	} {
+		if budget.limit.RequestsPerMinute < 0 {
+			invalid("%s.requestsPerMinute cannot be negative, got %d", budget.name, budget.limit.RequestsPerMinute)
+		}
+		if budget.limit.RequestsPerMinute > 0 && budget.limit.Burst <= 0 {
+			invalid("%s.burst must be positive, got %d", budget.name, budget.limit.Burst)
+		}
+	}
+
+	if c.AI.PrimaryModel == "" {
+		invalid("ai.primaryModel cannot be empty")
+	}
+	if c.AI.FallbackModel == "" {
+		invalid("ai.fallbackModel cannot be empty")
+	}
+	if c.AI.RequestTimeout < 0 {
+		invalid("ai.requestTimeout cannot be negative, got %s", c.AI.RequestTimeout)
+	}
+	if c.AI.RequestsPerMinute < 0 {
+		invalid("ai.requestsPerMinute cannot be negative, got %d", c.AI.RequestsPerMinute)
+	}
+
+	for _, updater := range []struct {
+		name  string
+		files UpdaterFiles
+	}{
+		{"updaters.bands", c.Updaters.Bands},
+		{"updaters.festivals", c.Updaters.Festivals},
+	} {
+		if updater.files.Prompt == "" || updater.files.Summary == "" || updater.files.Report == "" || updater.files.Checkpoint == "" {
+			invalid("%s needs a prompt, a summary, a report and a checkpoint path", updater.name)
+		}
+	}
+	if c.Updaters.Discovery.Prompt == "" || c.Updaters.Discovery.Summary == "" || c.Updaters.Discovery.Report == "" {
+		invalid("updaters.discovery needs a prompt, a summary and a report path")
+	}
+
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP:
+	default:
+		invalid("tracing.exporter must be %q, %q or %q, got %q", tracing.ExporterNone, tracing.ExporterStdout, tracing.ExporterOTLP, c.Tracing.Exporter)
+	}
+	if c.Tracing.Endpoint != "" {
+		if u, err := url.Parse(c.Tracing.Endpoint); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			invalid("tracing.endpoint %q is not a URL such as http://localhost:4317", c.Tracing.Endpoint)
+		}
+	}
+	if c.Tracing.ServiceName == "" {
+		invalid("tracing.serviceName cannot be empty")
+	}
+
+	switch c.Log.Format {
+	case logging.FormatText, logging.FormatJSON:
+	default:
+		invalid("log.format must be %q or %q, got %q", logging.FormatText, logging.FormatJSON, c.Log.Format)
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(c.Log.Level)); err != nil {
+		invalid("log.level must be \"debug\", \"info\", \"warn\" or \"error\", got %q", c.Log.Level)
+	}
+
+	if _, err := auth.ParseTokens(c.Auth.Tokens); err != nil {
+		invalid("auth.tokens: %v", err)
+	}
+	if oidc := c.Auth.OIDC; oidc.Issuer != "" {
+		if !isSecureURL(oidc.Issuer) {
+			invalid("auth.oidc.issuer %q is not an https URL", oidc.Issuer)
+		}
+		if !isSecureURL(oidc.JWKSURL) {
+			invalid("auth.oidc.jwksUrl %q is not an https URL", oidc.JWKSURL)
+		}
+		if oidc.RolesClaim == "" {
+			invalid("auth.oidc.rolesClaim cannot be empty")
+		}
+		if _, err := oidc.groupRoles(); err != nil {
+			invalid("%v", err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// corsMethods are the methods a CORS policy can allow
+var corsMethods = []string{http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete}
+
+// headerName matches the names of HTTP headers
+var headerName = regexp.MustCompile(`^[A-Za-z0-9!#$%&'*+.^_|~-]+$`)
+
+// validateOrigin accepts "*" or a scheme and host without a path
+func validateOrigin(origin string) error {
+	if origin == "*" {
+		return nil
+	}
+	u, err := url.Parse(origin)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || u.Path != "" || u.RawQuery != "" {
+		return fmt.Errorf("%q is not an origin such as https://example.com", origin)
+	}
+	return nil
+}
+
+// isSecureURL accepts https URLs, and http ones on the loopback interface for
+// an issuer running next to the server in development
+func isSecureURL(value string) bool {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	switch u.Scheme {
+	case "https":
+		return true
+	case "http":
+		host := u.Hostname()
+		if host == "localhost" {
+			return true
+		}
+		ip := net.ParseIP(host)
+		return ip != nil && ip.IsLoopback()
+	default:
+		return false
+	}
+}
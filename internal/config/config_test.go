@@ -0,0 +1,289 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/tracing"
+)
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "metal-fests.yaml")
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("failed to write config: %v", err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		expected Config
+		wantErr  bool
+	}{
+		{
+			name:     "Empty file keeps the defaults",
+			content:  "",
+			expected: Default(),
+		},
+		{
+			name:    "Values override the defaults",
+			content: "database:\n  path: data/db.json\noutput:\n  format: json\nserver:\n  port: 9000\n  readTimeout: 30s\n  cors:\n    public:\n      allowedOrigins: [https://metal-fests.com]\n",
+			expected: func() Config {
+				cfg := Default()
+				cfg.Database.Path = "data/db.json"
+				cfg.Output.Format = FormatJSON
+				cfg.Server.Port = 9000
+				cfg.Server.ReadTimeout = 30 * time.Second
+				cfg.Server.CORS.Public.AllowedOrigins = []string{"https://metal-fests.com"}
+				return cfg
+			}(),
+		},
+		{
+			name:    "Unknown field",
+			content: "databse:\n  path: db.json\n",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid format",
+			content: "output:\n  format: xml\n",
+			wantErr: true,
+		},
+		{
+			name:    "Invalid duration",
+			content: "server:\n  readTimeout: soon\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfig(t, tt.content)
+			cfg, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Load() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			tt.expected.File = path
+			if !tt.wantErr && !reflect.DeepEqual(cfg, tt.expected) {
+				t.Errorf("Load() = %+v, want %+v", cfg, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLoad_DefaultFile(t *testing.T) {
+	t.Chdir(t.TempDir())
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load() without a config file failed: %v", err)
+	}
+	if !reflect.DeepEqual(cfg, Default()) {
+		t.Errorf("Load() = %+v, want the defaults", cfg)
+	}
+
+	if _, err := Load("missing.yaml"); err == nil {
+		t.Error("Load() should fail when the given file does not exist")
+	}
+}
+
+func TestLoad_EnvironmentOverrides(t *testing.T) {
+	path := writeConfig(t, "server:\n  port: 9000\nai:\n  primaryModel: gpt-4.1-mini\n")
+	t.Setenv("METAL_FESTS_SERVER_PORT", "9100")
+	t.Setenv("METAL_FESTS_SERVER_IDLE_TIMEOUT", "2m")
+	t.Setenv("METAL_FESTS_SERVER_CORS_PUBLIC_ALLOWED_ORIGINS", "https://metal-fests.com, http://localhost:8000")
+	t.Setenv("METAL_FESTS_UPDATERS_BANDS_PROMPT", "prompts/bands.md")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load() failed: %v", err)
+	}
+	if cfg.Server.Port != 9100 {
+		t.Errorf("Server.Port = %d, want the environment to win over the file", cfg.Server.Port)
+	}
+	if cfg.Server.IdleTimeout != 2*time.Minute {
+		t.Errorf("Server.IdleTimeout = %v, want 2m", cfg.Server.IdleTimeout)
+	}
+	if origins := cfg.Server.CORS.Public.AllowedOrigins; !reflect.DeepEqual(origins, []string{"https://metal-fests.com", "http://localhost:8000"}) {
+		t.Errorf("Server.CORS.Public.AllowedOrigins = %v", origins)
+	}
+	if cfg.Updaters.Bands.Prompt != "prompts/bands.md" {
+		t.Errorf("Updaters.Bands.Prompt = %q", cfg.Updaters.Bands.Prompt)
+	}
+	if cfg.AI.PrimaryModel != "gpt-4.1-mini" {
+		t.Errorf("AI.PrimaryModel = %q, want the value of the file", cfg.AI.PrimaryModel)
+	}
+
+	t.Setenv("METAL_FESTS_AI_REQUESTS_PER_MINUTE", "many")
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "METAL_FESTS_AI_REQUESTS_PER_MINUTE") {
+		t.Errorf("Load() error = %v, want the invalid variable to be named", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(cfg *Config)
+		errors []string
+	}{
+		{name: "Defaults", modify: func(*Config) {}},
+		{
+			name:   "Port out of range",
+			modify: func(cfg *Config) { cfg.Server.Port = 70000 },
+			errors: []string{"server.port"},
+		},
+		{
+			name:   "Origin with a path",
+			modify: func(cfg *Config) { cfg.Server.CORS.Public.AllowedOrigins = []string{"https://metal-fests.com/admin"} },
+			errors: []string{"server.cors.public.allowedOrigins"},
+		},
+		{
+			name: "Specific origins",
+			modify: func(cfg *Config) {
+				cfg.Server.CORS.Public.AllowedOrigins = []string{"https://metal-fests.com", "http://localhost:8000"}
+			},
+		},
+		{
+			name: "Admin open to any origin, unknown method and header",
+			modify: func(cfg *Config) {
+				cfg.Server.CORS.Admin.AllowedOrigins = []string{"*"}
+				cfg.Server.CORS.Admin.AllowedMethods = []string{"TRACE"}
+				cfg.Server.CORS.Public.AllowedHeaders = []string{"X Bad"}
+			},
+			errors: []string{"server.cors.admin.allowedOrigins", "server.cors.admin.allowedMethods", "server.cors.public.allowedHeaders"},
+		},
+		{
+			name: "TLS with embedding sites",
+			modify: func(cfg *Config) {
+				cfg.Server.TLS = TLSConfig{CertFile: "cert.pem", KeyFile: "key.pem"}
+				cfg.Server.Security.FrameAncestors = []string{"'self'", "https://metal-fests.com"}
+			},
+		},
+		{
+			name: "TLS without key and frame ancestor with a path",
+			modify: func(cfg *Config) {
+				cfg.Server.TLS.CertFile = "cert.pem"
+				cfg.Server.Security.FrameAncestors = []string{"https://metal-fests.com/embed"}
+			},
+			errors: []string{"server.tls", "server.security.frameAncestors"},
+		},
+		{
+			name: "Unlimited public reads",
+			modify: func(cfg *Config) {
+				cfg.Server.RateLimit.Public = RateLimit{}
+			},
+		},
+		{
+			name: "Negative rate, no burst and no body",
+			modify: func(cfg *Config) {
+				cfg.Server.RateLimit.AdminWrites.RequestsPerMinute = -1
+				cfg.Server.RateLimit.ValidateURL.Burst = 0
+				cfg.Server.MaxBodyBytes = 0
+			},
+			errors: []string{"server.rateLimit.adminWrites.requestsPerMinute", "server.rateLimit.validateUrl.burst", "server.maxBodyBytes"},
+		},
+		{
+			name: "OTLP tracing",
+			modify: func(cfg *Config) {
+				cfg.Tracing.Exporter = tracing.ExporterOTLP
+				cfg.Tracing.Endpoint = "http://localhost:4317"
+			},
+		},
+		{
+			name: "Unknown exporter and endpoint without scheme",
+			modify: func(cfg *Config) {
+				cfg.Tracing.Exporter = "jaeger"
+				cfg.Tracing.Endpoint = "localhost:4317"
+			},
+			errors: []string{"tracing.exporter", "tracing.endpoint"},
+		},
+		{
+			name: "Unknown log format and level",
+			modify: func(cfg *Config) {
+				cfg.Log.Format = "logfmt"
+				cfg.Log.Level = "verbose"
+			},
+			errors: []string{"log.format", "log.level"},
+		},
+		{
+			name: "Tokens and OIDC issuer",
+			modify: func(cfg *Config) {
+				cfg.Auth.Tokens = []string{"ci:editor:" + strings.Repeat("ab", 32)}
+				cfg.Auth.OIDC.Issuer = "https://cognito-idp.eu-west-1.amazonaws.com/eu-west-1_abc"
+				cfg.Auth.OIDC.JWKSURL = "https://cognito-idp.eu-west-1.amazonaws.com/eu-west-1_abc/.well-known/jwks.json"
+				cfg.Auth.OIDC.GroupRoles = []string{"Editors:editor"}
+			},
+		},
+		{
+			name: "Local OIDC issuer",
+			modify: func(cfg *Config) {
+				cfg.Auth.OIDC.Issuer = "http://localhost:8080/realms/metal-fests"
+				cfg.Auth.OIDC.JWKSURL = "http://127.0.0.1:8080/realms/metal-fests/protocol/openid-connect/certs"
+			},
+		},
+		{
+			name: "Plain token, insecure issuer and unknown role",
+			modify: func(cfg *Config) {
+				cfg.Auth.Tokens = []string{"ci:editor:secret"}
+				cfg.Auth.OIDC.Issuer = "http://auth.example.com"
+				cfg.Auth.OIDC.GroupRoles = []string{"Editors:owner"}
+			},
+			errors: []string{"auth.tokens", "auth.oidc.issuer", "auth.oidc.jwksUrl", "auth.oidc.groupRoles"},
+		},
+		{
+			name: "Every invalid value is reported",
+			modify: func(cfg *Config) {
+				cfg.Server.WriteTimeout = 0
+				cfg.AI.PrimaryModel = ""
+				cfg.Updaters.Festivals.Prompt = ""
+			},
+			errors: []string{"server.writeTimeout", "ai.primaryModel", "updaters.festivals"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.modify(&cfg)
+			err := cfg.Validate()
+			if (err != nil) != (len(tt.errors) > 0) {
+				t.Fatalf("Validate() error = %v, want errors about %v", err, tt.errors)
+			}
+			for _, key := range tt.errors {
+				if !strings.Contains(err.Error(), key) {
+					t.Errorf("Validate() error = %v, want it to mention %s", err, key)
+				}
+			}
+		})
+	}
+}
+
+func TestEnvVars(t *testing.T) {
+	cfg := Default()
+	cfg.Server.CORS.Public.AllowedOrigins = []string{"https://a.example", "https://b.example"}
+
+	expected := map[string]string{
+		"METAL_FESTS_DATABASE_PATH":                      "db.json",
+		"METAL_FESTS_SERVER_READ_TIMEOUT":                "10s",
+		"METAL_FESTS_SERVER_CORS_PUBLIC_ALLOWED_ORIGINS": "https://a.example,https://b.example",
+		"METAL_FESTS_AI_REQUESTS_PER_MINUTE":             "60",
+		"METAL_FESTS_UPDATERS_DISCOVERY_REPORT":          "festival_discovery_report.json",
+	}
+	found := make(map[string]string)
+	for _, v := range cfg.EnvVars() {
+		found[v.Name] = v.Value
+	}
+	for name, value := range expected {
+		if found[name] != value {
+			t.Errorf("%s = %q, want %q", name, found[name], value)
+		}
+	}
+	if _, ok := found["METAL_FESTS_FILE"]; ok {
+		t.Error("the configuration file path should not be a setting")
+	}
+}
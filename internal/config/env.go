@@ -0,0 +1,154 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// EnvPrefix starts the environment variables overriding the configuration.
+// A setting's variable is its path in the file in upper snake case, e.g.
+// METAL_FESTS_SERVER_PORT for server.port and
+// METAL_FESTS_SERVER_CORS_ALLOWED_ORIGINS for server.cors.allowedOrigins.
+// Lists are comma separated.
+const EnvPrefix = "METAL_FESTS_"
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// EnvVar describes the environment variable overriding a setting
+type EnvVar struct {
+	Name string `json:"name"`
+	// Key is the path of the setting in the file, e.g. "server.port"
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+// EnvVars lists the environment variable of every setting, with its
+// effective value, in file order
+func (c Config) EnvVars() []EnvVar {
+	settings := c.settings()
+	vars := make([]EnvVar, 0, len(settings))
+	for _, s := range settings {
+		vars = append(vars, EnvVar{Name: s.env, Key: s.key, Value: formatValue(s.value)})
+	}
+	return vars
+}
+
+// setting is a single value of the configuration
+type setting struct {
+	key   string
+	env   string
+	value reflect.Value
+}
+
+// settings lists every value of the configuration in file order. The values
+// can be set when the configuration is addressable.
+func (c *Config) settings() []setting {
+	var settings []setting
+	collectSettings(reflect.ValueOf(c).Elem(), nil, &settings)
+	return settings
+}
+
+func collectSettings(v reflect.Value, path []string, settings *[]setting) {
+	for i := 0; i < v.NumField(); i++ {
+		name, _, _ := strings.Cut(v.Type().Field(i).Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+		field := v.Field(i)
+		keys := append(append([]string(nil), path...), name)
+		if field.Kind() == reflect.Struct {
+			collectSettings(field, keys, settings)
+			continue
+		}
+		*settings = append(*settings, setting{key: strings.Join(keys, "."), env: envName(keys), value: field})
+	}
+}
+
+// envName turns the keys of a setting into its environment variable
+func envName(keys []string) string {
+	var name strings.Builder
+	name.WriteString(EnvPrefix)
+	for i, key := range keys {
+		if i > 0 {
+			name.WriteByte('_')
+		}
+		for j, r := range key {
+			if unicode.IsUpper(r) && j > 0 {
+				name.WriteByte('_')
+			}
+			name.WriteRune(unicode.ToUpper(r))
+		}
+	}
+	return name.String()
+}
+
+// applyEnv overrides the settings whose environment variable is set
+func applyEnv(cfg *Config, lookup func(string) (string, bool)) error {
+	for _, s := range cfg.settings() {
+		value, ok := lookup(s.env)
+		if !ok {
+			continue
+		}
+		if err := setValue(s.value, value); err != nil {
+			return fmt.Errorf("%s: %w", s.env, err)
+		}
+	}
+	return nil
+}
+
+func setValue(field reflect.Value, value string) error {
+	value = strings.TrimSpace(value)
+	switch {
+	case field.Type() == durationType:
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+	case field.Kind() == reflect.String:
+		field.SetString(value)
+	case field.Kind() == reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a number", value)
+		}
+		field.SetInt(int64(n))
+	case field.Kind() == reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("%q is not a boolean", value)
+		}
+		field.SetBool(b)
+	case field.Kind() == reflect.Slice && field.Type().Elem().Kind() == reflect.String:
+		var items []string
+		for _, item := range strings.Split(value, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		field.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", field.Type())
+	}
+	return nil
+}
+
+// formatValue renders a setting the way its environment variable is written
+func formatValue(field reflect.Value) string {
+	switch {
+	case field.Type() == durationType:
+		return time.Duration(field.Int()).String()
+	case field.Kind() == reflect.Slice:
+		items := make([]string, field.Len())
+		for i := range items {
+			items[i] = fmt.Sprint(field.Index(i).Interface())
+		}
+		return strings.Join(items, ",")
+	default:
+		return fmt.Sprint(field.Interface())
+	}
+}
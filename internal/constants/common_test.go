@@ -12,6 +12,9 @@ func TestConstantsValues(t *testing.T) {
 	if DBFile != "db.json" {
 		t.Errorf("expected DBFile 'db.json', got %s", DBFile)
 	}
+	if PendingFile != "pending_review.json" {
+		t.Errorf("expected PendingFile 'pending_review.json', got %s", PendingFile)
+	}
 	if ReadTimeout != 10*time.Second {
 		t.Errorf("expected ReadTimeout 10s, got %v", ReadTimeout)
 	}
@@ -21,7 +24,13 @@ func TestConstantsValues(t *testing.T) {
 	if IdleTimeout != 60*time.Second {
 		t.Errorf("expected IdleTimeout 60s, got %v", IdleTimeout)
 	}
+	if ShutdownTimeout != 15*time.Second {
+		t.Errorf("expected ShutdownTimeout 15s, got %v", ShutdownTimeout)
+	}
 	if MaxHeaderBytes != 1<<20 {
 		t.Errorf("expected MaxHeaderBytes 1<<20, got %d", MaxHeaderBytes)
 	}
+	if MaxBodyBytes != 1<<20 {
+		t.Errorf("expected MaxBodyBytes 1<<20, got %d", MaxBodyBytes)
+	}
 }
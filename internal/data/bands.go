@@ -1,8 +1,10 @@
 package data
 
 import (
+	"context"
 	"fmt"
 	"regexp"
+	"slices"
 	"strings"
 
 	"golang.org/x/text/cases"
@@ -11,16 +13,39 @@ import (
 	"github.com/neovasili/metal-fests/internal/model"
 )
 
-func GetBands() ([]model.Band, error) {
-	db, err := readDatabase()
+func GetBands(ctx context.Context) ([]model.Band, error) {
+	db, err := readDatabase(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return db.Bands, nil
 }
 
-func AddBandToDatabase(newBand model.Band) error {
-	db, err := readDatabase()
+// GetBand returns the band stored under the given key
+func GetBand(ctx context.Context, key string) (*model.Band, error) {
+	db, err := readDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, band := range db.Bands {
+		if band.Key == key {
+			return &band, nil
+		}
+	}
+	return nil, fmt.Errorf("band %q: %w", key, ErrNotFound)
+}
+
+func AddBandToDatabase(ctx context.Context, newBand model.Band) error {
+	if err := requireKey(newBand.Key); err != nil {
+		return err
+	}
+	endWrite, err := beginWrite()
+	if err != nil {
+		return err
+	}
+	defer endWrite()
+
+	db, err := readDatabase(ctx)
 	if err != nil {
 		return err
 	}
@@ -28,13 +53,13 @@ func AddBandToDatabase(newBand model.Band) error {
 	// Check if band already exists
 	for _, band := range db.Bands {
 		if band.Key == newBand.Key {
-			return fmt.Errorf("band already exists")
+			return fmt.Errorf("band %q: %w", newBand.Key, ErrConflict)
 		}
 	}
 
 	db.Bands = append(db.Bands, newBand)
 
-	err = writeDatabase(db)
+	err = writeDatabase(ctx, db)
 	if err != nil {
 		return err
 	}
@@ -42,8 +67,17 @@ func AddBandToDatabase(newBand model.Band) error {
 	return nil
 }
 
-func UpdateBandInDatabase(updatedBand model.Band) error {
-	db, err := readDatabase()
+func UpdateBandInDatabase(ctx context.Context, updatedBand model.Band) error {
+	if err := requireKey(updatedBand.Key); err != nil {
+		return err
+	}
+	endWrite, err := beginWrite()
+	if err != nil {
+		return err
+	}
+	defer endWrite()
+
+	db, err := readDatabase(ctx)
 	if err != nil {
 		return err
 	}
@@ -58,10 +92,10 @@ func UpdateBandInDatabase(updatedBand model.Band) error {
 	}
 
 	if !bandFound {
-		return fmt.Errorf("band not found")
+		return fmt.Errorf("band %q: %w", updatedBand.Key, ErrNotFound)
 	}
 
-	err = writeDatabase(db)
+	err = writeDatabase(ctx, db)
 	if err != nil {
 		return err
 	}
@@ -69,8 +103,10 @@ func UpdateBandInDatabase(updatedBand model.Band) error {
 	return nil
 }
 
-func CollectAllFestivalBands() ([]model.BandRef, error) {
-	db, err := readDatabase()
+// CollectAllFestivalBands returns every band playing a festival once, sorted
+// by key so the updaters process and report them in the same order each run
+func CollectAllFestivalBands(ctx context.Context) ([]model.BandRef, error) {
+	db, err := readDatabase(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -89,6 +125,9 @@ func CollectAllFestivalBands() ([]model.BandRef, error) {
 	for _, bandRef := range bandSet {
 		bands = append(bands, bandRef)
 	}
+	slices.SortFunc(bands, func(a, b model.BandRef) int {
+		return strings.Compare(a.Key, b.Key)
+	})
 
 	return bands, nil
 }
@@ -119,3 +158,17 @@ func GenerateBandKey(bandName string) string {
 
 	return key
 }
+
+// IsBandComplete tells whether every field of the band is filled in
+func IsBandComplete(band model.Band) bool {
+	if band.Key == "" || band.Name == "" || band.Country == "" || band.Description == "" {
+		return false
+	}
+	if band.HeadlineImage == "" || band.Logo == "" || band.Website == "" || band.Spotify == "" {
+		return false
+	}
+	if len(band.Genres) == 0 || len(band.Members) == 0 {
+		return false
+	}
+	return true
+}
@@ -1,7 +1,9 @@
 package data
 
 import (
+	"context"
 	"os"
+	"slices"
 	"testing"
 
 	"github.com/neovasili/metal-fests/internal/model"
@@ -20,12 +22,12 @@ func TestAddAndGetBands(t *testing.T) {
 	}()
 
 	band := model.Band{Key: "testkey", Name: "Test Band"}
-	err := AddBandToDatabase(band)
+	err := AddBandToDatabase(context.Background(), band)
 	if err != nil {
 		t.Fatalf("AddBandToDatabase failed: %v", err)
 	}
 
-	bands, err := GetBands()
+	bands, err := GetBands(context.Background())
 	if err != nil {
 		t.Fatalf("GetBands failed: %v", err)
 	}
@@ -33,3 +35,167 @@ func TestAddAndGetBands(t *testing.T) {
 		t.Errorf("expected band with key 'testkey', got %+v", bands)
 	}
 }
+
+func TestGetBand(t *testing.T) {
+	tempFile := "test_db_get_band.json"
+	if err := os.WriteFile(tempFile, []byte(`{"bands":[{"key":"testkey","name":"Test Band"}],"festivals":[]}`), 0600); err != nil {
+		t.Fatalf("failed to create test db: %v", err)
+	}
+	oldPath := SetDBFilePathForTesting(tempFile)
+	defer func() {
+		SetDBFilePathForTesting(oldPath)
+		_ = os.Remove(tempFile)
+	}()
+
+	band, err := GetBand(context.Background(), "testkey")
+	if err != nil {
+		t.Fatalf("GetBand failed: %v", err)
+	}
+	if band.Name != "Test Band" {
+		t.Errorf("expected band 'Test Band', got %+v", band)
+	}
+
+	if _, err := GetBand(context.Background(), "missing"); err == nil {
+		t.Error("expected error for missing band")
+	}
+}
+
+func TestCollectAllFestivalBands(t *testing.T) {
+	tempFile := "test_db_festival_bands.json"
+	testData := `{"bands":[],"festivals":[
+		{"key":"hellfest","name":"Hellfest","bands":[{"key":"slayer","name":"Slayer"},{"key":"gojira","name":"Gojira"}]},
+		{"key":"wacken","name":"Wacken","bands":[{"key":"metallica","name":"Metallica"},{"key":"gojira","name":"Gojira"},{"key":"amon-amarth","name":"Amon Amarth"}]}
+	]}`
+	if err := os.WriteFile(tempFile, []byte(testData), 0600); err != nil {
+		t.Fatalf("failed to create test db: %v", err)
+	}
+	oldPath := SetDBFilePathForTesting(tempFile)
+	defer func() {
+		SetDBFilePathForTesting(oldPath)
+		_ = os.Remove(tempFile)
+	}()
+
+	bands, err := CollectAllFestivalBands(context.Background())
+	if err != nil {
+		t.Fatalf("CollectAllFestivalBands failed: %v", err)
+	}
+	keys := make([]string, len(bands))
+	for i, band := range bands {
+		keys[i] = band.Key
+	}
+	expected := []string{"amon-amarth", "gojira", "metallica", "slayer"}
+	if !slices.Equal(keys, expected) {
+		t.Errorf("expected bands %v, got %v", expected, keys)
+	}
+}
+
+func TestIsBandComplete(t *testing.T) {
+	completeBand := model.Band{
+		Key:           "metallica",
+		Name:          "Metallica",
+		Country:       "USA",
+		Description:   "American heavy metal band",
+		HeadlineImage: "https://example.com/image.jpg",
+		Logo:          "https://example.com/logo.png",
+		Website:       "https://metallica.com",
+		Spotify:       "https://open.spotify.com/artist/123",
+		Genres:        []string{"Heavy Metal", "Thrash Metal"},
+		Members: []model.Member{
+			{Name: "James Hetfield", Role: "Vocals"},
+		},
+	}
+
+	tests := []struct {
+		name     string
+		band     model.Band
+		expected bool
+	}{
+		{
+			name:     "Complete band",
+			band:     completeBand,
+			expected: true,
+		},
+		{
+			name: "Missing key",
+			band: model.Band{
+				Name:          "Metallica",
+				Country:       "USA",
+				Description:   "American heavy metal band",
+				HeadlineImage: "https://example.com/image.jpg",
+				Logo:          "https://example.com/logo.png",
+				Website:       "https://metallica.com",
+				Spotify:       "https://open.spotify.com/artist/123",
+				Genres:        []string{"Heavy Metal"},
+				Members: []model.Member{
+					{Name: "James Hetfield", Role: "Vocals"},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "Missing name",
+			band: model.Band{
+				Key:           "metallica",
+				Country:       "USA",
+				Description:   "American heavy metal band",
+				HeadlineImage: "https://example.com/image.jpg",
+				Logo:          "https://example.com/logo.png",
+				Website:       "https://metallica.com",
+				Spotify:       "https://open.spotify.com/artist/123",
+				Genres:        []string{"Heavy Metal"},
+				Members: []model.Member{
+					{Name: "James Hetfield", Role: "Vocals"},
+				},
+			},
+			expected: false,
+		},
+		{
+			name:     "Empty band",
+			band:     model.Band{},
+			expected: false,
+		},
+		{
+			name: "Empty genres",
+			band: model.Band{
+				Key:           "metallica",
+				Name:          "Metallica",
+				Country:       "USA",
+				Description:   "American heavy metal band",
+				HeadlineImage: "https://example.com/image.jpg",
+				Logo:          "https://example.com/logo.png",
+				Website:       "https://metallica.com",
+				Spotify:       "https://open.spotify.com/artist/123",
+				Genres:        []string{},
+				Members: []model.Member{
+					{Name: "James Hetfield", Role: "Vocals"},
+				},
+			},
+			expected: false,
+		},
+		{
+			name: "Empty members",
+			band: model.Band{
+				Key:           "metallica",
+				Name:          "Metallica",
+				Country:       "USA",
+				Description:   "American heavy metal band",
+				HeadlineImage: "https://example.com/image.jpg",
+				Logo:          "https://example.com/logo.png",
+				Website:       "https://metallica.com",
+				Spotify:       "https://open.spotify.com/artist/123",
+				Genres:        []string{"Heavy Metal"},
+				Members:       []model.Member{},
+			},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := IsBandComplete(tt.band)
+			if result != tt.expected {
+				t.Errorf("IsBandComplete() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
@@ -1,18 +1,97 @@
 package data
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/neovasili/metal-fests/internal/constants"
+	"github.com/neovasili/metal-fests/internal/metrics"
 	"github.com/neovasili/metal-fests/internal/model"
+	"github.com/neovasili/metal-fests/internal/tracing"
 )
 
 // dbFilePath allows overriding the database file path for testing
 var dbFilePath = constants.DBFile
 
+// SetDBFilePath points the data layer at another database file
+func SetDBFilePath(path string) {
+	dbFilePath = path
+}
+
+// DBFilePath returns the path of the database file in use
+func DBFilePath() string {
+	return dbFilePath
+}
+
+// ErrClosed is returned by writes attempted after Close
+var ErrClosed = errors.New("database is closed")
+
+var (
+	// writeMu serializes the read-modify-write cycles of the database and the
+	// pending-review queue, so concurrent saves don't overwrite each other
+	writeMu sync.Mutex
+	closed  atomic.Bool
+)
+
+// beginWrite waits for the write in progress, if any, and returns the
+// function ending this one
+func beginWrite() (func(), error) {
+	writeMu.Lock()
+	if closed.Load() {
+		writeMu.Unlock()
+		return nil, ErrClosed
+	}
+	return writeMu.Unlock, nil
+}
+
+// Close rejects new writes and waits for the one in progress to reach the
+// disk. It gives up waiting when ctx is done.
+func Close(ctx context.Context) error {
+	closed.Store(true)
+	done := make(chan struct{})
+	go func() {
+		writeMu.Lock()
+		writeMu.Unlock()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Ping reports whether the database file can be read and parsed. It fails
+// with ErrClosed once Close was called.
+func Ping(ctx context.Context) error {
+	if closed.Load() {
+		return ErrClosed
+	}
+	_, err := readDatabase(ctx)
+	return err
+}
+
+// GetDatabase returns every festival and band in the database
+func GetDatabase(ctx context.Context) (*model.Database, error) {
+	return readDatabase(ctx)
+}
+
 // Read current database
-func readDatabase() (*model.Database, error) {
+func readDatabase(ctx context.Context) (_ *model.Database, err error) {
+	done := observe(ctx, "database", "read")
+	defer func() { done(err) }()
+
 	// #nosec G304 - dbFilePath is controlled and can be overridden for testing
 	dbData, err := os.ReadFile(dbFilePath)
 	if err != nil {
@@ -27,7 +106,10 @@ func readDatabase() (*model.Database, error) {
 }
 
 // Write updated data back to database
-func writeDatabase(db *model.Database) error {
+func writeDatabase(ctx context.Context, db *model.Database) (err error) {
+	done := observe(ctx, "database", "write")
+	defer func() { done(err) }()
+
 	updatedData, err := json.MarshalIndent(db, "", "  ")
 	if err != nil {
 		return err
@@ -36,8 +118,43 @@ func writeDatabase(db *model.Database) error {
 	// Add trailing newline
 	updatedData = append(updatedData, '\n')
 
-	if err := os.WriteFile(dbFilePath, updatedData, 0600); err != nil {
+	return writeFileAtomic(dbFilePath, updatedData)
+}
+
+// observe times and traces a read or write of a database file, and returns
+// the function recording its outcome
+func observe(ctx context.Context, file, operation string) func(error) {
+	start := time.Now()
+	_, span := tracing.Tracer().Start(ctx, "data."+operation+" "+file, trace.WithAttributes(
+		semconv.DBOperationName(operation),
+		semconv.DBCollectionName(file),
+	))
+	return func(err error) {
+		_ = metrics.ObserveDB(file, operation, start, err)
+		tracing.End(span, err)
+	}
+}
+
+// writeFileAtomic replaces the file at path with content. The content goes
+// to a temporary file renamed over path, so an interrupted write never
+// leaves a truncated file behind.
+func writeFileAtomic(path string, content []byte) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(file.Name()) }()
+
+	if _, err := file.Write(content); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		_ = file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
 		return err
 	}
-	return nil
+	return os.Rename(file.Name(), path)
 }
@@ -1,8 +1,12 @@
 package data
 
 import (
+	"context"
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/neovasili/metal-fests/internal/model"
 )
@@ -18,18 +22,18 @@ func TestReadAndWriteDatabase(t *testing.T) {
 		_ = os.Remove(tempFile)
 	}()
 
-	db, err := readDatabase()
+	db, err := readDatabase(context.Background())
 	if err != nil {
 		t.Fatalf("readDatabase failed: %v", err)
 	}
 
 	db.Bands = append(db.Bands, model.Band{Key: "b1", Name: "Band 1"})
-	err = writeDatabase(db)
+	err = writeDatabase(context.Background(), db)
 	if err != nil {
 		t.Fatalf("writeDatabase failed: %v", err)
 	}
 
-	db2, err := readDatabase()
+	db2, err := readDatabase(context.Background())
 	if err != nil {
 		t.Fatalf("readDatabase after write failed: %v", err)
 	}
@@ -37,3 +41,86 @@ func TestReadAndWriteDatabase(t *testing.T) {
 		t.Errorf("expected band 'b1', got %+v", db2.Bands)
 	}
 }
+
+func TestWriteDatabase_LeavesNoTemporaryFile(t *testing.T) {
+	dir := t.TempDir()
+	oldPath := SetDBFilePathForTesting(filepath.Join(dir, "db.json"))
+	defer SetDBFilePathForTesting(oldPath)
+
+	if err := writeDatabase(context.Background(), &model.Database{Bands: []model.Band{{Key: "b1", Name: "Band 1"}}}); err != nil {
+		t.Fatalf("writeDatabase failed: %v", err)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("failed to list directory: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Name() != "db.json" {
+		t.Errorf("expected only db.json in the directory, got %v", entries)
+	}
+}
+
+func TestPing(t *testing.T) {
+	dir := t.TempDir()
+	valid := filepath.Join(dir, "db.json")
+	if err := os.WriteFile(valid, []byte(`{"bands":[],"festivals":[]}`), 0600); err != nil {
+		t.Fatalf("failed to create test db: %v", err)
+	}
+	corrupt := filepath.Join(dir, "corrupt.json")
+	if err := os.WriteFile(corrupt, []byte(`{"bands":[`), 0600); err != nil {
+		t.Fatalf("failed to create corrupt db: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		path    string
+		wantErr bool
+	}{
+		{name: "Valid database", path: valid},
+		{name: "Corrupt database", path: corrupt, wantErr: true},
+		{name: "Missing database", path: filepath.Join(dir, "missing.json"), wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oldPath := SetDBFilePathForTesting(tt.path)
+			defer SetDBFilePathForTesting(oldPath)
+			if err := Ping(context.Background()); (err != nil) != tt.wantErr {
+				t.Errorf("Ping() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClose(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "db.json")
+	if err := os.WriteFile(path, []byte(`{"bands":[],"festivals":[]}`), 0600); err != nil {
+		t.Fatalf("failed to create test db: %v", err)
+	}
+	oldPath := SetDBFilePathForTesting(path)
+	defer func() {
+		SetDBFilePathForTesting(oldPath)
+		ReopenForTesting()
+	}()
+
+	// A write in progress holds Close back until it ends
+	endWrite, err := beginWrite()
+	if err != nil {
+		t.Fatalf("beginWrite failed: %v", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := Close(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Close() during a write = %v, want the deadline to expire", err)
+	}
+	endWrite()
+
+	if err := Close(context.Background()); err != nil {
+		t.Fatalf("Close() failed: %v", err)
+	}
+	if err := AddBandToDatabase(context.Background(), model.Band{Key: "b1", Name: "Band 1"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("AddBandToDatabase(context.Background()) after Close = %v, want ErrClosed", err)
+	}
+	if _, err := GetBands(context.Background()); err != nil {
+		t.Errorf("GetBands(context.Background()) after Close failed: %v", err)
+	}
+}
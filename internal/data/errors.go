@@ -0,0 +1,49 @@
+package data
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Errors of the data layer, to be matched with errors.Is. The returned errors
+// wrap them with the record they are about.
+var (
+	// ErrNotFound is returned when no record has the requested key
+	ErrNotFound = errors.New("not found")
+	// ErrConflict is returned when a record with the same key already exists
+	ErrConflict = errors.New("already exists")
+	// ErrValidation is matched by a ValidationError
+	ErrValidation = errors.New("validation failed")
+)
+
+// ValidationError lists every field that makes a record invalid
+type ValidationError struct {
+	Fields []FieldError
+}
+
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		problems[i] = field.Error()
+	}
+	return fmt.Sprintf("%v: %s", ErrValidation, strings.Join(problems, "; "))
+}
+
+// Is makes errors.Is(err, ErrValidation) match any ValidationError
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// NewValidationError returns a ValidationError for a single field
+func NewValidationError(field, message string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+// requireKey rejects records saved without a key, which could never be found again
+func requireKey(key string) error {
+	if key == "" {
+		return NewValidationError("key", "is required")
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package data
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+func TestWriteErrors(t *testing.T) {
+	tempFile := "test_db_write_errors.json"
+	if err := os.WriteFile(tempFile, []byte(`{"bands":[{"key":"gojira","name":"Gojira"}],"festivals":[]}`), 0600); err != nil {
+		t.Fatalf("failed to create test db: %v", err)
+	}
+	oldPath := SetDBFilePathForTesting(tempFile)
+	defer func() {
+		SetDBFilePathForTesting(oldPath)
+		_ = os.Remove(tempFile)
+	}()
+
+	ctx := context.Background()
+	tests := []struct {
+		name  string
+		write func() error
+		want  error
+	}{
+		{name: "Add an existing band", write: func() error { return AddBandToDatabase(ctx, model.Band{Key: "gojira"}) }, want: ErrConflict},
+		{name: "Add a band without key", write: func() error { return AddBandToDatabase(ctx, model.Band{Name: "Nameless"}) }, want: ErrValidation},
+		{name: "Update a missing band", write: func() error { return UpdateBandInDatabase(ctx, model.Band{Key: "missing"}) }, want: ErrNotFound},
+		{name: "Update a band without key", write: func() error { return UpdateBandInDatabase(ctx, model.Band{}) }, want: ErrValidation},
+		{name: "Update a missing festival", write: func() error { return UpdateFestivalInDatabase(ctx, model.Festival{Key: "missing"}) }, want: ErrNotFound},
+		{name: "Update a festival without key", write: func() error { return UpdateFestivalInDatabase(ctx, model.Festival{}) }, want: ErrValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.write(); !errors.Is(err, tt.want) {
+				t.Errorf("write error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+
+	if _, err := GetBand(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetBand() error = %v, want %v", err, ErrNotFound)
+	}
+	if _, err := GetFestival(ctx, "missing"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetFestival() error = %v, want %v", err, ErrNotFound)
+	}
+}
+
+func TestValidationError(t *testing.T) {
+	err := error(&ValidationError{Fields: []FieldError{
+		{Field: "key", Message: "is required"},
+		{Field: "name", Message: "must be at least 2 characters"},
+	}})
+
+	want := "validation failed: key: is required; name: must be at least 2 characters"
+	if err.Error() != want {
+		t.Errorf("Error() = %q, want %q", err.Error(), want)
+	}
+
+	var validation *ValidationError
+	if !errors.As(err, &validation) || len(validation.Fields) != 2 {
+		t.Errorf("errors.As() did not find the fields of %v", err)
+	}
+	if errors.Is(err, ErrNotFound) {
+		t.Errorf("errors.Is(%v, ErrNotFound) = true, want false", err)
+	}
+}
@@ -1,21 +1,45 @@
 package data
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/neovasili/metal-fests/internal/model"
 )
 
-func GetFestivals() ([]model.Festival, error) {
-	db, err := readDatabase()
+func GetFestivals(ctx context.Context) ([]model.Festival, error) {
+	db, err := readDatabase(ctx)
 	if err != nil {
 		return nil, err
 	}
 	return db.Festivals, nil
 }
 
-func UpdateFestivalInDatabase(updatedFestival model.Festival) error {
-	db, err := readDatabase()
+// GetFestival returns the festival stored under the given key
+func GetFestival(ctx context.Context, key string) (*model.Festival, error) {
+	db, err := readDatabase(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, festival := range db.Festivals {
+		if festival.Key == key {
+			return &festival, nil
+		}
+	}
+	return nil, fmt.Errorf("festival %q: %w", key, ErrNotFound)
+}
+
+func UpdateFestivalInDatabase(ctx context.Context, updatedFestival model.Festival) error {
+	if err := requireKey(updatedFestival.Key); err != nil {
+		return err
+	}
+	endWrite, err := beginWrite()
+	if err != nil {
+		return err
+	}
+	defer endWrite()
+
+	db, err := readDatabase(ctx)
 	if err != nil {
 		return err
 	}
@@ -30,10 +54,10 @@ func UpdateFestivalInDatabase(updatedFestival model.Festival) error {
 	}
 
 	if !festivalFound {
-		return fmt.Errorf("festival not found")
+		return fmt.Errorf("festival %q: %w", updatedFestival.Key, ErrNotFound)
 	}
 
-	err = writeDatabase(db)
+	err = writeDatabase(ctx, db)
 	if err != nil {
 		return err
 	}
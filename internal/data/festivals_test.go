@@ -1,7 +1,9 @@
 package data
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
 	"testing"
@@ -29,7 +31,7 @@ func TestGetFestivals(t *testing.T) {
 	originalDBFile := SetDBFilePathForTesting(dbFile)
 	defer SetDBFilePathForTesting(originalDBFile)
 
-	festivals, err := GetFestivals()
+	festivals, err := GetFestivals(context.Background())
 	if err != nil {
 		t.Fatalf("GetFestivals failed: %v", err)
 	}
@@ -41,6 +43,28 @@ func TestGetFestivals(t *testing.T) {
 	}
 }
 
+func TestGetFestival(t *testing.T) {
+	tempDir := t.TempDir()
+	dbFile := filepath.Join(tempDir, "db.json")
+	if err := os.WriteFile(dbFile, []byte(`{"festivals":[{"key":"wacken-2026","name":"Wacken Open Air"}],"bands":[]}`), 0600); err != nil {
+		t.Fatalf("Failed to write test database: %v", err)
+	}
+	originalDBFile := SetDBFilePathForTesting(dbFile)
+	defer SetDBFilePathForTesting(originalDBFile)
+
+	festival, err := GetFestival(context.Background(), "wacken-2026")
+	if err != nil {
+		t.Fatalf("GetFestival failed: %v", err)
+	}
+	if festival.Name != "Wacken Open Air" {
+		t.Errorf("Expected festival 'Wacken Open Air', got %q", festival.Name)
+	}
+
+	if _, err := GetFestival(context.Background(), "missing"); err == nil {
+		t.Error("Expected error for missing festival")
+	}
+}
+
 func TestUpdateFestivalInDatabase(t *testing.T) {
 	tempDir := t.TempDir()
 	dbFile := filepath.Join(tempDir, "db.json")
@@ -71,12 +95,12 @@ func TestUpdateFestivalInDatabase(t *testing.T) {
 		Location:    "Wacken, Germany",
 		TicketPrice: 299.99,
 	}
-	err = UpdateFestivalInDatabase(updatedFestival)
+	err = UpdateFestivalInDatabase(context.Background(), updatedFestival)
 	if err != nil {
 		t.Fatalf("UpdateFestivalInDatabase failed: %v", err)
 	}
 
-	festivals, err := GetFestivals()
+	festivals, err := GetFestivals(context.Background())
 	if err != nil {
 		t.Fatalf("GetFestivals failed: %v", err)
 	}
@@ -112,13 +136,9 @@ func TestUpdateFestivalInDatabase_NotFound(t *testing.T) {
 		Key:  "non-existent",
 		Name: "Non Existent Festival",
 	}
-	err = UpdateFestivalInDatabase(updatedFestival)
-	if err == nil {
-		t.Errorf("Expected error when updating non-existent festival, got nil")
-	}
-	expectedError := "festival not found"
-	if err.Error() != expectedError {
-		t.Errorf("Expected error %q, got %q", expectedError, err.Error())
+	err = UpdateFestivalInDatabase(context.Background(), updatedFestival)
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound when updating non-existent festival, got %v", err)
 	}
 }
 
@@ -128,7 +148,7 @@ func TestGetFestivals_FileNotFound(t *testing.T) {
 	originalDBFile := SetDBFilePathForTesting(dbFile)
 	defer SetDBFilePathForTesting(originalDBFile)
 
-	_, err := GetFestivals()
+	_, err := GetFestivals(context.Background())
 	if err == nil {
 		t.Errorf("Expected error when database file not found, got nil")
 	}
@@ -148,7 +168,7 @@ func TestUpdateFestivalInDatabase_InvalidJSON(t *testing.T) {
 		Key:  "test",
 		Name: "Test Festival",
 	}
-	err := UpdateFestivalInDatabase(updatedFestival)
+	err := UpdateFestivalInDatabase(context.Background(), updatedFestival)
 	if err == nil {
 		t.Errorf("Expected error when reading invalid JSON, got nil")
 	}
@@ -191,12 +211,12 @@ func TestUpdateFestivalInDatabase_WithBandSizes(t *testing.T) {
 			{Key: "band3", Name: "Band Three", Size: 1},
 		},
 	}
-	err = UpdateFestivalInDatabase(updatedFestival)
+	err = UpdateFestivalInDatabase(context.Background(), updatedFestival)
 	if err != nil {
 		t.Fatalf("UpdateFestivalInDatabase failed: %v", err)
 	}
 
-	festivals, err := GetFestivals()
+	festivals, err := GetFestivals(context.Background())
 	if err != nil {
 		t.Fatalf("GetFestivals failed: %v", err)
 	}
@@ -0,0 +1,50 @@
+package data
+
+import (
+	"strings"
+)
+
+// genreFamilies are the words a genre name has to contain to fit the taxonomy.
+// Sub-genres are built by combining them with modifiers, e.g. "Melodic Death Metal".
+var genreFamilies = map[string]bool{
+	"metal": true, "rock": true, "punk": true, "hardcore": true, "grunge": true,
+	"doom": true, "sludge": true, "stoner": true, "djent": true, "nwobhm": true,
+	"folk": true, "pop": true, "emo": true, "screamo": true, "shoegaze": true,
+	"industrial": true, "electronic": true, "electronica": true, "ebm": true, "synthwave": true,
+	"hip-hop": true, "hip": true, "rap": true, "trap": true, "jazz": true, "blues": true,
+	"funk": true, "soul": true, "reggae": true, "ska": true, "ambient": true, "noise": true,
+	"goth": true, "gothic": true, "darkwave": true, "wave": true, "techno": true, "dance": true,
+	"classical": true, "orchestral": true, "country": true, "crossover": true, "psychedelic": true,
+	"psychedelia": true, "experimental": true, "alternative": true, "indie": true,
+	"thrash": true, "death": true, "black": true, "speed": true, "power": true, "progressive": true,
+	"prog": true, "glam": true, "roll": true, "crust": true, "d-beat": true, "oi!": true, "aor": true,
+	"nwothm": true, "rockabilly": true, "electro": true, "synth": true, "edm": true, "house": true,
+	"trance": true, "disco": true, "dub": true, "dubstep": true, "drum": true, "schlager": true,
+	"neofolk": true, "powerviolence": true, "hop": true,
+}
+
+// genreSuffixes cover compound names like "Deathcore", "Goregrind" or "Blackgaze"
+var genreSuffixes = []string{"core", "grind", "gaze", "metal", "rock", "punk", "pop", "wave", "musik"}
+
+// IsKnownGenre tells whether a genre fits the music taxonomy of the database
+func IsKnownGenre(genre string) bool {
+	words := strings.FieldsFunc(strings.ToLower(genre), func(r rune) bool {
+		return r == ' ' || r == '/' || r == '&'
+	})
+	for _, word := range words {
+		if genreFamilies[word] {
+			return true
+		}
+		for _, part := range strings.Split(word, "-") {
+			if genreFamilies[part] {
+				return true
+			}
+		}
+		for _, suffix := range genreSuffixes {
+			if strings.HasSuffix(word, suffix) {
+				return true
+			}
+		}
+	}
+	return false
+}
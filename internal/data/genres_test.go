@@ -0,0 +1,31 @@
+package data
+
+import "testing"
+
+func TestIsKnownGenre(t *testing.T) {
+	tests := []struct {
+		genre    string
+		expected bool
+	}{
+		{genre: "Heavy Metal", expected: true},
+		{genre: "Melodic Death Metal", expected: true},
+		{genre: "Deathcore", expected: true},
+		{genre: "Goregrind", expected: true},
+		{genre: "Blackgaze", expected: true},
+		{genre: "Post-Hardcore", expected: true},
+		{genre: "Hip-Hop", expected: true},
+		{genre: "Death/Doom Metal", expected: true},
+		{genre: "Thrash", expected: true},
+		{genre: "Kickboxing", expected: false},
+		{genre: "Talk Show", expected: false},
+		{genre: "", expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.genre, func(t *testing.T) {
+			if result := IsKnownGenre(tt.genre); result != tt.expected {
+				t.Errorf("IsKnownGenre(%q) = %v, want %v", tt.genre, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,119 @@
+package data
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+// ImportResult counts the records an import adds and replaces
+type ImportResult struct {
+	AddedFestivals   int `json:"addedFestivals"`
+	UpdatedFestivals int `json:"updatedFestivals"`
+	AddedBands       int `json:"addedBands"`
+	UpdatedBands     int `json:"updatedBands"`
+}
+
+// ImportDatabase merges the festivals and bands of incoming into the
+// database, replacing the records with the same key and adding the others.
+// With replace the database is replaced as a whole instead. Nothing is
+// written when incoming has invalid records, they are all returned, or when
+// dryRun is set.
+func ImportDatabase(ctx context.Context, incoming *model.Database, replace, dryRun bool) (ImportResult, []FieldError, error) {
+	var result ImportResult
+	if problems := validateImport(incoming); len(problems) > 0 {
+		return result, problems, nil
+	}
+	if !dryRun {
+		endWrite, err := beginWrite()
+		if err != nil {
+			return result, nil, err
+		}
+		defer endWrite()
+	}
+
+	db := &model.Database{Festivals: []model.Festival{}, Bands: []model.Band{}}
+	if !replace {
+		current, err := readDatabase(ctx)
+		if err != nil {
+			return result, nil, err
+		}
+		db = current
+	}
+
+	festivals := make(map[string]int, len(db.Festivals))
+	for i, festival := range db.Festivals {
+		festivals[festival.Key] = i
+	}
+	for _, festival := range incoming.Festivals {
+		if i, ok := festivals[festival.Key]; ok {
+			db.Festivals[i] = festival
+			result.UpdatedFestivals++
+			continue
+		}
+		db.Festivals = append(db.Festivals, festival)
+		result.AddedFestivals++
+	}
+
+	bands := make(map[string]int, len(db.Bands))
+	for i, band := range db.Bands {
+		bands[band.Key] = i
+	}
+	for _, band := range incoming.Bands {
+		if i, ok := bands[band.Key]; ok {
+			db.Bands[i] = band
+			result.UpdatedBands++
+			continue
+		}
+		db.Bands = append(db.Bands, band)
+		result.AddedBands++
+	}
+
+	if dryRun {
+		return result, nil, nil
+	}
+	return result, nil, writeDatabase(ctx, db)
+}
+
+// validateImport checks that every record can be stored: it has a name and a
+// valid key that no other record of its kind uses. Content rules are left to
+// the validate command, so a database can always be imported back.
+func validateImport(db *model.Database) []FieldError {
+	var problems []FieldError
+	fail := func(field, format string, args ...any) {
+		problems = append(problems, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	festivals := make(map[string]bool)
+	for i, festival := range db.Festivals {
+		field := fmt.Sprintf("festivals[%d]", i)
+		switch {
+		case !keyPattern.MatchString(festival.Key):
+			fail(field+".key", "%q is not a valid key", festival.Key)
+		case festivals[festival.Key]:
+			fail(field+".key", "%q is used by another festival", festival.Key)
+		}
+		festivals[festival.Key] = true
+		if festival.Name == "" {
+			fail(field+".name", "cannot be empty")
+		}
+	}
+
+	bands := make(map[string]bool)
+	for i, band := range db.Bands {
+		field := fmt.Sprintf("bands[%d]", i)
+		switch {
+		case !keyPattern.MatchString(band.Key):
+			fail(field+".key", "%q is not a valid key", band.Key)
+		case bands[band.Key]:
+			fail(field+".key", "%q is used by another band", band.Key)
+		}
+		bands[band.Key] = true
+		if band.Name == "" {
+			fail(field+".name", "cannot be empty")
+		}
+	}
+
+	return problems
+}
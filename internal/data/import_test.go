@@ -0,0 +1,105 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+func writeTestDatabase(t *testing.T, db model.Database) {
+	t.Helper()
+	dbFile := filepath.Join(t.TempDir(), "db.json")
+	content, err := json.MarshalIndent(db, "", "  ")
+	if err != nil {
+		t.Fatalf("Failed to marshal test database: %v", err)
+	}
+	if err := os.WriteFile(dbFile, append(content, '\n'), 0600); err != nil {
+		t.Fatalf("Failed to write test database: %v", err)
+	}
+	originalDBFile := SetDBFilePathForTesting(dbFile)
+	t.Cleanup(func() { SetDBFilePathForTesting(originalDBFile) })
+}
+
+func TestImportDatabase(t *testing.T) {
+	current := model.Database{
+		Festivals: []model.Festival{{Key: "hellfest", Name: "Hellfest", Location: "France"}},
+		Bands:     []model.Band{{Key: "gojira", Name: "Gojira"}},
+	}
+	incoming := &model.Database{
+		Festivals: []model.Festival{
+			{Key: "hellfest", Name: "Hellfest", Location: "Clisson, France"},
+			{Key: "wacken", Name: "Wacken Open Air"},
+		},
+		Bands: []model.Band{{Key: "sepultura", Name: "Sepultura"}},
+	}
+
+	tests := []struct {
+		name      string
+		replace   bool
+		dryRun    bool
+		expected  ImportResult
+		festivals int
+		bands     int
+	}{
+		{name: "Merge", expected: ImportResult{AddedFestivals: 1, UpdatedFestivals: 1, AddedBands: 1}, festivals: 2, bands: 2},
+		{name: "Replace", replace: true, expected: ImportResult{AddedFestivals: 2, AddedBands: 1}, festivals: 2, bands: 1},
+		{name: "Dry run", dryRun: true, expected: ImportResult{AddedFestivals: 1, UpdatedFestivals: 1, AddedBands: 1}, festivals: 1, bands: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			writeTestDatabase(t, current)
+
+			result, problems, err := ImportDatabase(context.Background(), incoming, tt.replace, tt.dryRun)
+			if err != nil || len(problems) > 0 {
+				t.Fatalf("ImportDatabase(context.Background()) failed: %v %v", err, problems)
+			}
+			if result != tt.expected {
+				t.Errorf("ImportDatabase(context.Background()) = %+v, want %+v", result, tt.expected)
+			}
+
+			db, err := GetDatabase(context.Background())
+			if err != nil {
+				t.Fatalf("GetDatabase failed: %v", err)
+			}
+			if len(db.Festivals) != tt.festivals || len(db.Bands) != tt.bands {
+				t.Errorf("database has %d festivals and %d bands, want %d and %d", len(db.Festivals), len(db.Bands), tt.festivals, tt.bands)
+			}
+		})
+	}
+}
+
+func TestImportDatabase_RejectsInvalidRecords(t *testing.T) {
+	writeTestDatabase(t, model.Database{Festivals: []model.Festival{}, Bands: []model.Band{}})
+
+	incoming := &model.Database{
+		Festivals: []model.Festival{{Key: "Hell Fest", Name: "Hellfest"}},
+		Bands:     []model.Band{{Key: "gojira", Name: "Gojira"}, {Key: "gojira"}},
+	}
+	_, problems, err := ImportDatabase(context.Background(), incoming, false, false)
+	if err != nil {
+		t.Fatalf("ImportDatabase(context.Background()) failed: %v", err)
+	}
+
+	fields := make(map[string]bool)
+	for _, problem := range problems {
+		fields[problem.Field] = true
+	}
+	for _, field := range []string{"festivals[0].key", "bands[1].key", "bands[1].name"} {
+		if !fields[field] {
+			t.Errorf("expected a problem with %s, got %v", field, problems)
+		}
+	}
+
+	db, err := GetDatabase(context.Background())
+	if err != nil {
+		t.Fatalf("GetDatabase failed: %v", err)
+	}
+	if len(db.Festivals) != 0 || len(db.Bands) != 0 {
+		t.Error("nothing should be written when a record is invalid")
+	}
+}
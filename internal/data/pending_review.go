@@ -0,0 +1,81 @@
+package data
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/constants"
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+// The pending-review queue lives next to the database file
+func pendingReviewFilePath() string {
+	return filepath.Join(filepath.Dir(dbFilePath), constants.PendingFile)
+}
+
+// GetPendingReviews returns the queued records, oldest first
+func GetPendingReviews(ctx context.Context) (_ []model.PendingReview, err error) {
+	done := observe(ctx, "pending_review", "read")
+	defer func() { done(err) }()
+
+	// #nosec G304 - the path is derived from the controlled database path
+	content, err := os.ReadFile(pendingReviewFilePath())
+	if errors.Is(err, fs.ErrNotExist) {
+		return []model.PendingReview{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var reviews []model.PendingReview
+	if err := json.Unmarshal(content, &reviews); err != nil {
+		return nil, err
+	}
+	return reviews, nil
+}
+
+// AddPendingReview queues a record for review, replacing any earlier
+// submission of the same record
+func AddPendingReview(ctx context.Context, review model.PendingReview) error {
+	endWrite, err := beginWrite()
+	if err != nil {
+		return err
+	}
+	defer endWrite()
+
+	reviews, err := GetPendingReviews(ctx)
+	if err != nil {
+		return err
+	}
+
+	if review.SubmittedAt.IsZero() {
+		review.SubmittedAt = time.Now().UTC()
+	}
+
+	replaced := false
+	for i, existing := range reviews {
+		if existing.Kind == review.Kind && existing.Key == review.Key {
+			reviews[i] = review
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		reviews = append(reviews, review)
+	}
+
+	content, err := json.MarshalIndent(reviews, "", "  ")
+	if err != nil {
+		return err
+	}
+	content = append(content, '\n')
+	done := observe(ctx, "pending_review", "write")
+	err = writeFileAtomic(pendingReviewFilePath(), content)
+	done(err)
+	return err
+}
@@ -0,0 +1,54 @@
+package data
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+func TestPendingReviews(t *testing.T) {
+	tempDir := t.TempDir()
+	originalDBFile := SetDBFilePathForTesting(filepath.Join(tempDir, "db.json"))
+	defer SetDBFilePathForTesting(originalDBFile)
+
+	reviews, err := GetPendingReviews(context.Background())
+	if err != nil {
+		t.Fatalf("GetPendingReviews failed: %v", err)
+	}
+	if len(reviews) != 0 {
+		t.Fatalf("Expected empty queue without a file, got %+v", reviews)
+	}
+
+	first := model.PendingReview{Kind: model.ReviewBand, Key: "test-band", Band: &model.Band{Key: "test-band"}, Confidence: 0.4}
+	if err := AddPendingReview(context.Background(), first); err != nil {
+		t.Fatalf("AddPendingReview failed: %v", err)
+	}
+	other := model.PendingReview{Kind: model.ReviewFestival, Key: "test-band", Confidence: 0.5}
+	if err := AddPendingReview(context.Background(), other); err != nil {
+		t.Fatalf("AddPendingReview failed: %v", err)
+	}
+	resubmitted := first
+	resubmitted.Confidence = 0.6
+	if err := AddPendingReview(context.Background(), resubmitted); err != nil {
+		t.Fatalf("AddPendingReview failed: %v", err)
+	}
+
+	reviews, err = GetPendingReviews(context.Background())
+	if err != nil {
+		t.Fatalf("GetPendingReviews failed: %v", err)
+	}
+	if len(reviews) != 2 {
+		t.Fatalf("Expected 2 queued records, got %d", len(reviews))
+	}
+	if reviews[0].Kind != model.ReviewBand || reviews[0].Confidence != 0.6 {
+		t.Errorf("Expected the band submission to be replaced in place, got %+v", reviews[0])
+	}
+	if reviews[0].SubmittedAt.IsZero() {
+		t.Error("Expected SubmittedAt to be set")
+	}
+	if reviews[0].Band == nil || reviews[0].Band.Key != "test-band" {
+		t.Errorf("Expected the band to round-trip, got %+v", reviews[0].Band)
+	}
+}
@@ -0,0 +1,131 @@
+package data
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+const (
+	// DefaultLinkThreshold is the similarity above which a name is linked to an existing band
+	DefaultLinkThreshold = 0.9
+	// ambiguousThreshold is the similarity above which a band is offered as a candidate
+	ambiguousThreshold = 0.75
+	// ambiguousMargin is how far ahead the best candidate has to be to be linked
+	ambiguousMargin = 0.05
+)
+
+// BandMatch is an existing band a name was compared against
+type BandMatch struct {
+	Key   string
+	Name  string
+	Score float64
+}
+
+// BandResolver resolves band names, as written on festival lineups, to the
+// bands already in the catalog using their names and aliases
+type BandResolver struct {
+	linkThreshold float64
+	// names maps every normalized name and alias to its band
+	names map[string]BandMatch
+}
+
+// NewBandResolver indexes the catalog bands, their aliases and any band
+// referenced by a festival that has no catalog entry yet
+func NewBandResolver(bands []model.Band, refs []model.BandRef, linkThreshold float64) *BandResolver {
+	resolver := &BandResolver{linkThreshold: linkThreshold, names: make(map[string]BandMatch)}
+	for _, band := range bands {
+		resolver.add(band.Name, band.Key, band.Name)
+		for _, alias := range band.Aliases {
+			resolver.add(alias, band.Key, band.Name)
+		}
+	}
+	for _, ref := range refs {
+		resolver.add(ref.Name, ref.Key, ref.Name)
+	}
+	return resolver
+}
+
+func (r *BandResolver) add(name, key, canonical string) {
+	normalized := NormalizeForMatching(name)
+	if normalized == "" {
+		return
+	}
+	if _, exists := r.names[normalized]; !exists {
+		r.names[normalized] = BandMatch{Key: key, Name: canonical}
+	}
+}
+
+// Resolve returns the band the name refers to and whether it was found.
+// When no band is close enough to be linked, the candidates that came close
+// are returned, best first, so a person can pick the right one.
+func (r *BandResolver) Resolve(name string) (BandMatch, bool, []BandMatch) {
+	if r == nil {
+		return BandMatch{}, false, nil
+	}
+	normalized := NormalizeForMatching(name)
+	if match, exists := r.names[normalized]; exists {
+		match.Score = 1
+		return match, true, nil
+	}
+
+	// Keep the best score of each band, as several aliases may point to it
+	best := make(map[string]BandMatch)
+	for candidate, match := range r.names {
+		match.Score = NameSimilarity(normalized, candidate)
+		if match.Score < ambiguousThreshold {
+			continue
+		}
+		if current, exists := best[match.Key]; !exists || match.Score > current.Score {
+			best[match.Key] = match
+		}
+	}
+
+	candidates := make([]BandMatch, 0, len(best))
+	for _, match := range best {
+		candidates = append(candidates, match)
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].Score != candidates[j].Score {
+			return candidates[i].Score > candidates[j].Score
+		}
+		return candidates[i].Key < candidates[j].Key
+	})
+
+	if len(candidates) == 0 {
+		return BandMatch{}, false, nil
+	}
+	top := candidates[0]
+	if top.Score >= r.linkThreshold && (len(candidates) == 1 || top.Score-candidates[1].Score >= ambiguousMargin) {
+		return top, true, nil
+	}
+	return BandMatch{}, false, candidates
+}
+
+// transliterations covers letters that do not decompose into a base letter and an accent
+var transliterations = strings.NewReplacer("ø", "o", "æ", "ae", "œ", "oe", "ß", "ss", "ð", "d", "þ", "th", "ł", "l", "&", "and")
+
+// NormalizeForMatching reduces a band name to the letters and digits that
+// identify it: lowercase, without accents, a leading "the" or punctuation
+func NormalizeForMatching(name string) string {
+	stripped, _, err := transform.String(transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC), name)
+	if err != nil {
+		stripped = name
+	}
+	stripped = transliterations.Replace(strings.ToLower(strings.TrimSpace(stripped)))
+	stripped = strings.TrimPrefix(stripped, "the ")
+
+	var builder strings.Builder
+	for _, r := range stripped {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,81 @@
+package data
+
+import (
+	"testing"
+
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+func TestNormalizeForMatching(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "BloodBath", expected: "bloodbath"},
+		{name: "The Halo Effect", expected: "haloeffect"},
+		{name: "Mötley Crüe", expected: "motleycrue"},
+		{name: "Kvelertak & Friends", expected: "kvelertakandfriends"},
+		{name: "Sólstafir", expected: "solstafir"},
+		{name: "Mørkt", expected: "morkt"},
+		{name: "AC/DC", expected: "acdc"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := NormalizeForMatching(tt.name); result != tt.expected {
+				t.Errorf("NormalizeForMatching(%q) = %q, want %q", tt.name, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBandResolver(t *testing.T) {
+	bands := []model.Band{
+		{Key: "bloodbath", Name: "Bloodbath"},
+		{Key: "the-halo-effect", Name: "The Halo Effect"},
+		{Key: "motley-crue", Name: "Mötley Crüe", Aliases: []string{"Crue"}},
+		{Key: "sepultura", Name: "Sepultura"},
+		{Key: "septicflesh", Name: "Septicflesh"},
+		{Key: "sodom", Name: "Sodom"},
+		{Key: "sodoma", Name: "Sodoma"},
+	}
+	refs := []model.BandRef{{Key: "heilung", Name: "Heilung"}}
+	resolver := NewBandResolver(bands, refs, DefaultLinkThreshold)
+
+	tests := []struct {
+		name       string
+		lookup     string
+		found      bool
+		key        string
+		candidates int
+	}{
+		{name: "Different capitalization", lookup: "BloodBath", found: true, key: "bloodbath"},
+		{name: "Missing leading the", lookup: "Halo Effect", found: true, key: "the-halo-effect"},
+		{name: "Without accents", lookup: "Motley Crue", found: true, key: "motley-crue"},
+		{name: "Alias", lookup: "Crüe", found: true, key: "motley-crue"},
+		{name: "Festival only band", lookup: "heilung", found: true, key: "heilung"},
+		{name: "Small typo is linked", lookup: "Sepulturra", found: true, key: "sepultura"},
+		{name: "Close to several bands is ambiguous", lookup: "Sodomy", found: false, candidates: 2},
+		{name: "Unknown band", lookup: "Gojira", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			match, found, candidates := resolver.Resolve(tt.lookup)
+			if found != tt.found {
+				t.Fatalf("Resolve(%q) found = %v, want %v (match %+v, candidates %+v)", tt.lookup, found, tt.found, match, candidates)
+			}
+			if found && match.Key != tt.key {
+				t.Errorf("Resolve(%q) = %q, want %q", tt.lookup, match.Key, tt.key)
+			}
+			if len(candidates) != tt.candidates {
+				t.Errorf("Resolve(%q) candidates = %+v, want %d", tt.lookup, candidates, tt.candidates)
+			}
+		})
+	}
+
+	var missing *BandResolver
+	if _, found, _ := missing.Resolve("Bloodbath"); found {
+		t.Error("Resolve() on a nil resolver should not find anything")
+	}
+}
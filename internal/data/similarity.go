@@ -0,0 +1,63 @@
+package data
+
+import "strings"
+
+// LevenshteinDistance calculates the Levenshtein distance between two strings
+func LevenshteinDistance(s1, s2 string) int {
+	r1 := []rune(s1)
+	r2 := []rune(s2)
+
+	if len(r1) < len(r2) {
+		return LevenshteinDistance(s2, s1)
+	}
+
+	if len(r2) == 0 {
+		return len(r1)
+	}
+
+	previousRow := make([]int, len(r2)+1)
+	for i := range previousRow {
+		previousRow[i] = i
+	}
+
+	for i, c1 := range r1 {
+		currentRow := []int{i + 1}
+		for j, c2 := range r2 {
+			insertions := previousRow[j+1] + 1
+			deletions := currentRow[j] + 1
+			substitutions := previousRow[j]
+			if c1 != c2 {
+				substitutions++
+			}
+			currentRow = append(currentRow, minInt(insertions, deletions, substitutions))
+		}
+		previousRow = currentRow
+	}
+
+	return previousRow[len(r2)]
+}
+
+func minInt(a, b, c int) int {
+	if a < b {
+		if a < c {
+			return a
+		}
+		return c
+	}
+	if b < c {
+		return b
+	}
+	return c
+}
+
+// NameSimilarity compares two names ignoring case and surrounding spaces,
+// returning 1 for identical names and 0 for completely different ones
+func NameSimilarity(a, b string) float64 {
+	a = strings.ToLower(strings.TrimSpace(a))
+	b = strings.ToLower(strings.TrimSpace(b))
+	longest := max(len([]rune(a)), len([]rune(b)))
+	if longest == 0 {
+		return 1
+	}
+	return 1 - float64(LevenshteinDistance(a, b))/float64(longest)
+}
@@ -0,0 +1,136 @@
+package data
+
+import "testing"
+
+func TestLevenshteinDistance(t *testing.T) {
+	tests := []struct {
+		name     string
+		s1       string
+		s2       string
+		expected int
+	}{
+		{
+			name:     "Identical strings",
+			s1:       "hello",
+			s2:       "hello",
+			expected: 0,
+		},
+		{
+			name:     "One character difference",
+			s1:       "hello",
+			s2:       "hallo",
+			expected: 1,
+		},
+		{
+			name:     "Empty strings",
+			s1:       "",
+			s2:       "",
+			expected: 0,
+		},
+		{
+			name:     "Empty to non-empty",
+			s1:       "",
+			s2:       "hello",
+			expected: 5,
+		},
+		{
+			name:     "Multiple operations",
+			s1:       "kitten",
+			s2:       "sitting",
+			expected: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := LevenshteinDistance(tt.s1, tt.s2)
+			if result != tt.expected {
+				t.Errorf("LevenshteinDistance(%q, %q) = %d, want %d", tt.s1, tt.s2, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMinInt(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        int
+		b        int
+		c        int
+		expected int
+	}{
+		{
+			name:     "a is minimum",
+			a:        1,
+			b:        2,
+			c:        3,
+			expected: 1,
+		},
+		{
+			name:     "b is minimum",
+			a:        3,
+			b:        1,
+			c:        2,
+			expected: 1,
+		},
+		{
+			name:     "c is minimum",
+			a:        3,
+			b:        2,
+			c:        1,
+			expected: 1,
+		},
+		{
+			name:     "All equal",
+			a:        5,
+			b:        5,
+			c:        5,
+			expected: 5,
+		},
+		{
+			name:     "Negative numbers",
+			a:        -5,
+			b:        -2,
+			c:        -10,
+			expected: -10,
+		},
+		{
+			name:     "Zero included",
+			a:        0,
+			b:        1,
+			c:        2,
+			expected: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := minInt(tt.a, tt.b, tt.c)
+			if result != tt.expected {
+				t.Errorf("minInt(%d, %d, %d) = %d, want %d", tt.a, tt.b, tt.c, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		name     string
+		a        string
+		b        string
+		expected float64
+	}{
+		{name: "Identical ignoring case", a: "Iron Maiden", b: "iron maiden ", expected: 1},
+		{name: "Both empty", a: "", b: "", expected: 1},
+		{name: "One typo", a: "Metallica", b: "Metalica", expected: 1 - 1.0/9},
+		{name: "Completely different", a: "abc", b: "xyz", expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := NameSimilarity(tt.a, tt.b); result != tt.expected {
+				t.Errorf("NameSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
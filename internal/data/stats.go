@@ -0,0 +1,66 @@
+package data
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// Stats summarises the content of the database
+type Stats struct {
+	Festivals              int       `json:"festivals"`
+	FestivalsWithoutPrice  int       `json:"festivalsWithoutPrice"`
+	FestivalsWithoutPoster int       `json:"festivalsWithoutPoster"`
+	Bands                  int       `json:"bands"`
+	ReviewedBands          int       `json:"reviewedBands"`
+	UnreviewedBands        int       `json:"unreviewedBands"`
+	IncompleteBands        int       `json:"incompleteBands"`
+	PendingReviews         int       `json:"pendingReviews"`
+	LastModified           time.Time `json:"lastModified"`
+}
+
+// GetStats counts the records in the database and the review queue
+func GetStats(ctx context.Context) (Stats, error) {
+	var stats Stats
+
+	info, err := os.Stat(dbFilePath)
+	if err != nil {
+		return stats, err
+	}
+	stats.LastModified = info.ModTime().UTC()
+
+	db, err := readDatabase(ctx)
+	if err != nil {
+		return stats, err
+	}
+
+	stats.Festivals = len(db.Festivals)
+	for _, festival := range db.Festivals {
+		if festival.TicketPrice == 0 {
+			stats.FestivalsWithoutPrice++
+		}
+		if festival.Poster == "" {
+			stats.FestivalsWithoutPoster++
+		}
+	}
+
+	stats.Bands = len(db.Bands)
+	for _, band := range db.Bands {
+		if band.Reviewed {
+			stats.ReviewedBands++
+		} else {
+			stats.UnreviewedBands++
+		}
+		if !IsBandComplete(band) {
+			stats.IncompleteBands++
+		}
+	}
+
+	reviews, err := GetPendingReviews(ctx)
+	if err != nil {
+		return stats, err
+	}
+	stats.PendingReviews = len(reviews)
+
+	return stats, nil
+}
@@ -0,0 +1,53 @@
+package data
+
+import (
+	"context"
+	"testing"
+
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+func TestGetStats(t *testing.T) {
+	writeTestDatabase(t, model.Database{
+		Festivals: []model.Festival{
+			{Key: "hellfest", Name: "Hellfest", TicketPrice: 329, Poster: "https://hellfest.fr/poster.jpg"},
+			{Key: "wacken", Name: "Wacken Open Air"},
+		},
+		Bands: []model.Band{
+			{Key: "gojira", Name: "Gojira", Reviewed: true},
+			{Key: "sepultura", Name: "Sepultura"},
+			{Key: "slayer", Name: "Slayer"},
+			{
+				Key: "metallica", Name: "Metallica", Country: "USA", Description: "American heavy metal band",
+				HeadlineImage: "https://example.com/image.jpg", Logo: "https://example.com/logo.png",
+				Website: "https://metallica.com", Spotify: "https://open.spotify.com/artist/123",
+				Genres: []string{"Heavy Metal"}, Members: []model.Member{{Name: "James Hetfield", Role: "Vocals"}},
+			},
+		},
+	})
+	if err := AddPendingReview(context.Background(), model.PendingReview{Kind: model.ReviewBand, Key: "metalica"}); err != nil {
+		t.Fatalf("AddPendingReview failed: %v", err)
+	}
+
+	stats, err := GetStats(context.Background())
+	if err != nil {
+		t.Fatalf("GetStats failed: %v", err)
+	}
+	expected := Stats{
+		Festivals:              2,
+		FestivalsWithoutPrice:  1,
+		FestivalsWithoutPoster: 1,
+		Bands:                  4,
+		ReviewedBands:          1,
+		UnreviewedBands:        3,
+		IncompleteBands:        3,
+		PendingReviews:         1,
+		LastModified:           stats.LastModified,
+	}
+	if stats != expected {
+		t.Errorf("GetStats(context.Background()) = %+v, want %+v", stats, expected)
+	}
+	if stats.LastModified.IsZero() {
+		t.Error("GetStats(context.Background()) should report when the database was last modified")
+	}
+}
@@ -7,3 +7,9 @@ func SetDBFilePathForTesting(path string) string {
 	dbFilePath = path
 	return oldPath
 }
+
+// ReopenForTesting accepts writes again after Close
+// This should only be used in tests
+func ReopenForTesting() {
+	closed.Store(false)
+}
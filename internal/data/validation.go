@@ -0,0 +1,87 @@
+package data
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+// FieldError describes why the value of a single field is not valid
+type FieldError = model.FieldError
+
+var keyPattern = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// ValidateFestival checks a festival against the same rules the admin form
+// applies, returning every field that breaks them
+func ValidateFestival(festival model.Festival) []FieldError {
+	var problems []FieldError
+	fail := func(field, format string, args ...any) {
+		problems = append(problems, FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+	}
+
+	if len(festival.Key) < 2 {
+		fail("key", "must be at least 2 characters")
+	} else if !keyPattern.MatchString(festival.Key) {
+		fail("key", "can only contain lowercase letters, numbers, and dashes")
+	}
+
+	if len(festival.Name) < 2 {
+		fail("name", "must be at least 2 characters")
+	}
+
+	start, startErr := time.Parse("2006-01-02", festival.Dates.Start)
+	end, endErr := time.Parse("2006-01-02", festival.Dates.End)
+	switch {
+	case startErr != nil || endErr != nil:
+		fail("dates", "start and end must be dates as YYYY-MM-DD")
+	case end.Before(start):
+		fail("dates", "end date must be after start date")
+	}
+
+	if len(festival.Location) < 2 {
+		fail("location", "must be at least 2 characters")
+	}
+
+	coordinates := festival.Coordinates
+	switch {
+	case coordinates.Lat == 0 && coordinates.Lng == 0:
+		fail("coordinates", "are missing")
+	case coordinates.Lat < -90 || coordinates.Lat > 90:
+		fail("coordinates", "latitude must be between -90 and 90")
+	case coordinates.Lng < -180 || coordinates.Lng > 180:
+		fail("coordinates", "longitude must be between -180 and 180")
+	}
+
+	if !isHTTPURL(festival.Poster) {
+		fail("poster", "must be an http or https URL")
+	}
+	if !isHTTPURL(festival.Website) {
+		fail("website", "must be an http or https URL")
+	}
+
+	if festival.TicketPrice < 0 {
+		fail("ticketPrice", "must be a positive number")
+	}
+
+	for _, band := range festival.Bands {
+		if !keyPattern.MatchString(band.Key) {
+			fail("bands", "%q has an invalid key %q", band.Name, band.Key)
+		}
+		if band.Size < 1 || band.Size > 3 {
+			fail("bands", "%q must have a size between 1 and 3", band.Name)
+		}
+	}
+
+	return problems
+}
+
+func isHTTPURL(value string) bool {
+	parsed, err := url.Parse(value)
+	if err != nil || parsed.Host == "" {
+		return false
+	}
+	return parsed.Scheme == "http" || parsed.Scheme == "https"
+}
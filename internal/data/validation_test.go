@@ -0,0 +1,105 @@
+package data
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+func TestValidateFestival(t *testing.T) {
+	valid := model.Festival{
+		Key:         "test-fest",
+		Name:        "Test Fest",
+		Dates:       model.Dates{Start: "2026-06-04", End: "2026-06-06"},
+		Location:    "Clisson, France",
+		Coordinates: model.Coordinates{Lat: 47.0875, Lng: -1.2808},
+		Poster:      "https://testfest.com/poster.jpg",
+		Website:     "https://testfest.com",
+		Bands:       []model.BandRef{{Key: "iron-maiden", Name: "Iron Maiden", Size: 3}},
+		TicketPrice: 150,
+	}
+
+	tests := []struct {
+		name     string
+		modify   func(festival *model.Festival)
+		expected []string
+	}{
+		{
+			name:   "Valid festival",
+			modify: func(festival *model.Festival) {},
+		},
+		{
+			name:     "Key with invalid characters",
+			modify:   func(festival *model.Festival) { festival.Key = "Test Fest" },
+			expected: []string{"key"},
+		},
+		{
+			name:     "End before start",
+			modify:   func(festival *model.Festival) { festival.Dates.End = "2026-06-01" },
+			expected: []string{"dates"},
+		},
+		{
+			name:     "Dates not announced",
+			modify:   func(festival *model.Festival) { festival.Dates = model.Dates{} },
+			expected: []string{"dates"},
+		},
+		{
+			name:     "Missing coordinates",
+			modify:   func(festival *model.Festival) { festival.Coordinates = model.Coordinates{} },
+			expected: []string{"coordinates"},
+		},
+		{
+			name:     "Latitude out of range",
+			modify:   func(festival *model.Festival) { festival.Coordinates.Lat = 147 },
+			expected: []string{"coordinates"},
+		},
+		{
+			name: "Invalid URLs",
+			modify: func(festival *model.Festival) {
+				festival.Poster = ""
+				festival.Website = "javascript:alert(1)"
+			},
+			expected: []string{"poster", "website"},
+		},
+		{
+			name: "Band without a tier",
+			modify: func(festival *model.Festival) {
+				festival.Bands = []model.BandRef{{Key: "slayer", Name: "Slayer", Size: 0}}
+			},
+			expected: []string{"bands"},
+		},
+		{
+			name: "Several problems",
+			modify: func(festival *model.Festival) {
+				festival.Name = "T"
+				festival.Location = ""
+				festival.TicketPrice = -1
+			},
+			expected: []string{"name", "location", "ticketPrice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			festival := valid
+			festival.Bands = append([]model.BandRef(nil), valid.Bands...)
+			tt.modify(&festival)
+
+			var fields []string
+			for _, problem := range ValidateFestival(festival) {
+				fields = append(fields, problem.Field)
+			}
+			if !reflect.DeepEqual(fields, tt.expected) {
+				t.Errorf("ValidateFestival() fields = %v, want %v", fields, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFieldErrorError(t *testing.T) {
+	err := FieldError{Field: "dates", Message: "end date must be after start date"}
+	if err.Error() != "dates: end date must be after start date" {
+		t.Errorf("Error() = %q", err.Error())
+	}
+}
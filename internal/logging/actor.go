@@ -0,0 +1,30 @@
+package logging
+
+import "context"
+
+type actorKey struct{}
+
+// actor names who made a request. Middleware shares it with the handlers, so
+// the actor a handler authenticates shows in the records logged around it.
+type actor struct {
+	name string
+}
+
+// WithActor records name, e.g. "token:ci", as the caller of the request ctx
+// belongs to. Records logged with ctx carry it, and so do those logged with
+// the context Middleware passed on.
+func WithActor(ctx context.Context, name string) context.Context {
+	if holder, ok := ctx.Value(actorKey{}).(*actor); ok {
+		holder.name = name
+		return ctx
+	}
+	return context.WithValue(ctx, actorKey{}, &actor{name: name})
+}
+
+// Actor returns the caller of the request ctx belongs to, if known
+func Actor(ctx context.Context) string {
+	if holder, ok := ctx.Value(actorKey{}).(*actor); ok {
+		return holder.name
+	}
+	return ""
+}
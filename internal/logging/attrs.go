@@ -0,0 +1,24 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+)
+
+type attrsKey struct{}
+
+// WithAttrs returns a copy of ctx whose records carry attrs, along with the
+// ones ctx already adds. Workers use it to name the item every record of
+// theirs is about, down to the packages they call.
+func WithAttrs(ctx context.Context, attrs ...slog.Attr) context.Context {
+	inherited := contextAttrs(ctx)
+	all := make([]slog.Attr, 0, len(inherited)+len(attrs))
+	all = append(append(all, inherited...), attrs...)
+	return context.WithValue(ctx, attrsKey{}, all)
+}
+
+// contextAttrs returns the attributes WithAttrs added to ctx
+func contextAttrs(ctx context.Context) []slog.Attr {
+	attrs, _ := ctx.Value(attrsKey{}).([]slog.Attr)
+	return attrs
+}
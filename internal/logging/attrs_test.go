@@ -0,0 +1,33 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestWithAttrs(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := New(&out, FormatText, "info")
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	ctx := WithAttrs(context.Background(), slog.String("item", "band"))
+	inner := WithAttrs(ctx, slog.String("band", "gojira"))
+	logger.InfoContext(inner, "band updated", "fields", 2)
+	logger.InfoContext(ctx, "run done")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("logged %d lines, want 2:\n%s", len(lines), out.String())
+	}
+	if want := "fields=2 item=band band=gojira"; !strings.Contains(lines[0], want) {
+		t.Errorf("line %q should contain %q", lines[0], want)
+	}
+	if strings.Contains(lines[1], "gojira") {
+		t.Errorf("the attributes of a derived context leaked into its parent: %q", lines[1])
+	}
+}
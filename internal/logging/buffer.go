@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+)
+
+type bufferKey struct{}
+
+// Buffer holds records until Flush hands them to the handler they were logged
+// with. Items processed concurrently log into a buffer each, and the buffers
+// are flushed one after the other, so the records of an item stay together
+// and in order.
+type Buffer struct {
+	mu      sync.Mutex
+	records []bufferedRecord
+}
+
+type bufferedRecord struct {
+	ctx     context.Context
+	handler slog.Handler
+	record  slog.Record
+}
+
+// WithBuffer returns a copy of ctx whose records are kept in buf instead of
+// being written. Only the loggers made by New keep them.
+func WithBuffer(ctx context.Context, buf *Buffer) context.Context {
+	return context.WithValue(ctx, bufferKey{}, buf)
+}
+
+// contextBuffer returns the buffer WithBuffer added to ctx, if any
+func contextBuffer(ctx context.Context) *Buffer {
+	buf, _ := ctx.Value(bufferKey{}).(*Buffer)
+	return buf
+}
+
+func (b *Buffer) add(ctx context.Context, handler slog.Handler, record slog.Record) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.records = append(b.records, bufferedRecord{ctx: ctx, handler: handler, record: record.Clone()})
+}
+
+// Flush writes the records held, in the order they were logged, and empties
+// the buffer
+func (b *Buffer) Flush() error {
+	b.mu.Lock()
+	records := b.records
+	b.records = nil
+	b.mu.Unlock()
+
+	var errs []error
+	for _, r := range records {
+		if err := r.handler.Handle(r.ctx, r.record); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,52 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestBuffer(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := New(&out, FormatText, "info")
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	var first, second Buffer
+	firstCtx := WithBuffer(context.Background(), &first)
+	secondCtx := WithBuffer(context.Background(), &second)
+	logger.InfoContext(secondCtx, "second item")
+	logger.InfoContext(firstCtx, "first item")
+	logger.DebugContext(firstCtx, "below the level")
+	logger.With("component", "updater").WarnContext(firstCtx, "first item again")
+	logger.Info("not buffered")
+
+	if got := out.String(); !strings.Contains(got, "not buffered") || strings.Contains(got, "item") {
+		t.Fatalf("only the record without a buffer should be written, got:\n%s", got)
+	}
+
+	out.Reset()
+	if err := first.Flush(); err != nil {
+		t.Fatalf("Flush() failed: %v", err)
+	}
+	if err := second.Flush(); err != nil {
+		t.Fatalf("Flush() failed: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("flushed %d lines, want 3:\n%s", len(lines), out.String())
+	}
+	for i, want := range []string{`msg="first item"`, `msg="first item again" component=updater`, `msg="second item"`} {
+		if !strings.Contains(lines[i], want) {
+			t.Errorf("line %d = %q, want it to contain %q", i, lines[i], want)
+		}
+	}
+
+	// Flushing empties the buffer
+	out.Reset()
+	if err := first.Flush(); err != nil || out.Len() != 0 {
+		t.Errorf("second Flush() = %v, wrote %q", err, out.String())
+	}
+}
@@ -0,0 +1,73 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		level   string
+		wantErr bool
+	}{
+		{name: "Text", format: FormatText, level: "info"},
+		{name: "JSON", format: FormatJSON, level: "debug"},
+		{name: "Unknown format", format: "logfmt", level: "info", wantErr: true},
+		{name: "Unknown level", format: FormatText, level: "verbose", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := New(&bytes.Buffer{}, tt.format, tt.level)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("New(%q, %q) error = %v, wantErr %v", tt.format, tt.level, err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestNew_ContextAttributes(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := New(&out, FormatJSON, "warn")
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+	ctx = WithActor(WithRequestID(ctx, "req-1"), "token:ci")
+
+	logger.InfoContext(ctx, "below the level")
+	logger.With("component", "api").WarnContext(ctx, "band update failed", "band", "gojira")
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 1 {
+		t.Fatalf("logged %d lines, want 1:\n%s", len(lines), out.String())
+	}
+	var record map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &record); err != nil {
+		t.Fatalf("record is not JSON: %v", err)
+	}
+	for key, want := range map[string]string{
+		"msg":        "band update failed",
+		"level":      "WARN",
+		"component":  "api",
+		"band":       "gojira",
+		"request_id": "req-1",
+		"actor":      "token:ci",
+		"trace_id":   traceID.String(),
+		"span_id":    spanID.String(),
+	} {
+		if record[key] != want {
+			t.Errorf("record[%q] = %v, want %q", key, record[key], want)
+		}
+	}
+}
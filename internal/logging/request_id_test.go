@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMiddleware(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		wantSame bool
+	}{
+		{name: "Propagates the ID of the client", header: "3f2c9a1e-7b1d-4c55-9a7e-1d2b3c4d5e6f", wantSame: true},
+		{name: "Generates an ID", header: ""},
+		{name: "Replaces IDs that could forge log lines", header: "abc\nlevel=ERROR"},
+		{name: "Replaces IDs that are too long", header: strings.Repeat("a", maxRequestIDLength+1)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var seen string
+			handler := Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+				seen = RequestID(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/health", nil)
+			if tt.header != "" {
+				req.Header.Set(RequestIDHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			if seen == "" {
+				t.Fatal("the handler received no request ID")
+			}
+			if got := w.Header().Get(RequestIDHeader); got != seen {
+				t.Errorf("%s header = %q, want %q", RequestIDHeader, got, seen)
+			}
+			if (seen == tt.header) != tt.wantSame {
+				t.Errorf("request ID = %q, want the client's %v", seen, tt.wantSame)
+			}
+		})
+	}
+}
+
+func TestMiddleware_SharesActor(t *testing.T) {
+	var outer context.Context
+	handler := Middleware(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		outer = r.Context()
+		// The handler authenticates the caller on a context of its own
+		inner := WithActor(context.WithValue(r.Context(), struct{}{}, "inner"), "oidc:alice")
+		if got := Actor(inner); got != "oidc:alice" {
+			t.Errorf("Actor(inner) = %q, want %q", got, "oidc:alice")
+		}
+	}))
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPut, "/api/bands/gojira", nil))
+
+	if got := Actor(outer); got != "oidc:alice" {
+		t.Errorf("Actor() of the middleware context = %q, want %q", got, "oidc:alice")
+	}
+	if got := Actor(context.Background()); got != "" {
+		t.Errorf("Actor() without middleware = %q, want none", got)
+	}
+}
@@ -0,0 +1,176 @@
+// Package metrics exposes the Prometheus metrics of the server
+package metrics
+
+import (
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "metal_fests"
+
+// Registry holds every metric of the application, plus the Go runtime and
+// process ones
+var Registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served, by route pattern, method and status code.",
+	}, []string{"route", "method", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Time taken to serve HTTP requests, by route pattern, method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"route", "method", "status"})
+
+	httpInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Namespace: namespace,
+		Name:      "http_requests_in_flight",
+		Help:      "HTTP requests being served.",
+	})
+
+	dbDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "db_operation_duration_seconds",
+		Help:      "Time taken to read or write the database files, by file and operation.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1},
+	}, []string{"file", "operation"})
+
+	dbFailures = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "db_operation_failures_total",
+		Help:      "Failed reads and writes of the database files, by file and operation.",
+	}, []string{"file", "operation"})
+
+	validateURLOutcomes = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "validate_url_total",
+		Help:      "URL validations, by outcome: valid, invalid (error status) or unreachable.",
+	}, []string{"outcome"})
+
+	cacheLookups = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "cache_lookups_total",
+		Help:      "Cache lookups, by cache and result (hit or miss).",
+	}, []string{"cache", "result"})
+
+	rateLimited = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "rate_limited_requests_total",
+		Help:      "Requests refused because the client ran out of budget, by budget.",
+	}, []string{"budget"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration, httpInFlight,
+		dbDuration, dbFailures,
+		validateURLOutcomes, cacheLookups, rateLimited,
+	)
+}
+
+// Handler serves the metrics in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	r.status = code
+	r.ResponseWriter.WriteHeader(code)
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// Middleware records the requests served by next. The route label is the
+// pattern routes matched, so keys in paths don't multiply the series. The
+// handlers between the middleware and routes must pass the request on as it
+// is: the pattern is only visible on the request routes received. Requests
+// answered before reaching routes, like the rate limited ones, are labelled
+// with the pattern routes would have matched.
+func Middleware(routes *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		httpInFlight.Inc()
+		defer httpInFlight.Dec()
+
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(recorder, r)
+
+		route := r.Pattern
+		if route == "" {
+			_, route = routes.Handler(r)
+		}
+		if route == "" {
+			route = "unmatched"
+		}
+		method := methodLabel(r.Method)
+		status := strconv.Itoa(recorder.status)
+		httpRequests.WithLabelValues(route, method, status).Inc()
+		httpDuration.WithLabelValues(route, method, status).Observe(time.Since(start).Seconds())
+	})
+}
+
+// standardMethods are the request methods kept as they are in the labels
+var standardMethods = []string{
+	http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut, http.MethodPatch,
+	http.MethodDelete, http.MethodConnect, http.MethodOptions, http.MethodTrace,
+}
+
+// methodLabel returns the method label of a request. Any method is accepted
+// by the file server, so the ones outside the standard set are all counted as
+// "OTHER" instead of creating a series each.
+func methodLabel(method string) string {
+	if slices.Contains(standardMethods, method) {
+		return method
+	}
+	return "OTHER"
+}
+
+// ObserveDB records a read or write of a database file. It returns err so
+// callers can wrap their return value.
+func ObserveDB(file, operation string, start time.Time, err error) error {
+	dbDuration.WithLabelValues(file, operation).Observe(time.Since(start).Seconds())
+	if err != nil {
+		dbFailures.WithLabelValues(file, operation).Inc()
+	}
+	return err
+}
+
+// ValidateURL records the outcome of a URL validation
+func ValidateURL(outcome string) {
+	validateURLOutcomes.WithLabelValues(outcome).Inc()
+}
+
+// CacheLookup records a lookup in the named cache
+func CacheLookup(cache string, hit bool) {
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+	cacheLookups.WithLabelValues(cache, result).Inc()
+}
+
+// RateLimited records a request refused by the named rate limit budget
+func RateLimited(budget string) {
+	rateLimited.WithLabelValues(budget).Inc()
+}
@@ -0,0 +1,101 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /api/bands/{key}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	// limited answers before the request reaches the mux, like the rate limiter
+	limited := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	tests := []struct {
+		name   string
+		next   http.Handler
+		method string
+		path   string
+		route  string
+		label  string
+		status string
+	}{
+		{name: "Matched route", next: mux, method: http.MethodPut, path: "/api/bands/gojira", route: "PUT /api/bands/{key}", label: http.MethodPut, status: "204"},
+		{name: "Unmatched route", next: mux, method: http.MethodGet, path: "/missing", route: "unmatched", label: http.MethodGet, status: "404"},
+		{name: "Non-standard method", next: mux, method: "PROPFIND", path: "/missing", route: "unmatched", label: "OTHER", status: "404"},
+		{name: "Answered before the mux", next: limited, method: http.MethodPut, path: "/api/bands/gojira", route: "PUT /api/bands/{key}", label: http.MethodPut, status: "429"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := httpRequests.WithLabelValues(tt.route, tt.label, tt.status)
+			before := testutil.ToFloat64(counter)
+
+			Middleware(mux, tt.next).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+
+			if got := testutil.ToFloat64(counter) - before; got != 1 {
+				t.Errorf("http_requests_total{route=%q,method=%q,status=%q} increased by %v, want 1", tt.route, tt.label, tt.status, got)
+			}
+		})
+	}
+}
+
+func TestMethodLabel(t *testing.T) {
+	tests := []struct {
+		method string
+		want   string
+	}{
+		{method: http.MethodGet, want: http.MethodGet},
+		{method: http.MethodOptions, want: http.MethodOptions},
+		{method: "PROPFIND", want: "OTHER"},
+		{method: "get", want: "OTHER"},
+		{method: "X-RANDOM-1234", want: "OTHER"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			if got := methodLabel(tt.method); got != tt.want {
+				t.Errorf("methodLabel(%q) = %q, want %q", tt.method, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestObserveDB(t *testing.T) {
+	failures := dbFailures.WithLabelValues("test", "write")
+	before := testutil.ToFloat64(failures)
+
+	if err := ObserveDB("test", "write", time.Now(), nil); err != nil {
+		t.Errorf("ObserveDB() = %v, want nil", err)
+	}
+	errWrite := errors.New("disk full")
+	if err := ObserveDB("test", "write", time.Now(), errWrite); err != errWrite {
+		t.Errorf("ObserveDB() = %v, want %v", err, errWrite)
+	}
+
+	if got := testutil.ToFloat64(failures) - before; got != 1 {
+		t.Errorf("db_operation_failures_total increased by %v, want 1", got)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	CacheLookup("test", true)
+
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	for _, expected := range []string{`metal_fests_cache_lookups_total{cache="test",result="hit"}`, "go_goroutines"} {
+		if !strings.Contains(w.Body.String(), expected) {
+			t.Errorf("metrics are missing %q", expected)
+		}
+	}
+}
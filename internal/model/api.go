@@ -1,5 +1,7 @@
 package model
 
+import "fmt"
+
 type ValidateURLRequest struct {
 	URL string `json:"url"`
 }
@@ -11,7 +13,61 @@ type ValidateURLResponse struct {
 	URL    string `json:"url"`
 }
 
+// UpdateBandResponse answers a band update with the band as stored
 type UpdateBandResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
+	Band    Band   `json:"band"`
+}
+
+// UpdateFestivalResponse answers a festival update with the festival as stored
+type UpdateFestivalResponse struct {
+	Success  bool     `json:"success"`
+	Message  string   `json:"message"`
+	Festival Festival `json:"festival"`
+}
+
+// Codes of ErrorResponse, stable for the clients to branch on
+const (
+	ErrorCodeBadRequest   = "bad_request"
+	ErrorCodeInvalidJSON  = "invalid_json"
+	ErrorCodeUnauthorized = "unauthorized"
+	ErrorCodeForbidden    = "forbidden"
+	ErrorCodeValidation   = "validation_failed"
+	ErrorCodeNotFound     = "not_found"
+	ErrorCodeConflict     = "conflict"
+	ErrorCodeTooLarge     = "request_too_large"
+	ErrorCodeRateLimited  = "rate_limited"
+	ErrorCodeUnavailable  = "unavailable"
+	ErrorCodeInternal     = "internal_error"
+)
+
+// ErrorResponse is the body of every failed API request
+type ErrorResponse struct {
+	Code      string       `json:"code"`
+	Message   string       `json:"message"`
+	Fields    []FieldError `json:"fields,omitempty"`
+	RequestID string       `json:"requestId,omitempty"`
+}
+
+// FieldError describes why the value of a single field is not valid
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+func (e FieldError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Field, e.Message)
+}
+
+// HealthResponse answers the liveness probe
+type HealthResponse struct {
+	Status        string `json:"status"`
+	UptimeSeconds int64  `json:"uptimeSeconds"`
+}
+
+// ReadyResponse answers the readiness probe, with the outcome of each check
+type ReadyResponse struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks"`
 }
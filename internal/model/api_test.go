@@ -180,3 +180,21 @@ func TestValidateURLResponseJSONOmitEmpty(t *testing.T) {
 		t.Errorf("URL field should be present")
 	}
 }
+
+func TestErrorResponseJSON(t *testing.T) {
+	resp := ErrorResponse{
+		Code:    ErrorCodeValidation,
+		Message: "Failed to update band: validation failed: key: is required",
+		Fields:  []FieldError{{Field: "key", Message: "is required"}},
+	}
+
+	data, err := json.Marshal(resp)
+	if err != nil {
+		t.Fatalf("Failed to marshal ErrorResponse: %v", err)
+	}
+
+	want := `{"code":"validation_failed","message":"Failed to update band: validation failed: key: is required","fields":[{"field":"key","message":"is required"}]}`
+	if string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+}
@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type Band struct {
 	Key           string   `json:"key"`
 	Name          string   `json:"name"`
@@ -12,6 +14,12 @@ type Band struct {
 	Genres        []string `json:"genres"`
 	Members       []Member `json:"members"`
 	Reviewed      bool     `json:"reviewed"`
+	// Aliases are other spellings of the band name found on festival lineups
+	Aliases []string `json:"aliases,omitempty"`
+
+	Provenance ProvenanceMap `json:"provenance,omitempty"`
+	// LastChecked is when the updater last looked the band up
+	LastChecked *time.Time `json:"lastChecked,omitempty"`
 }
 
 type Member struct {
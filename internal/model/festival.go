@@ -1,5 +1,7 @@
 package model
 
+import "time"
+
 type Festival struct {
 	Key         string      `json:"key"`
 	Name        string      `json:"name"`
@@ -10,12 +12,39 @@ type Festival struct {
 	Website     string      `json:"website"`
 	Bands       []BandRef   `json:"bands"`
 	TicketPrice float64     `json:"ticketPrice"`
+
+	// PriceHistory lists every ticket price seen for the festival, oldest first
+	PriceHistory []PricePoint  `json:"priceHistory,omitempty"`
+	Provenance   ProvenanceMap `json:"provenance,omitempty"`
+}
+
+// PricePoint is a ticket price and when it was first seen
+type PricePoint struct {
+	Price      float64   `json:"price"`
+	ObservedAt time.Time `json:"observedAt"`
+}
+
+// RecordPrice sets the ticket price and adds it to the price history when it
+// differs from the last price recorded. It reports whether it was added.
+func (f *Festival) RecordPrice(price float64, observedAt time.Time) bool {
+	f.TicketPrice = price
+	n := len(f.PriceHistory)
+	if n > 0 && f.PriceHistory[n-1].Price == price {
+		return false
+	}
+	// Never append in place, the history may be shared with other copies of the festival
+	f.PriceHistory = append(f.PriceHistory[:n:n], PricePoint{Price: price, ObservedAt: observedAt})
+	return true
 }
 
 type BandRef struct {
 	Key  string `json:"key"`
 	Name string `json:"name"`
 	Size int    `json:"size"`
+	// PossiblyCancelled is set when the band is missing from the latest
+	// official lineup; Misses counts the consecutive updates it was missing from
+	PossiblyCancelled bool `json:"possiblyCancelled,omitempty"`
+	Misses            int  `json:"misses,omitempty"`
 }
 
 type Dates struct {
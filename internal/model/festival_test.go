@@ -2,7 +2,9 @@ package model
 
 import (
 	"encoding/json"
+	"reflect"
 	"testing"
+	"time"
 )
 
 func TestFestivalJSON(t *testing.T) {
@@ -223,3 +225,40 @@ func TestFestivalJSONTags(t *testing.T) {
 		}
 	}
 }
+
+func TestFestivalRecordPrice(t *testing.T) {
+	first := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	second := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	festival := Festival{TicketPrice: 0}
+	if !festival.RecordPrice(150, first) {
+		t.Error("RecordPrice() = false for the first price, want true")
+	}
+	if festival.RecordPrice(150, second) {
+		t.Error("RecordPrice() = true for an unchanged price, want false")
+	}
+	if !festival.RecordPrice(180, second) {
+		t.Error("RecordPrice() = false for a new price, want true")
+	}
+
+	expected := []PricePoint{{Price: 150, ObservedAt: first}, {Price: 180, ObservedAt: second}}
+	if festival.TicketPrice != 180 {
+		t.Errorf("TicketPrice = %v, want 180", festival.TicketPrice)
+	}
+	if !reflect.DeepEqual(festival.PriceHistory, expected) {
+		t.Errorf("PriceHistory = %+v, want %+v", festival.PriceHistory, expected)
+	}
+}
+
+func TestFestivalRecordPrice_DoesNotShareHistory(t *testing.T) {
+	history := make([]PricePoint, 1, 4)
+	history[0] = PricePoint{Price: 100}
+	original := Festival{PriceHistory: history}
+
+	copied := original
+	copied.RecordPrice(120, time.Now())
+
+	if history[:2][1].Price == 120 {
+		t.Error("RecordPrice() wrote into the history shared with the original festival")
+	}
+}
@@ -9,4 +9,11 @@ type AskOpenAIResponse struct {
 	TotalUsedTokens int
 	EstimatedCost   float64
 	UsedModel       shared.ResponsesModel
+	Citations       []Citation
+}
+
+// Citation is a web source the model referenced while answering
+type Citation struct {
+	URL   string `json:"url"`
+	Title string `json:"title,omitempty"`
 }
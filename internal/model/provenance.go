@@ -0,0 +1,77 @@
+package model
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// ProvenanceOrigin tells whether a field was filled by a person or by the AI updaters
+type ProvenanceOrigin string
+
+const (
+	ProvenanceManual ProvenanceOrigin = "manual"
+	ProvenanceAI     ProvenanceOrigin = "ai"
+)
+
+// Provenance records where the current value of a single field came from
+type Provenance struct {
+	Origin        ProvenanceOrigin `json:"origin"`
+	Sources       []Citation       `json:"sources,omitempty"`
+	Model         string           `json:"model,omitempty"`
+	PromptVersion string           `json:"promptVersion,omitempty"`
+	UpdatedAt     time.Time        `json:"updatedAt"`
+}
+
+// ProvenanceMap holds the provenance of each field, keyed by the field JSON name
+type ProvenanceMap map[string]Provenance
+
+// Set records the provenance of the given fields, copying the map so values
+// shared with other copies of the same record are never modified
+func (p ProvenanceMap) Set(provenance Provenance, fields ...string) ProvenanceMap {
+	updated := make(ProvenanceMap, len(p)+len(fields))
+	for field, value := range p {
+		updated[field] = value
+	}
+	for _, field := range fields {
+		updated[field] = provenance
+	}
+	return updated
+}
+
+// untrackedFields are never given a provenance
+var untrackedFields = map[string]bool{
+	"key":          true,
+	"reviewed":     true,
+	"provenance":   true,
+	"lastChecked":  true,
+	"priceHistory": true,
+}
+
+// ChangedFields returns the JSON names of the fields that differ between two
+// values of the same struct type, ignoring the fields provenance does not track
+func ChangedFields[T any](previous, updated T) []string {
+	previousValue := reflect.ValueOf(previous)
+	updatedValue := reflect.ValueOf(updated)
+	valueType := previousValue.Type()
+
+	var fields []string
+	for i := 0; i < valueType.NumField(); i++ {
+		name := strings.Split(valueType.Field(i).Tag.Get("json"), ",")[0]
+		if name == "" || name == "-" || untrackedFields[name] {
+			continue
+		}
+		if !sameValue(previousValue.Field(i), updatedValue.Field(i)) {
+			fields = append(fields, name)
+		}
+	}
+	return fields
+}
+
+// sameValue compares two field values, treating nil and empty slices as equal
+func sameValue(previous, updated reflect.Value) bool {
+	if previous.Kind() == reflect.Slice && previous.Len() == 0 && updated.Len() == 0 {
+		return true
+	}
+	return reflect.DeepEqual(previous.Interface(), updated.Interface())
+}
@@ -0,0 +1,115 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProvenanceMapSet(t *testing.T) {
+	updatedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	original := ProvenanceMap{"country": {Origin: ProvenanceManual, UpdatedAt: updatedAt}}
+	ai := Provenance{Origin: ProvenanceAI, Model: "gpt-5", PromptVersion: "band-v2", UpdatedAt: updatedAt}
+
+	updated := original.Set(ai, "country", "logo")
+
+	if updated["country"].Origin != ProvenanceAI || updated["logo"].Origin != ProvenanceAI {
+		t.Errorf("Set() = %v, want ai provenance for country and logo", updated)
+	}
+	if original["country"].Origin != ProvenanceManual || len(original) != 1 {
+		t.Errorf("Set() modified the original map: %v", original)
+	}
+
+	var empty ProvenanceMap
+	if got := empty.Set(ai, "name"); len(got) != 1 {
+		t.Errorf("Set() on nil map = %v, want one entry", got)
+	}
+}
+
+func TestChangedFields(t *testing.T) {
+	base := Band{
+		Key:     "iron-maiden",
+		Name:    "Iron Maiden",
+		Country: "United Kingdom",
+		Genres:  []string{"Heavy Metal"},
+		Members: []Member{},
+	}
+
+	tests := []struct {
+		name     string
+		update   func(b *Band)
+		expected []string
+	}{
+		{
+			name:     "no changes",
+			update:   func(b *Band) {},
+			expected: nil,
+		},
+		{
+			name:     "nil and empty slices are equal",
+			update:   func(b *Band) { b.Members = nil },
+			expected: nil,
+		},
+		{
+			name: "untracked fields are ignored",
+			update: func(b *Band) {
+				b.Key = "other"
+				b.Reviewed = true
+				b.Provenance = ProvenanceMap{"name": {Origin: ProvenanceAI}}
+			},
+			expected: nil,
+		},
+		{
+			name: "changed fields use json names",
+			update: func(b *Band) {
+				b.Country = "England"
+				b.HeadlineImage = "https://example.com/image.jpg"
+				b.Genres = []string{"Heavy Metal", "NWOBHM"}
+			},
+			expected: []string{"country", "headlineImage", "genres"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			updated := base
+			tt.update(&updated)
+			result := ChangedFields(base, updated)
+			if !reflect.DeepEqual(result, tt.expected) {
+				t.Errorf("ChangedFields() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestProvenanceJSON(t *testing.T) {
+	band := Band{Key: "test", Name: "Test"}
+	data, err := json.Marshal(band)
+	if err != nil {
+		t.Fatalf("Failed to marshal band: %v", err)
+	}
+	var fields map[string]any
+	if err := json.Unmarshal(data, &fields); err != nil {
+		t.Fatalf("Failed to unmarshal band: %v", err)
+	}
+	if _, ok := fields["provenance"]; ok {
+		t.Errorf("Band without provenance should omit the field, got %s", data)
+	}
+
+	band.Provenance = ProvenanceMap{}.Set(Provenance{
+		Origin:  ProvenanceAI,
+		Sources: []Citation{{URL: "https://example.com", Title: "Example"}},
+	}, "country")
+	data, err = json.Marshal(band)
+	if err != nil {
+		t.Fatalf("Failed to marshal band: %v", err)
+	}
+	var unmarshaled Band
+	if err := json.Unmarshal(data, &unmarshaled); err != nil {
+		t.Fatalf("Failed to unmarshal band: %v", err)
+	}
+	if !reflect.DeepEqual(unmarshaled.Provenance, band.Provenance) {
+		t.Errorf("Provenance mismatch: got %v, want %v", unmarshaled.Provenance, band.Provenance)
+	}
+}
@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// ReviewKind is the kind of record waiting in the pending-review queue
+type ReviewKind string
+
+const (
+	ReviewBand     ReviewKind = "band"
+	ReviewFestival ReviewKind = "festival"
+)
+
+// PendingReview is an AI result held back from the database until someone reviews it
+type PendingReview struct {
+	Kind        ReviewKind `json:"kind"`
+	Key         string     `json:"key"`
+	Band        *Band      `json:"band,omitempty"`
+	Festival    *Festival  `json:"festival,omitempty"`
+	Confidence  float64    `json:"confidence"`
+	Reasons     []string   `json:"reasons,omitempty"`
+	SubmittedAt time.Time  `json:"submittedAt"`
+}
@@ -4,16 +4,21 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
+	"log/slog"
 	"net/http"
 	"os"
+	"time"
 
 	"github.com/openai/openai-go/v3"
 	"github.com/openai/openai-go/v3/option"
 	"github.com/openai/openai-go/v3/responses"
 	"github.com/openai/openai-go/v3/shared"
 
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
 	"github.com/neovasili/metal-fests/internal/model"
+	"github.com/neovasili/metal-fests/internal/tracing"
 )
 
 var modelPricing = map[string]struct {
@@ -29,19 +34,30 @@ var modelPricing = map[string]struct {
 const (
 	PrimaryModel  = openai.ChatModelGPT4oMini
 	FallbackModel = openai.ChatModelGPT4_1
+
+	// DefaultRequestTimeout bounds a single web-search request
+	DefaultRequestTimeout = 2 * time.Minute
 )
 
 type OpenAIClient struct {
-	client        openai.Client
-	responsesBase responses.ResponseNewParams
+	client         openai.Client
+	responsesBase  responses.ResponseNewParams
+	limiter        *RateLimiter
+	requestTimeout time.Duration
+	primaryModel   shared.ResponsesModel
+	fallbackModel  shared.ResponsesModel
 }
 
 func NewOpenAIClient(apiKey string) *OpenAIClient {
 	client := openai.NewClient(
 		option.WithAPIKey(apiKey),
+		option.WithHTTPClient(&http.Client{Transport: tracing.Transport(nil)}),
 	)
 	return &OpenAIClient{
-		client: client,
+		client:         client,
+		requestTimeout: DefaultRequestTimeout,
+		primaryModel:   PrimaryModel,
+		fallbackModel:  FallbackModel,
 		responsesBase: responses.ResponseNewParams{
 			Input:       responses.ResponseNewParamsInputUnion{},
 			Temperature: openai.Float(0.0),
@@ -56,6 +72,34 @@ func NewOpenAIClient(apiKey string) *OpenAIClient {
 	}
 }
 
+// SetRequestsPerMinute limits how many requests the client starts per minute.
+// It is safe to share the client between goroutines once configured.
+func (c *OpenAIClient) SetRequestsPerMinute(requestsPerMinute int) {
+	c.limiter = NewRateLimiter(requestsPerMinute)
+}
+
+// SetRequestTimeout bounds how long a single request may take.
+// A non-positive value leaves requests bounded only by the caller's context.
+func (c *OpenAIClient) SetRequestTimeout(timeout time.Duration) {
+	c.requestTimeout = timeout
+}
+
+// SetModels changes the model requests use, and the one they fall back to
+// when rate limited. Empty names keep the current models.
+func (c *OpenAIClient) SetModels(primary, fallback string) {
+	if primary != "" {
+		c.primaryModel = primary
+	}
+	if fallback != "" {
+		c.fallbackModel = fallback
+	}
+}
+
+// Models returns the model requests use and the one they fall back to
+func (c *OpenAIClient) Models() (primary, fallback shared.ResponsesModel) {
+	return c.primaryModel, c.fallbackModel
+}
+
 // Estimate the cost of a request based on model and token usage
 func estimateCost(model string, inTokens, outTokens int) float64 {
 	pr, ok := modelPricing[model]
@@ -87,8 +131,35 @@ func isRateLimitError(err error) bool {
 	return false
 }
 
-func (c *OpenAIClient) AskOpenAI(userPrompt string, jsonSchema map[string]any, modelToUse shared.ResponsesModel, dryRun bool) (*model.AskOpenAIResponse, error) {
-	ctx := context.Background()
+// Send a single request, waiting for the rate limiter and applying the per-request timeout
+func (c *OpenAIClient) newResponse(ctx context.Context, request responses.ResponseNewParams) (response *responses.Response, err error) {
+	ctx, span := tracing.Tracer().Start(ctx, "chat "+request.Model,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(semconv.GenAIProviderNameOpenAI, semconv.GenAIOperationNameChat, semconv.GenAIRequestModel(request.Model)),
+	)
+	defer func() {
+		if response != nil {
+			span.SetAttributes(
+				semconv.GenAIResponseModel(response.Model),
+				semconv.GenAIUsageInputTokens(int(response.Usage.InputTokens)),
+				semconv.GenAIUsageOutputTokens(int(response.Usage.OutputTokens)),
+			)
+		}
+		tracing.End(span, err)
+	}()
+
+	if err := c.limiter.Wait(ctx); err != nil {
+		return nil, err
+	}
+	if c.requestTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.requestTimeout)
+		defer cancel()
+	}
+	return c.client.Responses.New(ctx, request)
+}
+
+func (c *OpenAIClient) AskOpenAI(ctx context.Context, userPrompt string, jsonSchema map[string]any, modelToUse shared.ResponsesModel, dryRun bool) (*model.AskOpenAIResponse, error) {
 	request := c.responsesBase
 	inputText := userPrompt
 	request.Text = responses.ResponseTextConfigParam{
@@ -105,23 +176,23 @@ func (c *OpenAIClient) AskOpenAI(userPrompt string, jsonSchema map[string]any, m
 	request.Model = modelToUse
 
 	if dryRun {
-		// Print the request as JSON to stdout
-		jsonBytes, err := json.MarshalIndent(request, "", "  ")
+		// Log the request that would have been sent
+		jsonBytes, err := json.Marshal(request)
 		if err != nil {
 			return nil, err
 		}
-		fmt.Println(string(jsonBytes))
+		slog.InfoContext(ctx, "dry-run mode, OpenAI request not sent", "model", request.Model, "request", string(jsonBytes))
 		return nil, nil
 	}
-	fmt.Println(inputText)
+	slog.DebugContext(ctx, "sending OpenAI request", "model", request.Model, "prompt", inputText)
 
 	usedModel := request.Model
-	response, err := c.client.Responses.New(ctx, request)
+	response, err := c.newResponse(ctx, request)
 	if err != nil {
-		if isRateLimitError(err) {
-			usedModel = FallbackModel
+		if isRateLimitError(err) && ctx.Err() == nil {
+			usedModel = c.fallbackModel
 			request.Model = usedModel
-			response, err = c.client.Responses.New(ctx, request)
+			response, err = c.newResponse(ctx, request)
 			if err != nil {
 				return nil, err
 			}
@@ -130,14 +201,37 @@ func (c *OpenAIClient) AskOpenAI(userPrompt string, jsonSchema map[string]any, m
 		}
 	}
 
-	fmt.Printf("%+v\n\n", response.OutputText())
-
-	return &model.AskOpenAIResponse{
+	answer := &model.AskOpenAIResponse{
 		OutputText:      response.OutputText(),
 		TotalUsedTokens: int(response.Usage.TotalTokens),
 		EstimatedCost:   estimateCost(request.Model, int(response.Usage.InputTokens), int(response.Usage.OutputTokens)),
 		UsedModel:       usedModel,
-	}, nil
+		Citations:       responseCitations(response),
+	}
+	slog.DebugContext(ctx, "OpenAI response", "output", answer.OutputText)
+	slog.InfoContext(ctx, "OpenAI request done", "model", answer.UsedModel, "tokens", answer.TotalUsedTokens, "cost", answer.EstimatedCost)
+	return answer, nil
+}
+
+// Collect the web sources the model cited in its answer, without duplicates
+func responseCitations(response *responses.Response) []model.Citation {
+	var citations []model.Citation
+	seen := make(map[string]bool)
+	for _, output := range response.Output {
+		for _, content := range output.Content {
+			if content.Type != "output_text" {
+				continue
+			}
+			for _, annotation := range content.Annotations {
+				if annotation.Type != "url_citation" || annotation.URL == "" || seen[annotation.URL] {
+					continue
+				}
+				seen[annotation.URL] = true
+				citations = append(citations, model.Citation{URL: annotation.URL, Title: annotation.Title})
+			}
+		}
+	}
+	return citations
 }
 
 func LoadPromptFile(filename string) (string, error) {
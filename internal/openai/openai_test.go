@@ -1,12 +1,24 @@
 package openai
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
+	"net/http/httptest"
 	"os"
 	"testing"
 
 	"github.com/openai/openai-go/v3"
+	"github.com/openai/openai-go/v3/option"
+	"github.com/openai/openai-go/v3/responses"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/neovasili/metal-fests/internal/model"
 )
 
 func TestLoadPromptFile(t *testing.T) {
@@ -141,5 +153,107 @@ func TestIsRateLimitError(t *testing.T) {
 	}
 }
 
+func TestResponseCitations(t *testing.T) {
+	tests := []struct {
+		name     string
+		response string
+		expected []model.Citation
+	}{
+		{
+			name:     "No output",
+			response: `{"output": []}`,
+			expected: nil,
+		},
+		{
+			name: "Url citations are collected once",
+			response: `{"output": [
+				{"type": "web_search_call", "id": "ws_1", "status": "completed"},
+				{"type": "message", "id": "msg_1", "role": "assistant", "status": "completed", "content": [
+					{"type": "output_text", "text": "{}", "annotations": [
+						{"type": "url_citation", "url": "https://ironmaiden.com", "title": "Iron Maiden", "start_index": 0, "end_index": 1},
+						{"type": "file_citation", "file_id": "file_1", "index": 0},
+						{"type": "url_citation", "url": "https://en.wikipedia.org/wiki/Iron_Maiden", "title": "Wikipedia", "start_index": 0, "end_index": 1},
+						{"type": "url_citation", "url": "https://ironmaiden.com", "title": "Iron Maiden again", "start_index": 1, "end_index": 2}
+					]}
+				]}
+			]}`,
+			expected: []model.Citation{
+				{URL: "https://ironmaiden.com", Title: "Iron Maiden"},
+				{URL: "https://en.wikipedia.org/wiki/Iron_Maiden", Title: "Wikipedia"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var response responses.Response
+			if err := json.Unmarshal([]byte(tt.response), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+
+			result := responseCitations(&response)
+			if len(result) != len(tt.expected) {
+				t.Fatalf("responseCitations() = %v, want %v", result, tt.expected)
+			}
+			for i := range result {
+				if result[i] != tt.expected[i] {
+					t.Errorf("responseCitations()[%d] = %v, want %v", i, result[i], tt.expected[i])
+				}
+			}
+		})
+	}
+}
+
 // Note: Integration tests for AskOpenAI would require a real API key and network access.
 // You can add a test with a dryRun flag to check request formatting if needed.
+
+func TestSetModels(t *testing.T) {
+	client := NewOpenAIClient("test")
+	if primary, fallback := client.Models(); primary != PrimaryModel || fallback != FallbackModel {
+		t.Errorf("Models() = %s, %s, want the defaults", primary, fallback)
+	}
+
+	client.SetModels(openai.ChatModelGPT4_1Mini, "")
+	if primary, fallback := client.Models(); primary != openai.ChatModelGPT4_1Mini || fallback != FallbackModel {
+		t.Errorf("Models() = %s, %s, want the primary model changed only", primary, fallback)
+	}
+}
+
+func TestNewResponse_Span(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	previousProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previousProvider) })
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"id":"resp_1","object":"response","model":"gpt-4o-mini-2024-07-18","output":[],
+			"usage":{"input_tokens":120,"output_tokens":30,"total_tokens":150}}`)
+	}))
+	defer server.Close()
+
+	client := NewOpenAIClient("test")
+	client.client = openai.NewClient(option.WithAPIKey("test"), option.WithBaseURL(server.URL), option.WithMaxRetries(0))
+	if _, err := client.newResponse(context.Background(), responses.ResponseNewParams{Model: PrimaryModel}); err != nil {
+		t.Fatalf("newResponse() failed: %v", err)
+	}
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("newResponse() recorded %d spans, want 1", len(spans))
+	}
+	attrs := make(map[attribute.Key]string)
+	for _, attr := range spans[0].Attributes() {
+		attrs[attr.Key] = attr.Value.Emit()
+	}
+	for key, want := range map[attribute.Key]string{
+		"gen_ai.request.model":       PrimaryModel,
+		"gen_ai.response.model":      "gpt-4o-mini-2024-07-18",
+		"gen_ai.usage.input_tokens":  "120",
+		"gen_ai.usage.output_tokens": "30",
+	} {
+		if attrs[key] != want {
+			t.Errorf("span attribute %s = %q, want %q", key, attrs[key], want)
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package openai
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+)
+
+// VariableType is the type a prompt variable must be rendered with
+type VariableType string
+
+const (
+	StringVariable VariableType = "string"
+	IntVariable    VariableType = "int"
+)
+
+// Prompt is a prompt template with a declared set of typed variables.
+//
+// Prompt files start with a front matter block declaring the version and
+// variables, followed by a text/template body:
+//
+//	---
+//	version: band-v2
+//	variables: BandName, EditionYear:int
+//	---
+//	Search metal band "{{ .BandName }}"
+//
+// Variables default to the string type.
+type Prompt struct {
+	Name      string
+	Version   string
+	Variables map[string]VariableType
+	template  *template.Template
+}
+
+// LoadPrompt reads and parses a prompt file
+func LoadPrompt(filename string) (*Prompt, error) {
+	content, err := LoadPromptFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	prompt, err := ParsePrompt(filename, content)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filename, err)
+	}
+	return prompt, nil
+}
+
+// ParsePrompt parses prompt content, failing when the template references a
+// variable that is not declared in the front matter
+func ParsePrompt(name, content string) (*Prompt, error) {
+	header, body, err := splitFrontMatter(content)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt := &Prompt{
+		Name:      name,
+		Variables: make(map[string]VariableType),
+	}
+	for _, line := range strings.Split(header, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		key, value, found := strings.Cut(line, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid front matter line %q", line)
+		}
+		value = strings.TrimSpace(value)
+		switch strings.TrimSpace(key) {
+		case "version":
+			prompt.Version = value
+		case "variables":
+			if err := prompt.declareVariables(value); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unknown front matter key %q", key)
+		}
+	}
+	if prompt.Version == "" {
+		return nil, fmt.Errorf("prompt version is required")
+	}
+
+	tmpl, err := template.New(name).Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, err
+	}
+	for _, field := range referencedFields(tmpl.Root) {
+		if _, declared := prompt.Variables[field]; !declared {
+			return nil, fmt.Errorf("template uses undeclared variable %q", field)
+		}
+	}
+	prompt.template = tmpl
+
+	return prompt, nil
+}
+
+// Render fills the prompt, failing on missing, unknown or mistyped variables
+func (p *Prompt) Render(variables map[string]any) (string, error) {
+	for name, value := range variables {
+		variableType, declared := p.Variables[name]
+		if !declared {
+			return "", fmt.Errorf("prompt %s: unknown variable %q", p.Version, name)
+		}
+		if !hasType(value, variableType) {
+			return "", fmt.Errorf("prompt %s: variable %q must be of type %s, got %T", p.Version, name, variableType, value)
+		}
+	}
+	missing := make([]string, 0)
+	for name := range p.Variables {
+		if _, provided := variables[name]; !provided {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		sort.Strings(missing)
+		return "", fmt.Errorf("prompt %s: missing variables %s", p.Version, strings.Join(missing, ", "))
+	}
+
+	var buf bytes.Buffer
+	if err := p.template.Execute(&buf, variables); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// declareVariables parses a comma-separated list of name[:type] declarations
+func (p *Prompt) declareVariables(declarations string) error {
+	for _, declaration := range strings.Split(declarations, ",") {
+		name, variableType, found := strings.Cut(strings.TrimSpace(declaration), ":")
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !found {
+			variableType = string(StringVariable)
+		}
+		switch VariableType(strings.TrimSpace(variableType)) {
+		case StringVariable, IntVariable:
+			p.Variables[name] = VariableType(strings.TrimSpace(variableType))
+		default:
+			return fmt.Errorf("variable %q has unsupported type %q", name, variableType)
+		}
+	}
+	return nil
+}
+
+func hasType(value any, variableType VariableType) bool {
+	switch variableType {
+	case IntVariable:
+		_, ok := value.(int)
+		return ok
+	default:
+		_, ok := value.(string)
+		return ok
+	}
+}
+
+// splitFrontMatter separates the front matter block from the template body
+func splitFrontMatter(content string) (string, string, error) {
+	const delimiter = "---"
+
+	content = strings.TrimLeft(content, "\n")
+	if !strings.HasPrefix(content, delimiter+"\n") {
+		return "", "", fmt.Errorf("prompt must start with a %s front matter block", delimiter)
+	}
+	rest := strings.TrimPrefix(content, delimiter+"\n")
+	header, body, found := strings.Cut(rest, "\n"+delimiter+"\n")
+	if !found {
+		return "", "", fmt.Errorf("unterminated front matter block")
+	}
+	return header, strings.TrimLeft(body, "\n"), nil
+}
+
+// referencedFields lists the top-level fields a template refers to
+func referencedFields(node parse.Node) []string {
+	fields := make([]string, 0)
+	var walk func(parse.Node)
+	walk = func(node parse.Node) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg)
+			}
+		case *parse.FieldNode:
+			fields = append(fields, n.Ident[0])
+		case *parse.ChainNode:
+			walk(n.Node)
+		case *parse.IfNode:
+			walk(&n.BranchNode)
+		case *parse.RangeNode:
+			walk(&n.BranchNode)
+		case *parse.WithNode:
+			walk(&n.BranchNode)
+		case *parse.BranchNode:
+			walk(n.Pipe)
+			walk(n.List)
+			walk(n.ElseList)
+		case *parse.TemplateNode:
+			walk(n.Pipe)
+		}
+	}
+	walk(node)
+	return fields
+}
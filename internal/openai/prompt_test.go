@@ -0,0 +1,173 @@
+package openai
+
+import (
+	"os"
+	"strings"
+	"testing"
+)
+
+const testPrompt = `---
+version: test-v1
+variables: BandName, EditionYear:int
+---
+Search "{{ .BandName }}" for {{ .EditionYear }}{{ if .BandName }} now{{ end }}`
+
+func TestParsePrompt(t *testing.T) {
+	prompt, err := ParsePrompt("test", testPrompt)
+	if err != nil {
+		t.Fatalf("ParsePrompt failed: %v", err)
+	}
+	if prompt.Version != "test-v1" {
+		t.Errorf("expected version test-v1, got %q", prompt.Version)
+	}
+	if prompt.Variables["BandName"] != StringVariable || prompt.Variables["EditionYear"] != IntVariable {
+		t.Errorf("unexpected variables: %v", prompt.Variables)
+	}
+
+	rendered, err := prompt.Render(map[string]any{"BandName": "Metallica", "EditionYear": 2026})
+	if err != nil {
+		t.Fatalf("Render failed: %v", err)
+	}
+	if rendered != `Search "Metallica" for 2026 now` {
+		t.Errorf("unexpected rendered prompt: %q", rendered)
+	}
+}
+
+func TestParsePrompt_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		errText string
+	}{
+		{
+			name:    "Missing front matter",
+			content: "Search {{ .BandName }}",
+			errText: "front matter",
+		},
+		{
+			name:    "Unterminated front matter",
+			content: "---\nversion: v1\nSearch",
+			errText: "unterminated",
+		},
+		{
+			name:    "Missing version",
+			content: "---\nvariables: BandName\n---\nSearch {{ .BandName }}",
+			errText: "version is required",
+		},
+		{
+			name:    "Misspelled placeholder",
+			content: "---\nversion: v1\nvariables: BandName\n---\nSearch {{ .BandNmae }}",
+			errText: `undeclared variable "BandNmae"`,
+		},
+		{
+			name:    "Undeclared variable inside a block",
+			content: "---\nversion: v1\nvariables: BandName\n---\n{{ if .Year }}{{ .BandName }}{{ end }}",
+			errText: `undeclared variable "Year"`,
+		},
+		{
+			name:    "Unsupported type",
+			content: "---\nversion: v1\nvariables: Year:date\n---\n{{ .Year }}",
+			errText: "unsupported type",
+		},
+		{
+			name:    "Unknown front matter key",
+			content: "---\nversion: v1\nmodel: gpt\n---\nSearch",
+			errText: "unknown front matter key",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParsePrompt("test", tt.content)
+			if err == nil || !strings.Contains(err.Error(), tt.errText) {
+				t.Errorf("expected error containing %q, got %v", tt.errText, err)
+			}
+		})
+	}
+}
+
+func TestPromptRender_Errors(t *testing.T) {
+	prompt, err := ParsePrompt("test", testPrompt)
+	if err != nil {
+		t.Fatalf("ParsePrompt failed: %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		variables map[string]any
+		errText   string
+	}{
+		{
+			name:      "Missing variable",
+			variables: map[string]any{"BandName": "Metallica"},
+			errText:   "missing variables EditionYear",
+		},
+		{
+			name:      "Unknown variable",
+			variables: map[string]any{"BandName": "Metallica", "EditionYear": 2026, "Country": "USA"},
+			errText:   `unknown variable "Country"`,
+		},
+		{
+			name:      "Wrong type",
+			variables: map[string]any{"BandName": "Metallica", "EditionYear": "2026"},
+			errText:   `"EditionYear" must be of type int`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := prompt.Render(tt.variables)
+			if err == nil || !strings.Contains(err.Error(), tt.errText) {
+				t.Errorf("expected error containing %q, got %v", tt.errText, err)
+			}
+		})
+	}
+}
+
+func TestLoadPrompt_RepositoryPrompts(t *testing.T) {
+	tests := []struct {
+		filename  string
+		variables map[string]any
+	}{
+		{
+			filename:  "../../scripts/band_prompt.md",
+			variables: map[string]any{"BandName": "Metallica"},
+		},
+		{
+			filename: "../../scripts/festival_prompt.md",
+			variables: map[string]any{
+				"FestivalName":     "Wacken Open Air",
+				"FestivalLocation": "Wacken, Germany",
+				"FestivalURL":      "https://www.wacken.com",
+				"EditionYear":      2027,
+			},
+		},
+		{
+			filename: "../../scripts/festival_discovery_prompt.md",
+			variables: map[string]any{
+				"FestivalName": "Wacken Open Air",
+				"FestivalURL":  "",
+				"EditionYear":  2027,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.filename, func(t *testing.T) {
+			if _, err := os.Stat(tt.filename); err != nil {
+				t.Skipf("prompt file not available: %v", err)
+			}
+			prompt, err := LoadPrompt(tt.filename)
+			if err != nil {
+				t.Fatalf("LoadPrompt failed: %v", err)
+			}
+			rendered, err := prompt.Render(tt.variables)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if strings.Contains(rendered, "{{") {
+				t.Errorf("rendered prompt still contains placeholders: %s", rendered)
+			}
+		})
+	}
+}
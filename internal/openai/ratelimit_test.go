@@ -0,0 +1,58 @@
+package openai
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewRateLimiter_Disabled(t *testing.T) {
+	if NewRateLimiter(0) != nil {
+		t.Error("expected nil limiter for 0 requests per minute")
+	}
+
+	// A nil limiter must never block
+	var limiter *RateLimiter
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Errorf("expected no error from nil limiter, got %v", err)
+	}
+}
+
+func TestRateLimiter_WaitCancelled(t *testing.T) {
+	limiter := NewRateLimiter(1)
+	if err := limiter.Wait(context.Background()); err != nil {
+		t.Fatalf("first request should not wait, got %v", err)
+	}
+
+	// The next slot is a minute away, so a cancelled context must return first
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := limiter.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestRateLimiter_Reserve(t *testing.T) {
+	limiter := NewRateLimiter(60)
+	now := time.Now()
+
+	tests := []struct {
+		name     string
+		at       time.Time
+		expected time.Duration
+	}{
+		{name: "First request starts immediately", at: now, expected: 0},
+		{name: "Second request waits one interval", at: now, expected: time.Second},
+		{name: "Third request waits two intervals", at: now, expected: 2 * time.Second},
+		{name: "Idle limiter does not accumulate credit", at: now.Add(time.Minute), expected: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if wait := limiter.reserve(tt.at); wait != tt.expected {
+				t.Errorf("reserve() = %v, want %v", wait, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,137 @@
+package openai
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// GenerateSchema derives the JSON schema used for structured outputs from a
+// tagged Go type, so the schema sent to the model and the struct the response
+// is parsed into cannot disagree.
+//
+// Property names come from the `json` tag. Every property is required and
+// objects do not allow additional properties, as structured outputs expect.
+// Pointer fields are nullable. Extra constraints come from the `jsonschema`
+// tag, e.g. `jsonschema:"minItems=2,maxItems=4"` or `jsonschema:"enum=1|2|3"`,
+// and `jsonschema:"-"` leaves a field out. A `description` tag documents the
+// property for the model.
+func GenerateSchema(v any) map[string]any {
+	return schemaForType(reflect.TypeOf(v))
+}
+
+func schemaForType(t reflect.Type) map[string]any {
+	nullable := false
+	if t.Kind() == reflect.Pointer {
+		nullable = true
+		t = t.Elem()
+	}
+
+	schema := map[string]any{}
+	switch t.Kind() {
+	case reflect.String:
+		schema["type"] = "string"
+	case reflect.Bool:
+		schema["type"] = "boolean"
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		schema["type"] = "integer"
+	case reflect.Float32, reflect.Float64:
+		schema["type"] = "number"
+	case reflect.Slice, reflect.Array:
+		schema["type"] = "array"
+		schema["items"] = schemaForType(t.Elem())
+	case reflect.Struct:
+		schema = schemaForStruct(t)
+	default:
+		panic(fmt.Sprintf("openai: unsupported schema type %s", t))
+	}
+
+	if nullable {
+		schema["type"] = []any{schema["type"], "null"}
+	}
+	return schema
+}
+
+func schemaForStruct(t reflect.Type) map[string]any {
+	properties := map[string]any{}
+	required := make([]string, 0, t.NumField())
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() || field.Tag.Get("jsonschema") == "-" {
+			continue
+		}
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		property := schemaForType(field.Type)
+		if description := field.Tag.Get("description"); description != "" {
+			property["description"] = description
+		}
+		applyConstraints(property, field)
+
+		properties[name] = property
+		required = append(required, name)
+	}
+
+	return map[string]any{
+		"type":                 "object",
+		"properties":           properties,
+		"required":             required,
+		"additionalProperties": false,
+	}
+}
+
+// applyConstraints copies the `jsonschema` tag options onto a property
+func applyConstraints(property map[string]any, field reflect.StructField) {
+	tag := field.Tag.Get("jsonschema")
+	if tag == "" {
+		return
+	}
+
+	for _, option := range strings.Split(tag, ",") {
+		key, value, _ := strings.Cut(option, "=")
+		switch key {
+		case "minItems", "maxItems", "minLength", "maxLength", "minimum", "maximum":
+			number, err := strconv.Atoi(value)
+			if err != nil {
+				panic(fmt.Sprintf("openai: invalid %s %q on field %s", key, value, field.Name))
+			}
+			property[key] = number
+		case "enum":
+			property["enum"] = enumValues(field, strings.Split(value, "|"))
+		default:
+			panic(fmt.Sprintf("openai: unknown jsonschema option %q on field %s", key, field.Name))
+		}
+	}
+}
+
+// enumValues converts enum options to the field's JSON type
+func enumValues(field reflect.StructField, options []string) []any {
+	kind := field.Type.Kind()
+	if kind == reflect.Pointer || kind == reflect.Slice {
+		kind = field.Type.Elem().Kind()
+	}
+
+	values := make([]any, 0, len(options))
+	for _, option := range options {
+		switch kind {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			number, err := strconv.Atoi(option)
+			if err != nil {
+				panic(fmt.Sprintf("openai: invalid enum value %q on field %s", option, field.Name))
+			}
+			values = append(values, number)
+		default:
+			values = append(values, option)
+		}
+	}
+	return values
+}
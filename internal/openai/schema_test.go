@@ -0,0 +1,110 @@
+package openai
+
+import (
+	"reflect"
+	"testing"
+)
+
+type schemaTestMember struct {
+	Name string `json:"name"`
+	Role string `json:"role,omitempty"`
+}
+
+type schemaTestResult struct {
+	Name     string             `json:"name" description:"Official name, in title case"`
+	Genres   []string           `json:"genres" jsonschema:"minItems=2,maxItems=4"`
+	Tier     int                `json:"tier" jsonschema:"enum=1|2|3"`
+	Price    *float64           `json:"price"`
+	Active   bool               `json:"active"`
+	Members  []schemaTestMember `json:"members"`
+	Internal string             `json:"-"`
+	Ignored  string             `json:"ignored" jsonschema:"-"`
+	hidden   string
+}
+
+func TestGenerateSchema(t *testing.T) {
+	schema := GenerateSchema(schemaTestResult{hidden: "unused"})
+
+	if schema["type"] != "object" || schema["additionalProperties"] != false {
+		t.Fatalf("expected a closed object schema, got %v", schema)
+	}
+
+	expectedRequired := []string{"name", "genres", "tier", "price", "active", "members"}
+	if !reflect.DeepEqual(schema["required"], expectedRequired) {
+		t.Errorf("required = %v, want %v", schema["required"], expectedRequired)
+	}
+
+	properties := schema["properties"].(map[string]any)
+	if len(properties) != len(expectedRequired) {
+		t.Errorf("expected %d properties, got %d", len(expectedRequired), len(properties))
+	}
+
+	tests := []struct {
+		name     string
+		property string
+		expected map[string]any
+	}{
+		{
+			name:     "String with description",
+			property: "name",
+			expected: map[string]any{"type": "string", "description": "Official name, in title case"},
+		},
+		{
+			name:     "Array with item limits",
+			property: "genres",
+			expected: map[string]any{"type": "array", "items": map[string]any{"type": "string"}, "minItems": 2, "maxItems": 4},
+		},
+		{
+			name:     "Integer enum",
+			property: "tier",
+			expected: map[string]any{"type": "integer", "enum": []any{1, 2, 3}},
+		},
+		{
+			name:     "Nullable number",
+			property: "price",
+			expected: map[string]any{"type": []any{"number", "null"}},
+		},
+		{
+			name:     "Boolean",
+			property: "active",
+			expected: map[string]any{"type": "boolean"},
+		},
+		{
+			name:     "Array of objects",
+			property: "members",
+			expected: map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"name": map[string]any{"type": "string"},
+						"role": map[string]any{"type": "string"},
+					},
+					"required":             []string{"name", "role"},
+					"additionalProperties": false,
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !reflect.DeepEqual(properties[tt.property], tt.expected) {
+				t.Errorf("property %q = %v, want %v", tt.property, properties[tt.property], tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateSchema_InvalidTag(t *testing.T) {
+	type invalid struct {
+		Items []string `json:"items" jsonschema:"minItems=two"`
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Error("expected a panic for an invalid jsonschema tag")
+		}
+	}()
+	GenerateSchema(invalid{})
+}
@@ -0,0 +1,138 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/data"
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+// Server is the HTTP server of the application. It reports itself ready
+// while it accepts requests, and not ready as soon as it starts shutting down.
+type Server struct {
+	httpServer *http.Server
+	opts       Options
+	out        io.Writer
+	ready      atomic.Bool
+	startedAt  time.Time
+	// serveDir holds the files of the application
+	serveDir string
+}
+
+// Handler returns the handler serving the application and its API
+func (s *Server) Handler() http.Handler {
+	return s.httpServer.Handler
+}
+
+// Ready tells whether the server accepts requests
+func (s *Server) Ready() bool {
+	return s.ready.Load()
+}
+
+// Serve accepts connections on listener, over TLS when the configuration has
+// a certificate, until ctx is done, then shuts down
+// gracefully: it reports not ready, keeps serving for the drain delay, waits
+// for the in-flight requests and flushes the pending database writes, all
+// within the shutdown timeout.
+func (s *Server) Serve(ctx context.Context, listener net.Listener) error {
+	serveErr := make(chan error, 1)
+	s.startedAt = time.Now()
+	go func() {
+		if tls := s.opts.Config.TLS; tls.Enabled() {
+			serveErr <- s.httpServer.ServeTLS(listener, tls.CertFile, tls.KeyFile)
+			return
+		}
+		serveErr <- s.httpServer.Serve(listener)
+	}()
+	s.ready.Store(true)
+
+	select {
+	case err := <-serveErr:
+		s.ready.Store(false)
+		return err
+	case <-ctx.Done():
+	}
+	return s.shutdown()
+}
+
+func (s *Server) shutdown() error {
+	s.ready.Store(false)
+	cfg := s.opts.Config
+
+	if cfg.DrainDelay > 0 {
+		slog.Info("not ready anymore, draining", "drain_delay", cfg.DrainDelay)
+		time.Sleep(cfg.DrainDelay)
+	}
+	slog.Info("shutting down, waiting for in-flight requests", "timeout", cfg.ShutdownTimeout)
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+	if err := s.httpServer.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("draining requests: %w", err))
+		_ = s.httpServer.Close()
+	}
+	if err := data.Close(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("flushing database writes: %w", err))
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+
+	slog.Info("server stopped")
+	return nil
+}
+
+// handleHealth answers 200 as long as the process serves requests
+func (s *Server) handleHealth(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, r, http.StatusOK, model.HealthResponse{
+		Status:        "ok",
+		UptimeSeconds: int64(time.Since(s.startedAt).Seconds()),
+	})
+}
+
+// handleReady answers 200 while the server accepts requests, the database
+// can be read and parsed and the directory of the application is there, and
+// 503 otherwise. It turns 503 as soon as the server starts shutting down, so
+// load balancers stop routing to it.
+func (s *Server) handleReady(w http.ResponseWriter, r *http.Request) {
+	response := model.ReadyResponse{Status: "ready", Checks: map[string]string{"server": "ok", "database": "ok", "files": "ok"}}
+	code := http.StatusOK
+	if !s.Ready() {
+		response.Checks["server"] = "shutting down"
+	}
+	if err := data.Ping(r.Context()); err != nil {
+		response.Checks["database"] = err.Error()
+	}
+	if info, err := os.Stat(s.serveDir); err != nil {
+		response.Checks["files"] = err.Error()
+	} else if !info.IsDir() {
+		response.Checks["files"] = s.serveDir + " is not a directory"
+	}
+	for _, check := range response.Checks {
+		if check != "ok" {
+			response.Status, code = "not ready", http.StatusServiceUnavailable
+		}
+	}
+	writeJSON(w, r, code, response)
+}
+
+func writeJSON(w http.ResponseWriter, r *http.Request, code int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		slog.ErrorContext(r.Context(), "failed to encode response", "error", err)
+	}
+}
@@ -0,0 +1,224 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/config"
+	"github.com/neovasili/metal-fests/internal/data"
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+func newTestServer(t *testing.T, cfg config.ServerConfig) *Server {
+	t.Helper()
+	t.Chdir(t.TempDir())
+	if err := os.WriteFile("db.json", []byte(`{"bands":[],"festivals":[]}`), 0600); err != nil {
+		t.Fatalf("failed to create test db: %v", err)
+	}
+	oldPath := data.SetDBFilePathForTesting("db.json")
+	t.Cleanup(func() { data.SetDBFilePathForTesting(oldPath) })
+	server, err := New(Options{Config: cfg})
+	if err != nil {
+		t.Fatalf("New() failed: %v", err)
+	}
+	t.Cleanup(data.ReopenForTesting)
+	return server
+}
+
+// testClient opens a connection per request, so no idle connection it dialed
+// ahead of time holds the shutdown back
+var testClient = &http.Client{Transport: &http.Transport{DisableKeepAlives: true}}
+
+func TestHandleHealth(t *testing.T) {
+	server := newTestServer(t, config.Default().Server)
+	server.startedAt = time.Now().Add(-time.Minute)
+
+	w := httptest.NewRecorder()
+	server.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/health", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /api/health = %d, want 200", w.Code)
+	}
+	var health model.HealthResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &health); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+	if health.Status != "ok" || health.UptimeSeconds < 60 {
+		t.Errorf("GET /api/health = %+v", health)
+	}
+}
+
+func TestHandleReady(t *testing.T) {
+	server := newTestServer(t, config.Default().Server)
+	if err := os.WriteFile("corrupt.json", []byte(`{"bands":[`), 0600); err != nil {
+		t.Fatalf("failed to create corrupt db: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		ready    bool
+		dbPath   string
+		serveDir string
+		code     int
+		check    string
+		want     string
+	}{
+		{name: "Serving", ready: true, dbPath: "db.json", serveDir: ".", code: http.StatusOK, check: "database", want: "ok"},
+		{name: "Shutting down", ready: false, dbPath: "db.json", serveDir: ".", code: http.StatusServiceUnavailable, check: "database", want: "ok"},
+		{name: "Missing database", ready: true, dbPath: "missing.json", serveDir: ".", code: http.StatusServiceUnavailable},
+		{name: "Corrupt database", ready: true, dbPath: "corrupt.json", serveDir: ".", code: http.StatusServiceUnavailable, check: "files", want: "ok"},
+		{name: "Missing serve directory", ready: true, dbPath: "db.json", serveDir: "build", code: http.StatusServiceUnavailable, check: "database", want: "ok"},
+		{name: "Serve directory is a file", ready: true, dbPath: "db.json", serveDir: "db.json", code: http.StatusServiceUnavailable, check: "files", want: "db.json is not a directory"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server.ready.Store(tt.ready)
+			server.serveDir = tt.serveDir
+			data.SetDBFilePath(tt.dbPath)
+			w := httptest.NewRecorder()
+			server.Handler().ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/ready", nil))
+			if w.Code != tt.code {
+				t.Errorf("GET /api/ready = %d, want %d", w.Code, tt.code)
+			}
+			var ready model.ReadyResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &ready); err != nil {
+				t.Fatalf("failed to decode response: %v", err)
+			}
+			if tt.check != "" && ready.Checks[tt.check] != tt.want {
+				t.Errorf("%s check = %q, want %q", tt.check, ready.Checks[tt.check], tt.want)
+			}
+		})
+	}
+}
+
+func TestMetrics(t *testing.T) {
+	server := newTestServer(t, config.Default().Server)
+	handler := server.Handler()
+
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/health", nil))
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /metrics = %d, want 200", w.Code)
+	}
+	expected := `metal_fests_http_requests_total{method="GET",route="/api/health",status="200"}`
+	if !strings.Contains(w.Body.String(), expected) {
+		t.Errorf("GET /metrics is missing %s", expected)
+	}
+}
+
+func TestServe_GracefulShutdown(t *testing.T) {
+	cfg := config.Default().Server
+	cfg.ShutdownTimeout = 5 * time.Second
+	server := newTestServer(t, cfg)
+
+	// A slow request is in flight when the shutdown starts
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := server.Handler()
+	server.httpServer.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			close(started)
+			<-release
+			_, _ = io.WriteString(w, "done")
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- server.Serve(ctx, listener) }()
+
+	url := "http://" + listener.Addr().String()
+	resp, err := testClient.Get(url + "/api/ready")
+	if err != nil {
+		t.Fatalf("GET /api/ready failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("GET /api/ready = %d, want 200 while serving", resp.StatusCode)
+	}
+
+	slow := make(chan string, 1)
+	go func() {
+		resp, err := testClient.Get(url + "/slow")
+		if err != nil {
+			slow <- err.Error()
+			return
+		}
+		defer func() { _ = resp.Body.Close() }()
+		body, _ := io.ReadAll(resp.Body)
+		slow <- string(body)
+	}()
+	<-started
+
+	cancel()
+	deadline := time.Now().Add(time.Second)
+	for server.Ready() && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+	if server.Ready() {
+		t.Error("the server should report not ready once shutting down")
+	}
+
+	close(release)
+	if body := <-slow; body != "done" {
+		t.Errorf("in-flight request = %q, want it to complete", body)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("Serve() = %v, want a clean shutdown", err)
+	}
+
+	// Database writes are rejected once the server has stopped
+	if err := data.UpdateBandInDatabase(context.Background(), model.Band{Key: "gojira"}); !errors.Is(err, data.ErrClosed) {
+		t.Errorf("UpdateBandInDatabase() after shutdown = %v, want ErrClosed", err)
+	}
+}
+
+func TestServe_ShutdownTimeout(t *testing.T) {
+	cfg := config.Default().Server
+	cfg.ShutdownTimeout = 50 * time.Millisecond
+	server := newTestServer(t, cfg)
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	defer close(release)
+	server.httpServer.Handler = http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		close(started)
+		<-release
+	})
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("failed to listen: %v", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() { served <- server.Serve(ctx, listener) }()
+
+	go func() {
+		if resp, err := testClient.Get("http://" + listener.Addr().String() + "/stuck"); err == nil {
+			_ = resp.Body.Close()
+		}
+	}()
+	<-started
+	cancel()
+
+	if err := <-served; !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Serve() = %v, want the shutdown timeout to expire", err)
+	}
+}
@@ -0,0 +1,197 @@
+package server
+
+import (
+	"log/slog"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/api"
+	"github.com/neovasili/metal-fests/internal/config"
+	"github.com/neovasili/metal-fests/internal/logging"
+	"github.com/neovasili/metal-fests/internal/metrics"
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+// Names of the rate limit budgets, as labelled in the metrics
+const (
+	budgetPublic      = "public"
+	budgetAdminWrites = "admin_writes"
+	budgetValidateURL = "validate_url"
+)
+
+// unlimitedPaths are left out of the rate limits, so that load balancers and
+// Prometheus keep reaching the server whatever the clients sharing their
+// address do
+var unlimitedPaths = []string{"/api/health", "/api/ready", "/metrics"}
+
+// bucketSweepInterval is how often the buckets of idle clients are dropped
+const bucketSweepInterval = time.Minute
+
+// bucket holds the requests a client may still send
+type bucket struct {
+	tokens  float64
+	updated time.Time
+}
+
+// limiter is a token bucket per client
+type limiter struct {
+	name string
+	// rate is the number of tokens given back per second
+	rate  float64
+	burst float64
+	now   func() time.Time
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+// newLimiter returns the limiter of a budget, or nil when the budget is
+// unlimited
+func newLimiter(name string, cfg config.RateLimit) *limiter {
+	if cfg.RequestsPerMinute <= 0 {
+		return nil
+	}
+	return &limiter{
+		name:    name,
+		rate:    float64(cfg.RequestsPerMinute) / time.Minute.Seconds(),
+		burst:   float64(cfg.Burst),
+		now:     time.Now,
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// allow takes a token from the bucket of client. When the bucket is empty, it
+// returns how long until the next token.
+func (l *limiter) allow(client string) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	if now.Sub(l.swept) >= bucketSweepInterval {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: l.burst, updated: now}
+		l.buckets[client] = b
+	}
+	b.tokens = l.refill(b, now)
+	b.updated = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	return false, time.Duration((1 - b.tokens) / l.rate * float64(time.Second))
+}
+
+// refill returns the tokens of b at now
+func (l *limiter) refill(b *bucket, now time.Time) float64 {
+	return math.Min(l.burst, b.tokens+now.Sub(b.updated).Seconds()*l.rate)
+}
+
+// sweep drops the buckets refilled to the full burst, which are the same as
+// new ones, so the map doesn't grow with every client ever seen
+func (l *limiter) sweep(now time.Time) {
+	for client, b := range l.buckets {
+		if l.refill(b, now) >= l.burst {
+			delete(l.buckets, client)
+		}
+	}
+	l.swept = now
+}
+
+// rateLimits holds the budgets of each group of routes
+type rateLimits struct {
+	public, adminWrites, validateURL *limiter
+	trustForwardedFor                bool
+}
+
+// limiterFor returns the limiter of the budget r is counted in, nil when r
+// is not limited
+func (l *rateLimits) limiterFor(r *http.Request) *limiter {
+	switch {
+	case r.URL.Path == "/api/validate-url":
+		return l.validateURL
+	case api.IsAdminPath(r.URL.Path) && r.Method != http.MethodGet && r.Method != http.MethodHead:
+		return l.adminWrites
+	default:
+		for _, path := range unlimitedPaths {
+			if r.URL.Path == path {
+				return nil
+			}
+		}
+		return l.public
+	}
+}
+
+// rateLimitMiddleware refuses the requests of clients that ran out of budget
+// with a 429 and a Retry-After header
+func rateLimitMiddleware(cfg config.RateLimitConfig, next http.Handler) http.Handler {
+	limits := &rateLimits{
+		public:            newLimiter(budgetPublic, cfg.Public),
+		adminWrites:       newLimiter(budgetAdminWrites, cfg.AdminWrites),
+		validateURL:       newLimiter(budgetValidateURL, cfg.ValidateURL),
+		trustForwardedFor: cfg.TrustForwardedFor,
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		l := limits.limiterFor(r)
+		if l == nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		client := clientAddress(r, limits.trustForwardedFor)
+		allowed, retryAfter := l.allow(client)
+		if allowed {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		metrics.RateLimited(l.name)
+		seconds := int(math.Ceil(retryAfter.Seconds()))
+		slog.WarnContext(r.Context(), "request rate limited", "budget", l.name, "client", client, "retry_after", seconds)
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		writeJSON(w, r, http.StatusTooManyRequests, model.ErrorResponse{
+			Code:      model.ErrorCodeRateLimited,
+			Message:   "Too many requests, retry in " + strconv.Itoa(seconds) + "s",
+			RequestID: logging.RequestID(r.Context()),
+		})
+	})
+}
+
+// clientAddress tells the clients apart: by the IP address of the
+// connection, or by the last address of X-Forwarded-For, the one the proxy
+// in front of the server saw, when trusted
+func clientAddress(r *http.Request, trustForwardedFor bool) string {
+	if trustForwardedFor {
+		if forwarded := r.Header.Values("X-Forwarded-For"); len(forwarded) > 0 {
+			addresses := strings.Split(forwarded[len(forwarded)-1], ",")
+			if ip := net.ParseIP(strings.TrimSpace(addresses[len(addresses)-1])); ip != nil {
+				return ip.String()
+			}
+		}
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
+
+// limitBody caps the body of every request at maxBytes. Reading more fails
+// with an *http.MaxBytesError, and the connection is closed once the
+// response is sent.
+func limitBody(maxBytes int, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Body != nil && r.Body != http.NoBody {
+			r.Body = http.MaxBytesReader(w, r.Body, int64(maxBytes))
+		}
+		next.ServeHTTP(w, r)
+	})
+}
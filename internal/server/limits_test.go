@@ -0,0 +1,149 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/config"
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+func TestLimiter(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	l := newLimiter(budgetPublic, config.RateLimit{RequestsPerMinute: 60, Burst: 2})
+	l.now = func() time.Time { return now }
+
+	for i := range 2 {
+		if ok, _ := l.allow("203.0.113.1"); !ok {
+			t.Fatalf("request %d within the burst was refused", i+1)
+		}
+	}
+	ok, retryAfter := l.allow("203.0.113.1")
+	if ok {
+		t.Fatal("request over the burst was allowed")
+	}
+	if retryAfter != time.Second {
+		t.Errorf("retry after = %s, want 1s", retryAfter)
+	}
+	if ok, _ := l.allow("203.0.113.2"); !ok {
+		t.Error("another client shares the budget")
+	}
+
+	now = now.Add(time.Second)
+	if ok, _ := l.allow("203.0.113.1"); !ok {
+		t.Error("request refused once a token came back")
+	}
+
+	now = now.Add(bucketSweepInterval)
+	l.allow("203.0.113.3")
+	if len(l.buckets) != 1 {
+		t.Errorf("%d buckets after the sweep, want only the new client's", len(l.buckets))
+	}
+}
+
+func TestNewLimiter_Unlimited(t *testing.T) {
+	if l := newLimiter(budgetPublic, config.RateLimit{}); l != nil {
+		t.Error("newLimiter() returned a limiter for a budget without rate")
+	}
+}
+
+func TestRateLimitMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+	handler := rateLimitMiddleware(config.RateLimitConfig{
+		Public:      config.RateLimit{RequestsPerMinute: 60, Burst: 1},
+		AdminWrites: config.RateLimit{RequestsPerMinute: 60, Burst: 1},
+		ValidateURL: config.RateLimit{RequestsPerMinute: 6, Burst: 1},
+	}, next)
+
+	serve := func(method, path string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, nil)
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, r)
+		return w
+	}
+
+	// Each budget allows a single request at once
+	tests := []struct {
+		method, path string
+		want         int
+	}{
+		{http.MethodGet, "/db.json", http.StatusOK},
+		{http.MethodPut, "/api/bands/gojira", http.StatusOK},
+		{http.MethodPost, "/api/validate-url", http.StatusOK},
+		{http.MethodGet, "/index.html", http.StatusTooManyRequests},
+		{http.MethodGet, "/api/admin/stats", http.StatusTooManyRequests},
+		{http.MethodPut, "/api/festivals/hellfest", http.StatusTooManyRequests},
+		{http.MethodGet, "/api/health", http.StatusOK},
+		{http.MethodGet, "/api/ready", http.StatusOK},
+		{http.MethodGet, "/metrics", http.StatusOK},
+	}
+	for _, tt := range tests {
+		if w := serve(tt.method, tt.path); w.Code != tt.want {
+			t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, w.Code, tt.want)
+		}
+	}
+
+	w := serve(http.MethodPost, "/api/validate-url")
+	if w.Code != http.StatusTooManyRequests {
+		t.Fatalf("status = %d, want %d", w.Code, http.StatusTooManyRequests)
+	}
+	if got := w.Header().Get("Retry-After"); got != "10" {
+		t.Errorf("Retry-After = %q, want 10", got)
+	}
+	var response model.ErrorResponse
+	if err := json.NewDecoder(w.Body).Decode(&response); err != nil {
+		t.Fatalf("failed to decode the response: %v", err)
+	}
+	if response.Code != model.ErrorCodeRateLimited {
+		t.Errorf("code = %q, want %q", response.Code, model.ErrorCodeRateLimited)
+	}
+}
+
+func TestClientAddress(t *testing.T) {
+	tests := []struct {
+		name      string
+		forwarded []string
+		trust     bool
+		expected  string
+	}{
+		{name: "Connection address", expected: "192.0.2.1"},
+		{name: "Untrusted proxy", forwarded: []string{"203.0.113.7"}, expected: "192.0.2.1"},
+		{name: "Trusted proxy", forwarded: []string{"198.51.100.9, 203.0.113.7"}, trust: true, expected: "203.0.113.7"},
+		{name: "Several headers", forwarded: []string{"198.51.100.9", "2001:db8::1"}, trust: true, expected: "2001:db8::1"},
+		{name: "Invalid forwarded address", forwarded: []string{"unknown"}, trust: true, expected: "192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/", nil)
+			r.RemoteAddr = "192.0.2.1:54321"
+			for _, value := range tt.forwarded {
+				r.Header.Add("X-Forwarded-For", value)
+			}
+			if got := clientAddress(r, tt.trust); got != tt.expected {
+				t.Errorf("clientAddress() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestLimitBody(t *testing.T) {
+	handler := limitBody(8, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, err := io.ReadAll(r.Body); err != nil {
+			w.WriteHeader(http.StatusRequestEntityTooLarge)
+		}
+	}))
+
+	for body, want := range map[string]int{"12345678": http.StatusOK, "123456789": http.StatusRequestEntityTooLarge} {
+		w := httptest.NewRecorder()
+		handler.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/validate-url", strings.NewReader(body)))
+		if w.Code != want {
+			t.Errorf("body of %d bytes: status = %d, want %d", len(body), w.Code, want)
+		}
+	}
+}
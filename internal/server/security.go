@@ -0,0 +1,65 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/neovasili/metal-fests/internal/config"
+)
+
+// defaultContentSecurityPolicy lets the pages load Leaflet from unpkg, the map
+// tiles, and the posters and logos hosted on the festival and band sites. The
+// templates use inline event handlers, inline scripts stay forbidden.
+const defaultContentSecurityPolicy = "default-src 'self'; " +
+	"script-src 'self' https://unpkg.com; " +
+	"script-src-attr 'unsafe-inline'; " +
+	"style-src 'self' https://unpkg.com 'unsafe-inline'; " +
+	"img-src 'self' data: https:; " +
+	"connect-src 'self'; " +
+	"font-src 'self' data:; " +
+	"object-src 'none'; " +
+	"base-uri 'self'; " +
+	"form-action 'self'"
+
+// securityHeaders sets the security headers of every response. HSTS is only
+// sent on HTTPS requests, as browsers ignore it over plain HTTP.
+func securityHeaders(cfg config.SecurityConfig, next http.Handler) http.Handler {
+	csp := cfg.ContentSecurityPolicy
+	if csp == "" {
+		csp = defaultContentSecurityPolicy
+	}
+	if !strings.Contains(csp, "frame-ancestors") {
+		ancestors := "'none'"
+		if len(cfg.FrameAncestors) > 0 {
+			ancestors = strings.Join(cfg.FrameAncestors, " ")
+		}
+		csp = strings.TrimSuffix(strings.TrimSpace(csp), ";") + "; frame-ancestors " + ancestors
+	}
+	var hsts string
+	if cfg.HSTSMaxAge > 0 {
+		hsts = fmt.Sprintf("max-age=%d; includeSubDomains", int(cfg.HSTSMaxAge.Seconds()))
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := w.Header()
+		header.Set("Content-Security-Policy", csp)
+		header.Set("X-Content-Type-Options", "nosniff")
+		if cfg.ReferrerPolicy != "" {
+			header.Set("Referrer-Policy", cfg.ReferrerPolicy)
+		}
+		if hsts != "" && isHTTPS(r, cfg.TrustForwardedProto) {
+			header.Set("Strict-Transport-Security", hsts)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// isHTTPS tells whether r reached the server over TLS, or the proxy in front
+// of it when its X-Forwarded-Proto header is trusted
+func isHTTPS(r *http.Request, trustForwardedProto bool) bool {
+	if r.TLS != nil {
+		return true
+	}
+	return trustForwardedProto && strings.EqualFold(r.Header.Get("X-Forwarded-Proto"), "https")
+}
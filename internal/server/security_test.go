@@ -0,0 +1,110 @@
+package server
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/config"
+)
+
+func TestSecurityHeaders(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	tests := []struct {
+		name          string
+		modify        func(cfg *config.SecurityConfig)
+		tls           bool
+		forwarded     string
+		wantCSP       []string
+		wantHSTS      string
+		wantReferrer  string
+		unexpectedCSP []string
+	}{
+		{
+			name:         "Defaults over HTTP",
+			modify:       func(*config.SecurityConfig) {},
+			wantCSP:      []string{"default-src 'self'", "https://unpkg.com", "img-src 'self' data: https:", "frame-ancestors 'none'"},
+			wantReferrer: "strict-origin-when-cross-origin",
+		},
+		{
+			name:         "Defaults over TLS",
+			modify:       func(*config.SecurityConfig) {},
+			tls:          true,
+			wantCSP:      []string{"frame-ancestors 'none'"},
+			wantHSTS:     "max-age=31536000; includeSubDomains",
+			wantReferrer: "strict-origin-when-cross-origin",
+		},
+		{
+			name:         "Untrusted proxy",
+			modify:       func(*config.SecurityConfig) {},
+			forwarded:    "https",
+			wantReferrer: "strict-origin-when-cross-origin",
+		},
+		{
+			name: "Trusted proxy and embedding site",
+			modify: func(cfg *config.SecurityConfig) {
+				cfg.TrustForwardedProto = true
+				cfg.HSTSMaxAge = time.Hour
+				cfg.FrameAncestors = []string{"'self'", "https://metal-fests.com"}
+			},
+			forwarded:     "https",
+			wantCSP:       []string{"frame-ancestors 'self' https://metal-fests.com"},
+			unexpectedCSP: []string{"frame-ancestors 'none'"},
+			wantHSTS:      "max-age=3600; includeSubDomains",
+			wantReferrer:  "strict-origin-when-cross-origin",
+		},
+		{
+			name: "Custom policy",
+			modify: func(cfg *config.SecurityConfig) {
+				cfg.ContentSecurityPolicy = "default-src 'none'; frame-ancestors 'self'"
+				cfg.ReferrerPolicy = "no-referrer"
+				cfg.HSTSMaxAge = 0
+			},
+			tls:           true,
+			wantCSP:       []string{"default-src 'none'; frame-ancestors 'self'"},
+			unexpectedCSP: []string{"unpkg", "frame-ancestors 'none'"},
+			wantReferrer:  "no-referrer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default().Server.Security
+			tt.modify(&cfg)
+			r := httptest.NewRequest(http.MethodGet, "/index.html", nil)
+			if tt.tls {
+				r.TLS = &tls.ConnectionState{}
+			}
+			if tt.forwarded != "" {
+				r.Header.Set("X-Forwarded-Proto", tt.forwarded)
+			}
+			w := httptest.NewRecorder()
+			securityHeaders(cfg, next).ServeHTTP(w, r)
+
+			csp := w.Header().Get("Content-Security-Policy")
+			for _, want := range tt.wantCSP {
+				if !strings.Contains(csp, want) {
+					t.Errorf("Content-Security-Policy = %q, want it to contain %q", csp, want)
+				}
+			}
+			for _, unexpected := range tt.unexpectedCSP {
+				if strings.Contains(csp, unexpected) {
+					t.Errorf("Content-Security-Policy = %q, want no %q", csp, unexpected)
+				}
+			}
+			if got := w.Header().Get("Strict-Transport-Security"); got != tt.wantHSTS {
+				t.Errorf("Strict-Transport-Security = %q, want %q", got, tt.wantHSTS)
+			}
+			if got := w.Header().Get("Referrer-Policy"); got != tt.wantReferrer {
+				t.Errorf("Referrer-Policy = %q, want %q", got, tt.wantReferrer)
+			}
+			if got := w.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+				t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+			}
+		})
+	}
+}
@@ -0,0 +1,279 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/api"
+	"github.com/neovasili/metal-fests/internal/auth"
+	"github.com/neovasili/metal-fests/internal/config"
+	"github.com/neovasili/metal-fests/internal/logging"
+	"github.com/neovasili/metal-fests/internal/metrics"
+	"github.com/neovasili/metal-fests/internal/tracing"
+)
+
+// responseWriter wraps http.ResponseWriter to capture the status code
+type responseWriter struct {
+	http.ResponseWriter
+	statusCode int
+}
+
+// WriteHeader intercepts the status code
+func (rw *responseWriter) WriteHeader(code int) {
+	rw.statusCode = code
+	rw.ResponseWriter.WriteHeader(code)
+}
+
+// CustomFileServer wraps http.FileServer to handle clean URLs
+type CustomFileServer struct {
+	fs      http.Handler
+	baseDir string
+	devMode bool
+}
+
+func (cfs *CustomFileServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// In development mode, disable caching
+	if cfs.devMode {
+		w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+		w.Header().Set("Pragma", "no-cache")
+		w.Header().Set("Expires", "0")
+	}
+
+	// Check if the file exists
+	path := r.URL.Path
+	fullPath := filepath.Join(cfs.baseDir, path)
+
+	// If path starts with /admin, serve admin/index.html
+	if strings.HasPrefix(path, "/admin") {
+		if _, err := os.Stat(fullPath); os.IsNotExist(err) {
+			r.URL.Path = "/admin/index.html"
+		}
+	} else if _, err := os.Stat(fullPath); os.IsNotExist(err) && !strings.HasPrefix(path, "/api/") {
+		// If path doesn't exist and it's not an API request, serve error.html
+		r.URL.Path = "/error.html"
+	}
+
+	cfs.fs.ServeHTTP(w, r)
+}
+
+// corsPolicy answers the cross-origin requests of a group of routes
+type corsPolicy struct {
+	anyOrigin bool
+	origins   []string
+	methods   string
+	headers   string
+	maxAge    string
+}
+
+func newCORSPolicy(cfg config.CORSPolicy) corsPolicy {
+	return corsPolicy{
+		anyOrigin: slices.Contains(cfg.AllowedOrigins, "*"),
+		origins:   cfg.AllowedOrigins,
+		methods:   strings.Join(append(slices.Clone(cfg.AllowedMethods), http.MethodOptions), ", "),
+		headers:   strings.Join(cfg.AllowedHeaders, ", "),
+		maxAge:    strconv.Itoa(int(cfg.MaxAge.Seconds())),
+	}
+}
+
+// CORS middleware, applying the admin policy to the admin API and the public
+// one to everything else. Preflight requests are answered here.
+func corsMiddleware(cfg config.CORSConfig, next http.Handler) http.Handler {
+	public, admin := newCORSPolicy(cfg.Public), newCORSPolicy(cfg.Admin)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		policy := public
+		if api.IsAdminPath(r.URL.Path) {
+			policy = admin
+		}
+
+		origin := r.Header.Get("Origin")
+		allowed := origin != "" && (policy.anyOrigin || slices.Contains(policy.origins, origin))
+		if !policy.anyOrigin {
+			w.Header().Add("Vary", "Origin")
+		}
+		if allowed {
+			if policy.anyOrigin {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			w.Header().Set("Access-Control-Expose-Headers", logging.RequestIDHeader)
+		}
+
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			if allowed {
+				w.Header().Set("Access-Control-Allow-Methods", policy.methods)
+				w.Header().Set("Access-Control-Allow-Headers", policy.headers)
+				w.Header().Set("Access-Control-Max-Age", policy.maxAge)
+			}
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// loggingMiddleware logs every request once served, server errors at the
+// error level
+func loggingMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		// wrap the ResponseWriter
+		rw := &responseWriter{ResponseWriter: w, statusCode: http.StatusOK}
+		next.ServeHTTP(rw, r)
+
+		level := slog.LevelInfo
+		if rw.statusCode >= http.StatusInternalServerError {
+			level = slog.LevelError
+		}
+		slog.LogAttrs(r.Context(), level, "request served",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.String("route", r.Pattern),
+			slog.Int("status", rw.statusCode),
+			slog.Duration("duration", time.Since(start)),
+		)
+	})
+}
+
+// Options configures the HTTP server
+type Options struct {
+	Config config.ServerConfig
+	// Build serves the minified files of the build folder instead of the sources
+	Build bool
+	// Out receives the startup banner
+	Out io.Writer
+	// Authenticator checks the callers of the admin API, which is open when nil
+	Authenticator auth.Authenticator
+}
+
+// New builds the server for the application and its API
+func New(opts Options) (*Server, error) {
+	// Determine which directory to serve
+	serveDir := "."
+	if opts.Build {
+		serveDir = "build"
+	}
+
+	// Verify the serve directory exists
+	if _, err := os.Stat(serveDir); os.IsNotExist(err) {
+		return nil, fmt.Errorf("%s directory does not exist, run 'pnpm build' first", serveDir)
+	}
+
+	// Create file server with the appropriate directory
+	fileServer := &CustomFileServer{
+		fs:      http.FileServer(http.Dir(serveDir)),
+		baseDir: serveDir,
+		devMode: !opts.Build, // Disable caching in dev mode
+	}
+
+	server := &Server{opts: opts, out: opts.Out, serveDir: serveDir}
+	if server.out == nil {
+		server.out = io.Discard
+	}
+
+	// Setup routes
+	mux := http.NewServeMux()
+
+	// API routes
+	mux.HandleFunc("/api/health", server.handleHealth)
+	mux.HandleFunc("/api/ready", server.handleReady)
+	api.Register(mux, opts.Authenticator)
+
+	// Prometheus metrics
+	mux.Handle("GET /metrics", metrics.Handler())
+
+	// Static file serving
+	mux.Handle("/", fileServer)
+
+	// Apply middleware. The tracing, logging and metrics middlewares and the
+	// ones under them pass the request on to the mux as it is, to read the
+	// route pattern the mux matched.
+	handler := logging.Middleware(securityHeaders(opts.Config.Security, corsMiddleware(opts.Config.CORS,
+		tracing.Middleware(loggingMiddleware(metrics.Middleware(mux,
+			rateLimitMiddleware(opts.Config.RateLimit, limitBody(opts.Config.MaxBodyBytes, mux))))))))
+
+	server.httpServer = &http.Server{
+		Addr:           fmt.Sprintf(":%d", opts.Config.Port),
+		Handler:        handler,
+		ReadTimeout:    opts.Config.ReadTimeout,
+		WriteTimeout:   opts.Config.WriteTimeout,
+		IdleTimeout:    opts.Config.IdleTimeout,
+		MaxHeaderBytes: opts.Config.MaxHeaderBytes,
+	}
+	return server, nil
+}
+
+// Run serves the application until ctx is done or the process receives
+// SIGINT or SIGTERM, then shuts the server down gracefully. A second signal
+// stops the process at once.
+func Run(ctx context.Context, opts Options) error {
+	server, err := New(opts)
+	if err != nil {
+		return err
+	}
+	out := server.out
+
+	// Get current directory
+	dir, err := os.Getwd()
+	if err != nil {
+		return err
+	}
+
+	listener, err := net.Listen("tcp", server.httpServer.Addr)
+	if err != nil {
+		return err
+	}
+
+	scheme := "http"
+	if opts.Config.TLS.Enabled() {
+		scheme = "https"
+	}
+	serveDir := "."
+	mode := "Development"
+	if opts.Build {
+		serveDir = "build"
+		mode = "Production"
+	}
+
+	// Print startup information
+	_, _ = fmt.Fprintf(out, "🤘 Metal Festivals Timeline Server (%s Mode) 🤘\n", mode)
+	_, _ = fmt.Fprintf(out, "📡 Serving at: %s://localhost:%d\n", scheme, opts.Config.Port)
+	_, _ = fmt.Fprintf(out, "📁 Base directory: %s\n", dir)
+	_, _ = fmt.Fprintf(out, "📂 Serving from: %s/\n", serveDir)
+	_, _ = fmt.Fprintln(out, "🌐 Access the application:")
+	_, _ = fmt.Fprintf(out, "   Timeline: %s://localhost:%d/index.html\n", scheme, opts.Config.Port)
+	_, _ = fmt.Fprintf(out, "   Map:      %s://localhost:%d/map.html\n", scheme, opts.Config.Port)
+	_, _ = fmt.Fprintf(out, "   Admin:    %s://localhost:%d/admin/\n", scheme, opts.Config.Port)
+	if opts.Build {
+		_, _ = fmt.Fprintln(out, "   ⚡ Serving minified production files")
+	} else {
+		_, _ = fmt.Fprintln(out, "   🔧 Serving source files (dev mode)")
+	}
+	_, _ = fmt.Fprintln(out, "   (Clean URLs handled by client-side router)")
+	_, _ = fmt.Fprintln(out, "⏹️  Press Ctrl+C to stop the server")
+	_, _ = fmt.Fprintln(out)
+	slog.Info("server listening", "addr", listener.Addr().String(), "mode", strings.ToLower(mode), "dir", filepath.Join(dir, serveDir))
+	if opts.Authenticator == nil {
+		slog.Warn("the admin API is open to anyone reaching the server, configure auth.tokens or auth.oidc to protect it")
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	// Once shutting down, the default handling of the signals comes back
+	context.AfterFunc(ctx, stop)
+
+	return server.Serve(ctx, listener)
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/neovasili/metal-fests/internal/config"
+)
+
+func TestCustomFileServer(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"index.html":       "timeline",
+		"error.html":       "not found",
+		"admin/index.html": "admin",
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0750); err != nil {
+			t.Fatalf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+	}
+	fileServer := &CustomFileServer{fs: http.FileServer(http.Dir(dir)), baseDir: dir, devMode: true}
+
+	tests := []struct {
+		name     string
+		path     string
+		expected string
+	}{
+		{name: "Existing file", path: "/", expected: "timeline"},
+		{name: "Admin index", path: "/admin/", expected: "admin"},
+		{name: "Missing page", path: "/nope", expected: "not found"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			fileServer.ServeHTTP(w, httptest.NewRequest(http.MethodGet, tt.path, nil))
+			if body := w.Body.String(); body != tt.expected {
+				t.Errorf("GET %s = %q, want %q", tt.path, body, tt.expected)
+			}
+			if w.Header().Get("Cache-Control") == "" {
+				t.Error("expected caching to be disabled in dev mode")
+			}
+		})
+	}
+
+	// Admin routes that are not files are handed to the admin application
+	w := httptest.NewRecorder()
+	fileServer.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/admin/bands/metallica", nil))
+	if location := w.Header().Get("Location"); w.Code != http.StatusMovedPermanently || location != "./" {
+		t.Errorf("GET /admin/bands/metallica = %d %q, want a redirect to the admin index", w.Code, location)
+	}
+}
+
+func TestNew_MissingBuildFolder(t *testing.T) {
+	t.Chdir(t.TempDir())
+	if _, err := New(Options{Config: config.Default().Server, Build: true}); err == nil {
+		t.Error("New() should fail when the build folder does not exist")
+	}
+}
+
+func TestCorsMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) { w.WriteHeader(http.StatusNoContent) })
+	policies := func(public, admin []string) config.CORSConfig {
+		cfg := config.Default().Server.CORS
+		cfg.Public.AllowedOrigins = public
+		cfg.Admin.AllowedOrigins = admin
+		return cfg
+	}
+
+	tests := []struct {
+		name     string
+		cors     config.CORSConfig
+		path     string
+		origin   string
+		expected string
+	}{
+		{name: "Any origin", cors: policies([]string{"*"}, nil), path: "/db.json", origin: "https://example.com", expected: "*"},
+		{name: "Allowed origin", cors: policies([]string{"https://metal-fests.com"}, nil), path: "/db.json", origin: "https://metal-fests.com", expected: "https://metal-fests.com"},
+		{name: "Other origin", cors: policies([]string{"https://metal-fests.com"}, nil), path: "/db.json", origin: "https://example.com", expected: ""},
+		{name: "No origin", cors: policies([]string{"https://metal-fests.com"}, nil), path: "/db.json", origin: "", expected: ""},
+		{name: "Admin API closed to the public origins", cors: policies([]string{"*"}, nil), path: "/api/bands/gojira", origin: "https://example.com", expected: ""},
+		{name: "Admin API open to the admin origins", cors: policies([]string{"*"}, []string{"https://admin.metal-fests.com"}), path: "/api/bands/gojira", origin: "https://admin.metal-fests.com", expected: "https://admin.metal-fests.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			w := httptest.NewRecorder()
+			corsMiddleware(tt.cors, next).ServeHTTP(w, r)
+			if got := w.Header().Get("Access-Control-Allow-Origin"); got != tt.expected {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCorsMiddleware_Preflight(t *testing.T) {
+	called := false
+	next := http.HandlerFunc(func(http.ResponseWriter, *http.Request) { called = true })
+	cors := config.Default().Server.CORS
+	cors.Admin.AllowedOrigins = []string{"https://admin.metal-fests.com"}
+
+	tests := []struct {
+		name        string
+		path        string
+		origin      string
+		wantMethods string
+		wantHeaders string
+	}{
+		{name: "Public route", path: "/db.json", origin: "https://example.com", wantMethods: "GET, HEAD, OPTIONS", wantHeaders: "Content-Type, X-Request-ID"},
+		{name: "Admin route", path: "/api/festivals/hellfest", origin: "https://admin.metal-fests.com", wantMethods: "GET, POST, PUT, OPTIONS", wantHeaders: "Authorization, Content-Type, X-Request-ID"},
+		{name: "Admin route from another origin", path: "/api/festivals/hellfest", origin: "https://example.com"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodOptions, tt.path, nil)
+			r.Header.Set("Origin", tt.origin)
+			r.Header.Set("Access-Control-Request-Method", http.MethodPut)
+			w := httptest.NewRecorder()
+			corsMiddleware(cors, next).ServeHTTP(w, r)
+
+			if w.Code != http.StatusNoContent {
+				t.Errorf("status = %d, want %d", w.Code, http.StatusNoContent)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Methods"); got != tt.wantMethods {
+				t.Errorf("Access-Control-Allow-Methods = %q, want %q", got, tt.wantMethods)
+			}
+			if got := w.Header().Get("Access-Control-Allow-Headers"); got != tt.wantHeaders {
+				t.Errorf("Access-Control-Allow-Headers = %q, want %q", got, tt.wantHeaders)
+			}
+		})
+	}
+	if called {
+		t.Error("preflight requests should not reach the handler")
+	}
+}
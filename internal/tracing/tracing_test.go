@@ -0,0 +1,164 @@
+package tracing
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans routes the spans of the test to the returned recorder
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+	return recorder
+}
+
+func attributeValue(attrs []attribute.KeyValue, key attribute.Key) string {
+	for _, attr := range attrs {
+		if attr.Key == key {
+			return attr.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("PUT /api/bands/{key}", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("/api/", func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	handler := Middleware(mux)
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	tests := []struct {
+		name        string
+		method      string
+		path        string
+		traceparent string
+		spanName    string
+		route       string
+		status      codes.Code
+	}{
+		{name: "Continues the trace of the caller", method: http.MethodPut, path: "/api/bands/gojira", traceparent: "00-" + traceID + "-00f067aa0ba902b7-01", spanName: "PUT /api/bands/{key}", route: "/api/bands/{key}", status: codes.Unset},
+		{name: "Server errors", method: http.MethodGet, path: "/api/missing", spanName: "GET /api/", route: "/api/", status: codes.Error},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recorder := recordSpans(t)
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			if tt.traceparent != "" {
+				req.Header.Set("traceparent", tt.traceparent)
+			}
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			spans := recorder.Ended()
+			if len(spans) != 1 {
+				t.Fatalf("Middleware() recorded %d spans, want 1", len(spans))
+			}
+			span := spans[0]
+			if span.Name() != tt.spanName {
+				t.Errorf("span name = %q, want %q", span.Name(), tt.spanName)
+			}
+			if route := attributeValue(span.Attributes(), "http.route"); route != tt.route {
+				t.Errorf("http.route = %q, want %q", route, tt.route)
+			}
+			if span.Status().Code != tt.status {
+				t.Errorf("span status = %v, want %v", span.Status().Code, tt.status)
+			}
+			if tt.traceparent != "" && span.SpanContext().TraceID().String() != traceID {
+				t.Errorf("trace ID = %s, want the caller's %s", span.SpanContext().TraceID(), traceID)
+			}
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	recorder := recordSpans(t)
+	var traceparent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Transport(nil)}
+	ctx, parent := Tracer().Start(context.Background(), "parent")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL+"/poster.jpg?token=secret", nil)
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	_ = resp.Body.Close()
+	parent.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("recorded %d spans, want 2", len(spans))
+	}
+	span := spans[0]
+	if span.Parent().SpanID() != parent.SpanContext().SpanID() {
+		t.Errorf("client span is not a child of the caller's span")
+	}
+	if !strings.Contains(traceparent, span.SpanContext().TraceID().String()) {
+		t.Errorf("traceparent = %q, want the trace ID %s", traceparent, span.SpanContext().TraceID())
+	}
+	if url := attributeValue(span.Attributes(), "url.full"); strings.Contains(url, "secret") {
+		t.Errorf("url.full = %q, want the query string left out", url)
+	}
+	if span.Status().Code != codes.Error {
+		t.Errorf("span status = %v, want an error for a 404", span.Status().Code)
+	}
+}
+
+func TestSetup(t *testing.T) {
+	previousProvider, previousPropagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	t.Cleanup(func() {
+		otel.SetTracerProvider(previousProvider)
+		otel.SetTextMapPropagator(previousPropagator)
+	})
+
+	if _, err := Setup(context.Background(), Options{Exporter: "zipkin"}); err == nil {
+		t.Error("Setup() with an unknown exporter should fail")
+	}
+
+	var out bytes.Buffer
+	shutdown, err := Setup(context.Background(), Options{Exporter: ExporterStdout, ServiceName: "metal-fests-test", Out: &out})
+	if err != nil {
+		t.Fatalf("Setup() failed: %v", err)
+	}
+	_, span := Tracer().Start(context.Background(), "test span")
+	span.End()
+	if err := shutdown(context.Background()); err != nil {
+		t.Fatalf("shutdown failed: %v", err)
+	}
+	for _, expected := range []string{`"Name": "test span"`, "metal-fests-test"} {
+		if !strings.Contains(out.String(), expected) {
+			t.Errorf("exported spans are missing %q:\n%s", expected, out.String())
+		}
+	}
+}
@@ -0,0 +1,914 @@
+package bands
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/neovasili/metal-fests/internal/data"
+	"github.com/neovasili/metal-fests/internal/logging"
+	"github.com/neovasili/metal-fests/internal/model"
+	"github.com/neovasili/metal-fests/internal/openai"
+	"github.com/neovasili/metal-fests/internal/updater"
+	"github.com/neovasili/metal-fests/internal/urlcheck"
+)
+
+// BandSearchResult is the structured output requested from the model.
+// Its JSON schema is generated from this type, see bandSearchSchema.
+type BandSearchResult struct {
+	Error         string         `json:"error,omitempty" description:"Empty string when the band is found, otherwise 'Band not found'"`
+	Key           string         `json:"key,omitempty" description:"Lowercase key with hyphens instead of spaces"`
+	Name          string         `json:"name,omitempty" description:"Band name in title case"`
+	Country       string         `json:"country,omitempty" description:"Country the band comes from"`
+	Description   string         `json:"description,omitempty" description:"200-300 words about the band history, style and albums"`
+	HeadlineImage string         `json:"headlineImage,omitempty" description:"URL of a high-quality band photo"`
+	Logo          string         `json:"logo,omitempty" description:"URL of a high-quality band logo"`
+	Website       string         `json:"website,omitempty" description:"Official band website URL"`
+	Spotify       string         `json:"spotify,omitempty" description:"Spotify artist page URL"`
+	Genres        []string       `json:"genres,omitempty" jsonschema:"minItems=2,maxItems=4" description:"Main music genres"`
+	Members       []model.Member `json:"members,omitempty" description:"Current lineup"`
+
+	// PromptVersion identifies the prompt that produced this result
+	PromptVersion string `json:"-"`
+	// Model and Sources describe where the answer came from
+	Model   string           `json:"-"`
+	Sources []model.Citation `json:"-"`
+}
+
+// provenance describes the origin of the fields filled from this result
+func (r *BandSearchResult) provenance() model.Provenance {
+	return model.Provenance{
+		Origin:        model.ProvenanceAI,
+		Sources:       r.Sources,
+		Model:         r.Model,
+		PromptVersion: r.PromptVersion,
+		UpdatedAt:     time.Now().UTC(),
+	}
+}
+
+// bandSearchSchema is the JSON schema sent along with every band search
+var bandSearchSchema = openai.GenerateSchema(BandSearchResult{})
+
+type UpdateStats struct {
+	TotalBands       int
+	AddedBands       int
+	UpdatedBands     int
+	SkippedBands     int
+	NotFoundBands    int
+	FailedBands      int
+	ReviewBands      int
+	RefreshedBands   int
+	CheckedBands     int
+	ResumedBands     int
+	TotalTokens      int
+	TotalCost        float64
+	UsedModel        string
+	PromptTokens     int
+	CompletionTokens int
+	PromptVersion    string
+	ProcessedBands   int
+	Interrupted      bool
+	// Items holds the outcome of every band, in processing order
+	Items []updater.ItemReport
+}
+
+// FieldChange is a value overwritten by the refresh mode
+type FieldChange struct {
+	Band  string
+	Field string
+	Old   string
+	New   string
+}
+
+// bandOutcome describes what processing a single band resulted in
+type bandOutcome int
+
+const (
+	bandFailed bandOutcome = iota
+	bandSkipped
+	bandNotFound
+	bandUpdated
+	bandAdded
+	bandNeedsReview
+	bandChecked
+)
+
+// String names the outcome in the run report
+func (o bandOutcome) String() string {
+	switch o {
+	case bandSkipped:
+		return "skipped"
+	case bandNotFound:
+		return "notFound"
+	case bandUpdated:
+		return "updated"
+	case bandAdded:
+		return "added"
+	case bandNeedsReview:
+		return "review"
+	case bandChecked:
+		return "checked"
+	default:
+		return "failed"
+	}
+}
+
+// bandResult is produced by a worker and applied to the database by the collector
+type bandResult struct {
+	ref           model.BandRef
+	outcome       bandOutcome
+	reason        string
+	promptVersion string
+	band          model.Band
+	verification  bandVerification
+	changes       []FieldChange
+	tokens        int
+	cost          float64
+	usedModel     string
+	duration      time.Duration
+	// span covers the band until its result is stored
+	span trace.Span
+}
+
+var openaiClient *openai.OpenAIClient
+
+// Weights of each check in the confidence score
+const (
+	nameMatchWeight = 0.4
+	urlsWeight      = 0.4
+	genresWeight    = 0.2
+	// Names less similar than this are reported as a mismatch
+	minNameSimilarity = 0.8
+)
+
+// bandVerifier checks AI results before they are written to the database
+type bandVerifier struct {
+	checker       *urlcheck.Checker
+	minConfidence float64
+}
+
+// bandVerification is the confidence in a result and the reasons it was lowered
+type bandVerification struct {
+	confidence float64
+	reasons    []string
+}
+
+// verify scores a result by how closely its name matches the requested one,
+// how many of its URLs resolve and how many of its genres fit the taxonomy
+func (v *bandVerifier) verify(ctx context.Context, requestedName string, result *BandSearchResult) bandVerification {
+	var verification bandVerification
+	score := 0.0
+	weights := 0.0
+
+	similarity := data.NameSimilarity(requestedName, result.Name)
+	if similarity < minNameSimilarity {
+		verification.reasons = append(verification.reasons, fmt.Sprintf("name %q does not match %q", result.Name, requestedName))
+	}
+	score += nameMatchWeight * similarity
+	weights += nameMatchWeight
+
+	urls := []struct{ field, url string }{
+		{"website", result.Website},
+		{"logo", result.Logo},
+		{"headlineImage", result.HeadlineImage},
+		{"spotify", result.Spotify},
+	}
+	checked, resolved := 0, 0
+	for _, u := range urls {
+		if u.url == "" {
+			continue
+		}
+		checked++
+		check := v.checker.Check(ctx, u.url)
+		if check.Valid {
+			resolved++
+			continue
+		}
+		if check.Status != 0 {
+			verification.reasons = append(verification.reasons, fmt.Sprintf("%s does not resolve (status %d)", u.field, check.Status))
+		} else {
+			verification.reasons = append(verification.reasons, fmt.Sprintf("%s does not resolve (%s)", u.field, check.Error))
+		}
+	}
+	// Results without URLs are judged on the other checks only
+	if checked > 0 {
+		score += urlsWeight * float64(resolved) / float64(checked)
+		weights += urlsWeight
+	}
+
+	known := 0
+	for _, genre := range result.Genres {
+		if data.IsKnownGenre(genre) {
+			known++
+		} else {
+			verification.reasons = append(verification.reasons, fmt.Sprintf("unknown genre %q", genre))
+		}
+	}
+	if len(result.Genres) > 0 {
+		score += genresWeight * float64(known) / float64(len(result.Genres))
+		weights += genresWeight
+	}
+
+	verification.confidence = score / weights
+	return verification
+}
+
+func generateBandKey(bandName string) string {
+	// Convert to lowercase
+	key := strings.ToLower(bandName)
+
+	// Remove special characters
+	reg := regexp.MustCompile(`[^a-z0-9\s-]`)
+	key = reg.ReplaceAllString(key, "")
+
+	// Replace spaces with hyphens
+	key = strings.ReplaceAll(key, " ", "-")
+
+	// Remove consecutive hyphens
+	reg = regexp.MustCompile(`-+`)
+	key = reg.ReplaceAllString(key, "-")
+
+	// Trim hyphens from start and end
+	key = strings.Trim(key, "-")
+
+	return key
+}
+
+// defaultOverwriteFields are the fields the refresh mode corrects by default.
+// They go stale the most; descriptions, countries and genres are only filled in.
+const defaultOverwriteFields = "website,logo,headlineImage,spotify,members"
+
+// refreshableFields are the fields the refresh mode is able to overwrite
+var refreshableFields = []string{"country", "description", "headlineImage", "logo", "website", "spotify", "genres", "members"}
+
+// refreshPolicy controls which complete bands are looked up again and which
+// of their fields may be overwritten
+type refreshPolicy struct {
+	overwrite  map[string]bool
+	staleAfter time.Duration
+	now        time.Time
+}
+
+// parseOverwriteFields turns a comma separated list of field names into a set
+func parseOverwriteFields(list string) (map[string]bool, error) {
+	fields := make(map[string]bool)
+	for _, field := range strings.Split(list, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		if !slices.Contains(refreshableFields, field) {
+			return nil, fmt.Errorf("unknown field %q, expected one of %s", field, strings.Join(refreshableFields, ", "))
+		}
+		fields[field] = true
+	}
+	return fields, nil
+}
+
+// isStale tells whether a band was never checked or was checked too long ago
+func (p *refreshPolicy) isStale(band model.Band) bool {
+	return band.LastChecked == nil || p.now.Sub(*band.LastChecked) >= p.staleAfter
+}
+
+// isProtected tells whether a person vouched for the field, either by editing
+// it or by marking the whole band as reviewed
+func isProtected(band *model.Band, field string) bool {
+	return band.Reviewed || band.Provenance[field].Origin == model.ProvenanceManual
+}
+
+// refreshBandData overwrites the fields allowed by the policy with the new
+// values and returns every overwritten value. Empty fields are left to mergeBandData.
+func refreshBandData(existing *model.Band, updated *BandSearchResult, overwrite map[string]bool) []FieldChange {
+	var changes []FieldChange
+	refresh := func(field, current, value string) bool {
+		if !overwrite[field] || current == "" || value == "" || current == value || isProtected(existing, field) {
+			return false
+		}
+		changes = append(changes, FieldChange{Band: existing.Name, Field: field, Old: current, New: value})
+		return true
+	}
+
+	if refresh("country", existing.Country, updated.Country) {
+		existing.Country = updated.Country
+	}
+	if refresh("description", existing.Description, updated.Description) {
+		existing.Description = updated.Description
+	}
+	if refresh("headlineImage", existing.HeadlineImage, updated.HeadlineImage) {
+		existing.HeadlineImage = updated.HeadlineImage
+	}
+	if refresh("logo", existing.Logo, updated.Logo) {
+		existing.Logo = updated.Logo
+	}
+	if refresh("website", existing.Website, updated.Website) {
+		existing.Website = updated.Website
+	}
+	if refresh("spotify", existing.Spotify, updated.Spotify) {
+		existing.Spotify = updated.Spotify
+	}
+	if refresh("genres", strings.Join(existing.Genres, ", "), strings.Join(updated.Genres, ", ")) {
+		existing.Genres = updated.Genres
+	}
+	if refresh("members", formatMembers(existing.Members), formatMembers(updated.Members)) {
+		existing.Members = updated.Members
+	}
+
+	if len(changes) > 0 {
+		fields := make([]string, 0, len(changes))
+		for _, change := range changes {
+			fields = append(fields, change.Field)
+		}
+		existing.Provenance = existing.Provenance.Set(updated.provenance(), fields...)
+	}
+	return changes
+}
+
+// formatMembers renders a lineup as "Name (Role), ..." to compare and report it
+func formatMembers(members []model.Member) string {
+	parts := make([]string, 0, len(members))
+	for _, member := range members {
+		parts = append(parts, fmt.Sprintf("%s (%s)", member.Name, member.Role))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func searchBandInfo(ctx context.Context, prompt *openai.Prompt, bandName string, dryRun bool) (*BandSearchResult, int, float64, string, error) {
+	usedTokens := 0
+	estimatedCost := 0.0
+	usedModel := ""
+
+	userPrompt, err := prompt.Render(map[string]any{"BandName": bandName})
+	if err != nil {
+		return nil, usedTokens, estimatedCost, usedModel, err
+	}
+
+	primaryModel, _ := openaiClient.Models()
+	resp, err := openaiClient.AskOpenAI(ctx, userPrompt, bandSearchSchema, primaryModel, dryRun)
+	if err != nil {
+		return nil, usedTokens, estimatedCost, usedModel, err
+	}
+
+	if resp == nil {
+		return nil, usedTokens, estimatedCost, usedModel, fmt.Errorf("no response from OpenAI")
+	}
+	usedTokens = resp.TotalUsedTokens
+	estimatedCost = resp.EstimatedCost
+	usedModel = string(resp.UsedModel)
+	if len(resp.OutputText) == 0 {
+		return nil, usedTokens, estimatedCost, usedModel, fmt.Errorf("empty response from OpenAI")
+	}
+
+	content := strings.TrimSpace(resp.OutputText)
+	var result BandSearchResult
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return nil, usedTokens, estimatedCost, usedModel, fmt.Errorf("failed to parse OpenAI response: %w", err)
+	}
+	result.Key = generateBandKey(result.Name)
+	result.PromptVersion = prompt.Version
+	result.Model = usedModel
+	result.Sources = resp.Citations
+
+	return &result, usedTokens, estimatedCost, usedModel, nil
+}
+
+func mergeBandData(existing *model.Band, updated *BandSearchResult) bool {
+	var filled []string
+
+	if existing.Country == "" && updated.Country != "" {
+		existing.Country = updated.Country
+		filled = append(filled, "country")
+	}
+	if existing.Description == "" && updated.Description != "" {
+		existing.Description = updated.Description
+		filled = append(filled, "description")
+	}
+	if existing.HeadlineImage == "" && updated.HeadlineImage != "" {
+		existing.HeadlineImage = updated.HeadlineImage
+		filled = append(filled, "headlineImage")
+	}
+	if existing.Logo == "" && updated.Logo != "" {
+		existing.Logo = updated.Logo
+		filled = append(filled, "logo")
+	}
+	if existing.Website == "" && updated.Website != "" {
+		existing.Website = updated.Website
+		filled = append(filled, "website")
+	}
+	if existing.Spotify == "" && updated.Spotify != "" {
+		existing.Spotify = updated.Spotify
+		filled = append(filled, "spotify")
+	}
+	if len(existing.Genres) == 0 && len(updated.Genres) > 0 {
+		existing.Genres = updated.Genres
+		filled = append(filled, "genres")
+	}
+	if len(existing.Members) == 0 && len(updated.Members) > 0 {
+		existing.Members = updated.Members
+		filled = append(filled, "members")
+	}
+
+	if len(filled) == 0 {
+		return false
+	}
+	existing.Provenance = existing.Provenance.Set(updated.provenance(), filled...)
+	return true
+}
+
+// processBand looks up a single band and works out what should be written.
+// It runs concurrently with other bands, so it must not touch the database.
+func processBand(ctx context.Context, prompt *openai.Prompt, band model.BandRef, existingBand *model.Band, verifier *bandVerifier, refresh *refreshPolicy, dryRun bool) bandResult {
+	// Check if band exists and is complete
+	if existingBand != nil && data.IsBandComplete(*existingBand) {
+		if refresh == nil {
+			slog.InfoContext(ctx, "band skipped", "reason", "already complete")
+			return bandResult{outcome: bandSkipped, reason: "already complete"}
+		}
+		if !refresh.isStale(*existingBand) {
+			slog.InfoContext(ctx, "band skipped", "reason", "checked recently")
+			return bandResult{outcome: bandSkipped, reason: "checked recently"}
+		}
+	}
+
+	// Search for band information
+	result, tokens, cost, usedModel, err := searchBandInfo(ctx, prompt, band.Name, dryRun)
+	outcome := bandResult{tokens: tokens, cost: cost, usedModel: usedModel, promptVersion: prompt.Version}
+	if err != nil {
+		slog.ErrorContext(ctx, "band search failed", "error", err)
+		outcome.reason = err.Error()
+		return outcome
+	}
+
+	// Check if band was found
+	if result.Error != "" {
+		slog.WarnContext(ctx, "band not found")
+		outcome.outcome = bandNotFound
+		outcome.reason = "band not found"
+		return outcome
+	}
+
+	result.Name = data.NormalizeBandName(result.Name)
+
+	// Ensure music genres and band members roles are properly capitalized
+	for i := range result.Genres {
+		result.Genres[i] = data.NormalizeBandName(result.Genres[i])
+	}
+	for i := range result.Members {
+		result.Members[i].Name = data.NormalizeBandName(result.Members[i].Name)
+		result.Members[i].Role = data.NormalizeBandName(result.Members[i].Role)
+	}
+
+	// Hold back dubious results for a person to review
+	needsReview := false
+	if verifier != nil {
+		outcome.verification = verifier.verify(ctx, band.Name, result)
+		slog.InfoContext(ctx, "band verified", "confidence", outcome.verification.confidence, "reasons", outcome.verification.reasons)
+		needsReview = outcome.verification.confidence < verifier.minConfidence
+	}
+
+	if existingBand != nil {
+		// Merge into a copy so the shared lookup table is never modified by workers
+		merged := *existingBand
+		filled := mergeBandData(&merged, result)
+		if refresh != nil {
+			outcome.changes = refreshBandData(&merged, result, refresh.overwrite)
+			for _, change := range outcome.changes {
+				slog.InfoContext(ctx, "band field refreshed", "field", change.Field, "old", change.Old, "new", change.New)
+			}
+		}
+		checkedAt := time.Now().UTC()
+		merged.LastChecked = &checkedAt
+		outcome.band = merged
+		if !filled && len(outcome.changes) == 0 {
+			slog.InfoContext(ctx, "band skipped", "reason", "no new data")
+			outcome.outcome = bandSkipped
+			outcome.reason = "no new data"
+			if refresh != nil {
+				// Remember the check so the band is not looked up again until it goes stale
+				outcome.outcome = bandChecked
+				outcome.reason = "checked, no changes"
+			}
+			return outcome
+		}
+		outcome.outcome = bandUpdated
+		outcome.reason = "updated"
+		if needsReview {
+			outcome.outcome = bandNeedsReview
+			outcome.reason = fmt.Sprintf("sent to review (confidence %.2f)", outcome.verification.confidence)
+		}
+		return outcome
+	}
+
+	outcome.outcome = bandAdded
+	outcome.reason = "added"
+	if needsReview {
+		outcome.outcome = bandNeedsReview
+		outcome.reason = fmt.Sprintf("sent to review (confidence %.2f)", outcome.verification.confidence)
+	}
+	newBand := model.Band{
+		Key:           result.Key,
+		Name:          result.Name,
+		Country:       result.Country,
+		Description:   result.Description,
+		HeadlineImage: result.HeadlineImage,
+		Logo:          result.Logo,
+		Website:       result.Website,
+		Spotify:       result.Spotify,
+		Genres:        result.Genres,
+		Members:       result.Members,
+	}
+	checkedAt := time.Now().UTC()
+	newBand.LastChecked = &checkedAt
+	newBand.Provenance = model.ProvenanceMap{}.Set(result.provenance(), model.ChangedFields(model.Band{}, newBand)...)
+	outcome.band = newBand
+	return outcome
+}
+
+// collectBandResult applies a worker result to the database and stats and
+// returns the status to record in the checkpoint.
+// It is only ever called from a single goroutine.
+func collectBandResult(ctx context.Context, stats *UpdateStats, result bandResult) (updater.ItemStatus, string) {
+	status, reason := applyBandResult(ctx, stats, result)
+
+	item := updater.ItemReport{
+		Key:        result.ref.Key,
+		Name:       result.ref.Name,
+		Status:     status,
+		Outcome:    result.outcome.String(),
+		Reason:     reason,
+		Confidence: result.verification.confidence,
+		Notes:      result.verification.reasons,
+		Model:      result.usedModel,
+		Tokens:     result.tokens,
+		Cost:       result.cost,
+		DurationMs: result.duration.Milliseconds(),
+	}
+	if result.band.Name != "" {
+		item.Name = result.band.Name
+	}
+	if status == updater.StatusFailed {
+		item.Outcome = bandFailed.String()
+		item.Error = reason
+	}
+	for _, change := range result.changes {
+		item.Changes = append(item.Changes, updater.Change{Kind: "refreshed", Field: change.Field, Old: change.Old, New: change.New})
+	}
+	stats.Items = append(stats.Items, item)
+
+	return status, reason
+}
+
+// applyBandResult writes a single result to the database and counts it
+func applyBandResult(ctx context.Context, stats *UpdateStats, result bandResult) (updater.ItemStatus, string) {
+	stats.TotalTokens += result.tokens
+	stats.TotalCost += result.cost
+	if result.usedModel != "" {
+		stats.UsedModel = result.usedModel
+	}
+
+	switch result.outcome {
+	case bandSkipped:
+		stats.SkippedBands++
+		return updater.StatusSkipped, result.reason
+	case bandNotFound:
+		stats.NotFoundBands++
+		return updater.StatusSkipped, result.reason
+	case bandUpdated:
+		if err := data.UpdateBandInDatabase(ctx, result.band); err != nil {
+			slog.ErrorContext(ctx, "failed to update band in database", "error", err)
+			stats.FailedBands++
+			return updater.StatusFailed, err.Error()
+		}
+		slog.InfoContext(ctx, "band updated", "refreshed_fields", len(result.changes))
+		stats.UpdatedBands++
+		if len(result.changes) > 0 {
+			stats.RefreshedBands++
+		}
+		return updater.StatusProcessed, result.reason
+	case bandChecked:
+		if err := data.UpdateBandInDatabase(ctx, result.band); err != nil {
+			slog.ErrorContext(ctx, "failed to update band in database", "error", err)
+			stats.FailedBands++
+			return updater.StatusFailed, err.Error()
+		}
+		stats.CheckedBands++
+		return updater.StatusProcessed, result.reason
+	case bandAdded:
+		if err := data.AddBandToDatabase(ctx, result.band); err != nil {
+			slog.ErrorContext(ctx, "failed to add band to database", "error", err)
+			stats.FailedBands++
+			return updater.StatusFailed, err.Error()
+		}
+		slog.InfoContext(ctx, "band added")
+		stats.AddedBands++
+		return updater.StatusProcessed, result.reason
+	case bandNeedsReview:
+		review := model.PendingReview{
+			Kind:       model.ReviewBand,
+			Key:        result.band.Key,
+			Band:       &result.band,
+			Confidence: result.verification.confidence,
+			Reasons:    result.verification.reasons,
+		}
+		if err := data.AddPendingReview(ctx, review); err != nil {
+			slog.ErrorContext(ctx, "failed to add band to the review queue", "error", err)
+			stats.FailedBands++
+			return updater.StatusFailed, err.Error()
+		}
+		slog.InfoContext(ctx, "band sent to the review queue", "confidence", result.verification.confidence)
+		stats.ReviewBands++
+		return updater.StatusSkipped, result.reason
+	default:
+		stats.FailedBands++
+		return updater.StatusFailed, result.reason
+	}
+}
+
+// addMissingBands stops picking up new bands once stop is done; abort bounds
+// the requests of bands that are already in progress.
+func addMissingBands(stop, abort context.Context, prompt *openai.Prompt, bandName string, verifier *bandVerifier, refresh *refreshPolicy, dryRun bool, concurrency int, checkpoint *updater.Checkpoint, mode updater.ResumeMode) *UpdateStats {
+	stats := &UpdateStats{PromptVersion: prompt.Version}
+
+	// Collect all bands from festivals
+	festivalBands, err := data.CollectAllFestivalBands(stop)
+	if err != nil {
+		slog.ErrorContext(stop, "failed to read festival bands", "error", err)
+		return stats
+	}
+
+	// Filter by band name if provided
+	if bandName != "" {
+		found := false
+		for _, band := range festivalBands {
+			if band.Name == bandName {
+				festivalBands = []model.BandRef{band}
+				found = true
+				break
+			}
+		}
+		if !found {
+			slog.WarnContext(stop, "band not found in any festival", "band", bandName)
+			return stats
+		}
+	}
+
+	slog.InfoContext(stop, "festival bands collected", "bands", len(festivalBands))
+
+	// Leave out bands already handled according to the checkpoint
+	if mode != updater.ModeFresh {
+		pending := make([]model.BandRef, 0, len(festivalBands))
+		for _, band := range festivalBands {
+			if checkpoint.ShouldProcess(band.Key, mode) {
+				pending = append(pending, band)
+			}
+		}
+		stats.ResumedBands = len(festivalBands) - len(pending)
+		festivalBands = pending
+		slog.InfoContext(stop, "continuing from the checkpoint", "bands", len(festivalBands), "resumed", stats.ResumedBands)
+	}
+
+	stats.TotalBands = len(festivalBands)
+
+	// Get existing bands
+	existingBandsList, err := data.GetBands(stop)
+	if err != nil {
+		slog.ErrorContext(stop, "failed to read bands", "error", err)
+		return stats
+	}
+
+	// Create a map of existing bands for quick lookup. Workers only read from it.
+	existingBands := make(map[string]*model.Band)
+	for i := range existingBandsList {
+		existingBands[existingBandsList[i].Key] = &existingBandsList[i]
+	}
+
+	// Process bands concurrently; database writes happen in order in the collector
+	stats.ProcessedBands = updater.Run(stop, stats.TotalBands, concurrency,
+		func(i int, log *logging.Buffer) bandResult {
+			band := festivalBands[i]
+			started := time.Now()
+			ctx, span := updater.StartItem(updater.ItemContext(abort, log, "band", band.Key), "band", band.Key)
+			slog.InfoContext(ctx, "processing band", "name", band.Name, "position", i+1, "total", stats.TotalBands)
+			result := processBand(ctx, prompt, band, existingBands[band.Key], verifier, refresh, dryRun)
+			result.ref = band
+			result.duration = time.Since(started)
+			result.span = span
+			return result
+		},
+		func(i int, result bandResult, log *logging.Buffer) {
+			ctx := updater.ItemContext(trace.ContextWithSpan(stop, result.span), log, "band", festivalBands[i].Key)
+			status, reason := collectBandResult(ctx, stats, result)
+			updater.EndItem(result.span, status, reason)
+			// Bands cut short by a shutdown stay unrecorded so a resumed run picks them up
+			if dryRun || (status == updater.StatusFailed && abort.Err() != nil) {
+				return
+			}
+			entry := updater.CheckpointEntry{Status: status, Reason: reason, PromptVersion: result.promptVersion}
+			if err := checkpoint.Record(festivalBands[i].Key, entry); err != nil {
+				slog.ErrorContext(ctx, "failed to save checkpoint", "error", err)
+			}
+		},
+	)
+	stats.Interrupted = stats.ProcessedBands < stats.TotalBands
+
+	return stats
+}
+
+// summaryTemplate renders the pull request summary from the run report
+//
+//go:embed summary.md.tmpl
+var summaryTemplate string
+
+// buildReport turns the stats of a run into its report
+func buildReport(stats *UpdateStats, startedAt time.Time, dryRun bool) *updater.Report {
+	report := updater.NewReport("band_updater", startedAt)
+	report.DryRun = dryRun
+	report.PromptVersion = stats.PromptVersion
+	report.Model = stats.UsedModel
+	report.Tokens = stats.TotalTokens
+	report.Cost = stats.TotalCost
+	report.Total = stats.TotalBands
+	report.Processed = stats.ProcessedBands
+	report.Resumed = stats.ResumedBands
+	report.Interrupted = stats.Interrupted
+	report.Counts = map[string]int{
+		"added":     stats.AddedBands,
+		"updated":   stats.UpdatedBands,
+		"skipped":   stats.SkippedBands,
+		"notFound":  stats.NotFoundBands,
+		"failed":    stats.FailedBands,
+		"review":    stats.ReviewBands,
+		"refreshed": stats.RefreshedBands,
+		"checked":   stats.CheckedBands,
+	}
+	if stats.Items != nil {
+		report.Items = stats.Items
+	}
+	return report
+}
+
+func generateSummary(report *updater.Report) (string, error) {
+	return report.Render(summaryTemplate)
+}
+
+// Options configures a band update run
+type Options struct {
+	DryRun            bool
+	Band              string
+	Concurrency       int
+	RequestsPerMinute int
+	RequestTimeout    time.Duration
+	Model             string
+	FallbackModel     string
+	Timeout           time.Duration
+	Checkpoint        string
+	Report            string
+	Summary           string
+	Prompt            string
+	Resume            bool
+	RetryFailed       bool
+	Refresh           bool
+	OverwriteFields   string
+	StaleAfter        time.Duration
+	MinConfidence     float64
+}
+
+// RegisterFlags defines the command line flags of a band update run
+func (o *Options) RegisterFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&o.DryRun, "dry-run", false, "Enable dry run mode")
+	fs.StringVar(&o.Band, "band", "", "Specify band name")
+	fs.IntVar(&o.Concurrency, "concurrency", 4, "Number of bands processed in parallel")
+	fs.IntVar(&o.RequestsPerMinute, "rpm", 60, "Maximum OpenAI requests per minute (0 disables the limit)")
+	fs.DurationVar(&o.RequestTimeout, "request-timeout", openai.DefaultRequestTimeout, "Maximum duration of a single OpenAI request")
+	fs.StringVar(&o.Model, "model", openai.PrimaryModel, "OpenAI model used to look up bands")
+	fs.StringVar(&o.FallbackModel, "fallback-model", openai.FallbackModel, "OpenAI model used when the primary one is rate limited")
+	fs.DurationVar(&o.Timeout, "timeout", 0, "Maximum duration of the whole run (0 means no limit)")
+	fs.StringVar(&o.Checkpoint, "checkpoint", "band_update_checkpoint.json", "Checkpoint file recording per-band progress")
+	fs.StringVar(&o.Report, "report", "band_update_report.json", "JSON report of the run")
+	fs.StringVar(&o.Summary, "summary", "band_update_summary.md", "Markdown summary of the run")
+	fs.StringVar(&o.Prompt, "prompt", "scripts/band_prompt.md", "Prompt template used to look up bands")
+	fs.BoolVar(&o.Resume, "resume", false, "Continue from the checkpoint, skipping bands already handled")
+	fs.BoolVar(&o.RetryFailed, "retry-failed", false, "Only retry bands that failed in the checkpointed run")
+	fs.BoolVar(&o.Refresh, "refresh", false, "Look up complete bands again and correct stale values")
+	fs.StringVar(&o.OverwriteFields, "overwrite-fields", defaultOverwriteFields, "Comma separated fields the refresh mode may overwrite")
+	fs.DurationVar(&o.StaleAfter, "stale-after", 90*24*time.Hour, "Refresh complete bands last checked longer ago than this")
+	fs.Float64Var(&o.MinConfidence, "min-confidence", 0.7, "Results scoring below this confidence go to the review queue (0 disables verification)")
+}
+
+// Run adds the bands of the festival lineups missing from the database and
+// completes the ones with missing information. It returns an error when the
+// run cannot start, or when it is interrupted by a signal.
+func Run(ctx context.Context, opts Options) error {
+	startedAt := time.Now()
+
+	if opts.Resume && opts.RetryFailed {
+		return errors.New("--resume and --retry-failed cannot be combined")
+	}
+
+	if opts.Concurrency < 1 {
+		return errors.New("--concurrency must be at least 1")
+	}
+
+	if opts.MinConfidence < 0 || opts.MinConfidence > 1 {
+		return errors.New("--min-confidence must be between 0 and 1")
+	}
+
+	var refreshMode *refreshPolicy
+	if opts.Refresh {
+		overwrite, err := parseOverwriteFields(opts.OverwriteFields)
+		if err != nil {
+			return fmt.Errorf("--overwrite-fields: %w", err)
+		}
+		refreshMode = &refreshPolicy{overwrite: overwrite, staleAfter: opts.StaleAfter, now: time.Now()}
+		slog.InfoContext(ctx, "refresh mode", "overwrite_fields", opts.OverwriteFields, "stale_after", opts.StaleAfter)
+	}
+
+	if opts.DryRun {
+		slog.InfoContext(ctx, "dry-run mode, no API calls are made and no files are modified")
+	}
+
+	if opts.Band != "" {
+		slog.InfoContext(ctx, "single band mode", "band", opts.Band)
+	}
+
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" && !opts.DryRun {
+		return errors.New("OPENAI_API_KEY environment variable not set")
+	}
+
+	openaiClient = openai.NewOpenAIClient(apiKey)
+	openaiClient.SetRequestsPerMinute(opts.RequestsPerMinute)
+	openaiClient.SetRequestTimeout(opts.RequestTimeout)
+	openaiClient.SetModels(opts.Model, opts.FallbackModel)
+
+	// Load prompt template
+	prompt, err := openai.LoadPrompt(opts.Prompt)
+	if err != nil {
+		return fmt.Errorf("loading prompt template: %w", err)
+	}
+
+	// Load the checkpoint of a previous run when continuing from it
+	mode := updater.ModeFresh
+	checkpoint := updater.NewCheckpoint(opts.Checkpoint)
+	if opts.Resume || opts.RetryFailed {
+		mode = updater.ModeResume
+		if opts.RetryFailed {
+			mode = updater.ModeRetryFailed
+		}
+		checkpoint, err = updater.LoadCheckpoint(opts.Checkpoint)
+		if err != nil {
+			return fmt.Errorf("loading checkpoint: %w", err)
+		}
+	}
+
+	// Stop on SIGINT/SIGTERM or when the whole-run deadline expires
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	stop, abort, release := updater.NotifyShutdown(ctx)
+	defer release()
+
+	var verifier *bandVerifier
+	if opts.MinConfidence > 0 {
+		verifier = &bandVerifier{checker: urlcheck.NewChecker(urlcheck.Options{}), minConfidence: opts.MinConfidence}
+	}
+
+	stats := addMissingBands(stop, abort, prompt, opts.Band, verifier, refreshMode, opts.DryRun, opts.Concurrency, checkpoint, mode)
+
+	// Generate the report and the summary rendered from it
+	report := buildReport(stats, startedAt, opts.DryRun)
+	report.Finish(time.Now())
+	if err := report.WriteJSON(opts.Report); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	summary, err := generateSummary(report)
+	if err != nil {
+		return fmt.Errorf("generating summary: %w", err)
+	}
+	if err := os.WriteFile(opts.Summary, []byte(summary), 0600); err != nil {
+		return fmt.Errorf("writing summary: %w", err)
+	}
+
+	if stats.Interrupted {
+		slog.WarnContext(ctx, "band update stopped early", "cause", context.Cause(stop), "summary", opts.Summary, "report", opts.Report)
+		if errors.Is(context.Cause(stop), updater.ErrInterrupted) {
+			return fmt.Errorf("band update stopped early: %w", context.Cause(stop))
+		}
+		return nil
+	}
+
+	slog.InfoContext(ctx, "band update completed", "summary", opts.Summary, "report", opts.Report)
+	return nil
+}
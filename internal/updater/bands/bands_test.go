@@ -0,0 +1,672 @@
+package bands
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/data"
+	"github.com/neovasili/metal-fests/internal/model"
+	"github.com/neovasili/metal-fests/internal/updater"
+	"github.com/neovasili/metal-fests/internal/urlcheck"
+)
+
+func TestGenerateBandKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		bandName string
+		expected string
+	}{
+		{
+			name:     "Simple band name",
+			bandName: "Metallica",
+			expected: "metallica",
+		},
+		{
+			name:     "Band name with spaces",
+			bandName: "Iron Maiden",
+			expected: "iron-maiden",
+		},
+		{
+			name:     "Band name with special characters",
+			bandName: "Motörhead",
+			expected: "motrhead",
+		},
+		{
+			name:     "Band name with ampersand and spaces",
+			bandName: "Judas Priest & Friends",
+			expected: "judas-priest-friends",
+		},
+		{
+			name:     "Band name with multiple consecutive spaces",
+			bandName: "Foo   Bar",
+			expected: "foo-bar",
+		},
+		{
+			name:     "Band name with leading and trailing hyphens",
+			bandName: "---Test Band---",
+			expected: "test-band",
+		},
+		{
+			name:     "Band name with numbers",
+			bandName: "AC/DC 2024",
+			expected: "acdc-2024",
+		},
+		{
+			name:     "Band name all uppercase",
+			bandName: "BLACK SABBATH",
+			expected: "black-sabbath",
+		},
+		{
+			name:     "Band name with parentheses",
+			bandName: "Foo (Band) Bar",
+			expected: "foo-band-bar",
+		},
+		{
+			name:     "Band name with dots",
+			bandName: "A.B.C. Band",
+			expected: "abc-band",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := generateBandKey(tt.bandName)
+			if result != tt.expected {
+				t.Errorf("generateBandKey(%q) = %q, want %q", tt.bandName, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMergeBandData(t *testing.T) {
+	tests := []struct {
+		name            string
+		existing        model.Band
+		updated         BandSearchResult
+		expectedChanges bool
+		checkField      func(model.Band) bool
+	}{
+		{
+			name: "Update country when empty",
+			existing: model.Band{
+				Key:     "metallica",
+				Name:    "Metallica",
+				Country: "",
+			},
+			updated: BandSearchResult{
+				Country: "USA",
+			},
+			expectedChanges: true,
+			checkField: func(b model.Band) bool {
+				return b.Country == "USA"
+			},
+		},
+		{
+			name: "Do not update country when not empty",
+			existing: model.Band{
+				Key:     "metallica",
+				Name:    "Metallica",
+				Country: "USA",
+			},
+			updated: BandSearchResult{
+				Country: "Canada",
+			},
+			expectedChanges: false,
+			checkField: func(b model.Band) bool {
+				return b.Country == "USA"
+			},
+		},
+		{
+			name: "Update genres when empty",
+			existing: model.Band{
+				Key:    "metallica",
+				Name:   "Metallica",
+				Genres: []string{},
+			},
+			updated: BandSearchResult{
+				Genres: []string{"Heavy Metal", "Thrash Metal"},
+			},
+			expectedChanges: true,
+			checkField: func(b model.Band) bool {
+				return len(b.Genres) == 2 && b.Genres[0] == "Heavy Metal" && b.Genres[1] == "Thrash Metal"
+			},
+		},
+		{
+			name: "Do not update genres when not empty",
+			existing: model.Band{
+				Key:    "metallica",
+				Name:   "Metallica",
+				Genres: []string{"Heavy Metal"},
+			},
+			updated: BandSearchResult{
+				Genres: []string{"Thrash Metal"},
+			},
+			expectedChanges: false,
+			checkField: func(b model.Band) bool {
+				return len(b.Genres) == 1 && b.Genres[0] == "Heavy Metal"
+			},
+		},
+		{
+			name: "Update members when empty",
+			existing: model.Band{
+				Key:     "metallica",
+				Name:    "Metallica",
+				Members: []model.Member{},
+			},
+			updated: BandSearchResult{
+				Members: []model.Member{
+					{Name: "James Hetfield", Role: "Vocals"},
+				},
+			},
+			expectedChanges: true,
+			checkField: func(b model.Band) bool {
+				return len(b.Members) == 1 && b.Members[0].Name == "James Hetfield"
+			},
+		},
+		{
+			name: "Multiple updates at once",
+			existing: model.Band{
+				Key:     "metallica",
+				Name:    "Metallica",
+				Country: "",
+				Website: "",
+			},
+			updated: BandSearchResult{
+				Country: "USA",
+				Website: "https://metallica.com",
+			},
+			expectedChanges: true,
+			checkField: func(b model.Band) bool {
+				return b.Country == "USA" && b.Website == "https://metallica.com"
+			},
+		},
+		{
+			name: "No updates when all fields exist",
+			existing: model.Band{
+				Key:           "metallica",
+				Name:          "Metallica",
+				Country:       "USA",
+				Description:   "Band",
+				HeadlineImage: "image.jpg",
+				Logo:          "logo.png",
+				Website:       "website.com",
+				Spotify:       "spotify.com",
+				Genres:        []string{"Metal"},
+				Members:       []model.Member{{Name: "Member"}},
+			},
+			updated: BandSearchResult{
+				Country:       "Canada",
+				Description:   "New description",
+				HeadlineImage: "new.jpg",
+				Logo:          "new.png",
+				Website:       "new.com",
+				Spotify:       "new-spotify.com",
+				Genres:        []string{"New Genre"},
+				Members:       []model.Member{{Name: "New Member"}},
+			},
+			expectedChanges: false,
+			checkField: func(b model.Band) bool {
+				return b.Country == "USA" && b.Description == "Band"
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := mergeBandData(&tt.existing, &tt.updated)
+			if result != tt.expectedChanges {
+				t.Errorf("mergeBandData() = %v, want %v", result, tt.expectedChanges)
+			}
+			if !tt.checkField(tt.existing) {
+				t.Errorf("mergeBandData() did not update fields as expected")
+			}
+		})
+	}
+}
+
+func TestGenerateSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		stats    UpdateStats
+		contains []string
+	}{
+		{
+			name: "Summary with updates",
+			stats: UpdateStats{
+				TotalBands:    10,
+				AddedBands:    3,
+				UpdatedBands:  2,
+				SkippedBands:  4,
+				NotFoundBands: 1,
+				TotalTokens:   5000,
+				TotalCost:     0.15,
+				UsedModel:     "gpt-4o-mini",
+			},
+			contains: []string{
+				"# 🤖 Automated Bands Information Update",
+				"**Total Bands Processed**: 10",
+				"**New Bands Added**: 3",
+				"**Existing Bands Updated**: 2",
+				"**Bands Skipped**",
+				"**Bands Not Found**: 1",
+				"**Total Tokens**: 5000",
+				"**Total Cost**: 0.15 €",
+				"gpt-4o-mini",
+				"`metal-fests update bands`",
+				"*This PR was automatically generated. Please review the changes before merging.*",
+			},
+		},
+		{
+			name: "Summary with no updates",
+			stats: UpdateStats{
+				TotalBands:    10,
+				AddedBands:    0,
+				UpdatedBands:  0,
+				SkippedBands:  10,
+				NotFoundBands: 0,
+				TotalTokens:   0,
+			},
+			contains: []string{
+				"# 🤖 Automated Bands Information Update",
+				"**Total Bands Processed**: 10",
+				"**New Bands Added**: 0",
+				"**Existing Bands Updated**: 0",
+				"*No updates were needed. All band information is up to date.*",
+			},
+		},
+		{
+			name: "Summary of an interrupted run",
+			stats: UpdateStats{
+				TotalBands:     10,
+				ProcessedBands: 4,
+				UpdatedBands:   2,
+				Interrupted:    true,
+			},
+			contains: []string{
+				"**Run Interrupted**: only 4 of 10 bands were processed",
+				"*This PR was automatically generated. Please review the changes before merging.*",
+			},
+		},
+		{
+			name: "Summary with bands sent to review",
+			stats: UpdateStats{
+				TotalBands:  3,
+				ReviewBands: 1,
+				Items: []updater.ItemReport{
+					{Name: "Metalica", Outcome: "review", Confidence: 0.45, Notes: []string{"logo does not resolve (status 404)"}},
+				},
+			},
+			contains: []string{
+				"**Bands Sent to Review** (low confidence): 1",
+				"<summary>⏸️ Bands Sent to Review</summary>",
+				"- Metalica (confidence 0.45): logo does not resolve (status 404)",
+			},
+		},
+		{
+			name: "Summary of a refresh run",
+			stats: UpdateStats{
+				TotalBands:     3,
+				UpdatedBands:   1,
+				RefreshedBands: 1,
+				CheckedBands:   2,
+				Items: []updater.ItemReport{
+					{Name: "Metallica", Outcome: "updated", Changes: []updater.Change{
+						{Kind: "refreshed", Field: "website", Old: "http://old.com", New: "https://metallica.com"},
+					}},
+				},
+			},
+			contains: []string{
+				"**Bands Refreshed** (values overwritten): 1",
+				"**Bands Checked Without Changes**: 2",
+				"<summary>🔁 Refreshed Fields</summary>",
+				"| Metallica | website | http://old.com | https://metallica.com |",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := generateSummary(buildReport(&tt.stats, time.Now(), false))
+			if err != nil {
+				t.Fatalf("generateSummary() failed: %v", err)
+			}
+			for _, substr := range tt.contains {
+				if !strings.Contains(summary, substr) {
+					t.Errorf("generateSummary() missing expected substring: %q", substr)
+				}
+			}
+		})
+	}
+}
+
+func TestBandSearchSchema_CoversCompletenessFields(t *testing.T) {
+	properties := bandSearchSchema["properties"].(map[string]any)
+
+	completeBand := model.Band{
+		Key:           "metallica",
+		Name:          "Metallica",
+		Country:       "USA",
+		Description:   "American heavy metal band",
+		HeadlineImage: "https://example.com/image.jpg",
+		Logo:          "https://example.com/logo.png",
+		Website:       "https://metallica.com",
+		Spotify:       "https://open.spotify.com/artist/123",
+		Genres:        []string{"Heavy Metal", "Thrash Metal"},
+		Members:       []model.Member{{Name: "James Hetfield", Role: "Vocals"}},
+	}
+	content, _ := json.Marshal(completeBand)
+	var fields map[string]any
+	_ = json.Unmarshal(content, &fields)
+
+	// Any field data.IsBandComplete depends on must be requested from the model,
+	// otherwise bands missing it can never be completed
+	for field := range fields {
+		without := make(map[string]any)
+		for key, value := range fields {
+			if key != field {
+				without[key] = value
+			}
+		}
+		content, _ := json.Marshal(without)
+		var band model.Band
+		_ = json.Unmarshal(content, &band)
+
+		if data.IsBandComplete(band) {
+			continue
+		}
+		if _, exists := properties[field]; !exists {
+			t.Errorf("data.IsBandComplete requires %q but the search schema does not request it", field)
+		}
+	}
+}
+
+func TestBandSearchSchema_FieldsAreMerged(t *testing.T) {
+	properties := bandSearchSchema["properties"].(map[string]any)
+	required := bandSearchSchema["required"].([]string)
+	if len(required) != len(properties) {
+		t.Errorf("expected every property to be required, got %d of %d", len(required), len(properties))
+	}
+
+	// Fields handled outside mergeBandData
+	notMerged := map[string]bool{"error": true, "key": true, "name": true}
+
+	for field, property := range properties {
+		if notMerged[field] {
+			continue
+		}
+		var value any = "value"
+		if property.(map[string]any)["type"] == "array" {
+			value = []any{}
+			if field == "members" {
+				value = []any{map[string]any{"name": "Name", "role": "Role"}}
+			} else {
+				value = []any{"Value"}
+			}
+		}
+		content, _ := json.Marshal(map[string]any{field: value})
+		var result BandSearchResult
+		if err := json.Unmarshal(content, &result); err != nil {
+			t.Fatalf("schema property %q does not match BandSearchResult: %v", field, err)
+		}
+		if !mergeBandData(&model.Band{}, &result) {
+			t.Errorf("schema property %q is requested but never merged into the band", field)
+		}
+	}
+}
+
+func TestMergeBandData_RecordsProvenance(t *testing.T) {
+	existing := model.Band{
+		Key:        "test-band",
+		Name:       "Test Band",
+		Country:    "Spain",
+		Provenance: model.ProvenanceMap{"country": {Origin: model.ProvenanceManual}},
+	}
+	shared := existing.Provenance
+	updated := BandSearchResult{
+		Country:       "Portugal",
+		Website:       "https://testband.com",
+		Genres:        []string{"Heavy Metal", "Power Metal"},
+		Model:         "gpt-5",
+		PromptVersion: "band-v2",
+		Sources:       []model.Citation{{URL: "https://testband.com/about"}},
+	}
+
+	if !mergeBandData(&existing, &updated) {
+		t.Fatal("mergeBandData() = false, want true")
+	}
+
+	if existing.Provenance["country"].Origin != model.ProvenanceManual {
+		t.Errorf("country provenance = %+v, want the manual one kept", existing.Provenance["country"])
+	}
+	for _, field := range []string{"website", "genres"} {
+		provenance := existing.Provenance[field]
+		if provenance.Origin != model.ProvenanceAI || provenance.Model != "gpt-5" || provenance.PromptVersion != "band-v2" {
+			t.Errorf("%s provenance = %+v, want ai from gpt-5 and band-v2", field, provenance)
+		}
+		if len(provenance.Sources) != 1 || provenance.Sources[0].URL != "https://testband.com/about" {
+			t.Errorf("%s sources = %v, want the cited page", field, provenance.Sources)
+		}
+	}
+	if len(shared) != 1 {
+		t.Errorf("mergeBandData() modified the provenance shared with the original band: %v", shared)
+	}
+}
+
+func TestBandVerifierVerify(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/ok" {
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	verifier := &bandVerifier{checker: urlcheck.NewChecker(urlcheck.Options{Timeout: 2 * time.Second, AllowLoopback: true}), minConfidence: 0.7}
+
+	tests := []struct {
+		name       string
+		requested  string
+		result     BandSearchResult
+		confidence float64
+		reasons    int
+	}{
+		{
+			name:      "Everything checks out",
+			requested: "Iron Maiden",
+			result: BandSearchResult{
+				Name:    "Iron Maiden",
+				Website: server.URL + "/ok",
+				Logo:    server.URL + "/ok",
+				Genres:  []string{"Heavy Metal", "NWOBHM"},
+			},
+			confidence: 1,
+		},
+		{
+			name:      "Broken URLs and unknown genres lower the score",
+			requested: "Iron Maiden",
+			result: BandSearchResult{
+				Name:    "Iron Maiden",
+				Website: server.URL + "/ok",
+				Logo:    server.URL + "/missing",
+				Genres:  []string{"Heavy Metal", "Kickboxing"},
+			},
+			confidence: 0.4 + 0.4*0.5 + 0.2*0.5,
+			reasons:    2,
+		},
+		{
+			name:      "A different band with only a name",
+			requested: "Iron Maiden",
+			result:    BandSearchResult{Name: "Iron Savior"},
+			// Without URLs or genres only the name is taken into account
+			confidence: 1 - 5.0/11,
+			reasons:    1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			verification := verifier.verify(context.Background(), tt.requested, &tt.result)
+			if math.Abs(verification.confidence-tt.confidence) > 1e-9 {
+				t.Errorf("verify() confidence = %v, want %v", verification.confidence, tt.confidence)
+			}
+			if len(verification.reasons) != tt.reasons {
+				t.Errorf("verify() reasons = %v, want %d reasons", verification.reasons, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestCollectBandResult_NeedsReview(t *testing.T) {
+	tempDir := t.TempDir()
+	originalDBFile := data.SetDBFilePathForTesting(filepath.Join(tempDir, "db.json"))
+	defer data.SetDBFilePathForTesting(originalDBFile)
+
+	stats := &UpdateStats{}
+	result := bandResult{
+		outcome:      bandNeedsReview,
+		reason:       "sent to review (confidence 0.40)",
+		band:         model.Band{Key: "metalica", Name: "Metalica"},
+		verification: bandVerification{confidence: 0.4, reasons: []string{"logo does not resolve (status 404)"}},
+	}
+
+	status, _ := collectBandResult(context.Background(), stats, result)
+	if status != updater.StatusSkipped {
+		t.Errorf("collectBandResult() status = %v, want %v", status, updater.StatusSkipped)
+	}
+	if stats.ReviewBands != 1 || len(stats.Items) != 1 || stats.Items[0].Outcome != "review" {
+		t.Errorf("expected one band sent to review, got %+v", stats)
+	}
+
+	reviews, err := data.GetPendingReviews(context.Background())
+	if err != nil {
+		t.Fatalf("GetPendingReviews failed: %v", err)
+	}
+	if len(reviews) != 1 || reviews[0].Key != "metalica" || reviews[0].Confidence != 0.4 {
+		t.Errorf("expected the band in the review queue, got %+v", reviews)
+	}
+}
+
+func TestRefreshBandData(t *testing.T) {
+	base := model.Band{
+		Key:     "metallica",
+		Name:    "Metallica",
+		Country: "United States",
+		Website: "http://old-metallica.com",
+		Logo:    "https://example.com/old-logo.png",
+		Genres:  []string{"Thrash Metal", "Heavy Metal"},
+		Members: []model.Member{{Name: "James Hetfield", Role: "Vocals"}},
+	}
+	updated := BandSearchResult{
+		Country:       "USA",
+		Website:       "https://metallica.com",
+		Logo:          "https://example.com/logo.png",
+		Spotify:       "https://open.spotify.com/artist/metallica",
+		Genres:        []string{"Thrash Metal", "Heavy Metal"},
+		Members:       []model.Member{{Name: "James Hetfield", Role: "Vocals, Rhythm Guitar"}},
+		PromptVersion: "band-v2",
+	}
+	overwrite := map[string]bool{"website": true, "logo": true, "spotify": true, "genres": true, "members": true}
+
+	tests := []struct {
+		name     string
+		prepare  func(b *model.Band)
+		expected []string
+	}{
+		{
+			name:     "Overwrites the allowed fields that changed",
+			prepare:  func(b *model.Band) {},
+			expected: []string{"logo", "website", "members"},
+		},
+		{
+			name: "Skips fields edited by hand",
+			prepare: func(b *model.Band) {
+				b.Provenance = model.ProvenanceMap{"website": {Origin: model.ProvenanceManual}}
+			},
+			expected: []string{"logo", "members"},
+		},
+		{
+			name:     "Skips reviewed bands",
+			prepare:  func(b *model.Band) { b.Reviewed = true },
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			band := base
+			tt.prepare(&band)
+			changes := refreshBandData(&band, &updated, overwrite)
+
+			var fields []string
+			for _, change := range changes {
+				fields = append(fields, change.Field)
+				if band.Provenance[change.Field].PromptVersion != "band-v2" {
+					t.Errorf("expected ai provenance for %s, got %+v", change.Field, band.Provenance[change.Field])
+				}
+			}
+			if strings.Join(fields, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("refreshBandData() changed %v, want %v", fields, tt.expected)
+			}
+			if band.Country != "United States" {
+				t.Errorf("country is not in the policy and should be kept, got %q", band.Country)
+			}
+			if band.Spotify != "" {
+				t.Errorf("empty fields are left to mergeBandData, got spotify %q", band.Spotify)
+			}
+		})
+	}
+
+	band := base
+	changes := refreshBandData(&band, &updated, overwrite)
+	if changes[0].Old != "https://example.com/old-logo.png" || changes[0].New != "https://example.com/logo.png" {
+		t.Errorf("unexpected change %+v", changes[0])
+	}
+	if changes[2].Old != "James Hetfield (Vocals)" || changes[2].New != "James Hetfield (Vocals, Rhythm Guitar)" {
+		t.Errorf("unexpected members change %+v", changes[2])
+	}
+}
+
+func TestRefreshPolicyIsStale(t *testing.T) {
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	policy := refreshPolicy{staleAfter: 30 * 24 * time.Hour, now: now}
+	recent := now.Add(-24 * time.Hour)
+	old := now.Add(-60 * 24 * time.Hour)
+
+	tests := []struct {
+		name     string
+		checked  *time.Time
+		expected bool
+	}{
+		{name: "Never checked", checked: nil, expected: true},
+		{name: "Checked recently", checked: &recent, expected: false},
+		{name: "Checked long ago", checked: &old, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := policy.isStale(model.Band{LastChecked: tt.checked}); result != tt.expected {
+				t.Errorf("isStale() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseOverwriteFields(t *testing.T) {
+	fields, err := parseOverwriteFields(defaultOverwriteFields)
+	if err != nil {
+		t.Fatalf("parseOverwriteFields() error = %v", err)
+	}
+	if len(fields) != 5 || !fields["website"] || !fields["members"] {
+		t.Errorf("parseOverwriteFields() = %v", fields)
+	}
+
+	if _, err := parseOverwriteFields("website, name"); err == nil {
+		t.Error("parseOverwriteFields() expected an error for a field that cannot be refreshed")
+	}
+}
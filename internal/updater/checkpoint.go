@@ -0,0 +1,143 @@
+package updater
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// ItemStatus is the outcome recorded for an item in a checkpoint
+type ItemStatus string
+
+const (
+	StatusProcessed ItemStatus = "processed"
+	StatusFailed    ItemStatus = "failed"
+	StatusSkipped   ItemStatus = "skipped"
+)
+
+// ResumeMode selects which items a run picks up from an existing checkpoint
+type ResumeMode int
+
+const (
+	// ModeFresh processes every item and starts a new checkpoint
+	ModeFresh ResumeMode = iota
+	// ModeResume skips every item already recorded in the checkpoint
+	ModeResume
+	// ModeRetryFailed only processes items that failed last time
+	ModeRetryFailed
+)
+
+// CheckpointEntry records what happened to a single item
+type CheckpointEntry struct {
+	Status        ItemStatus `json:"status"`
+	Reason        string     `json:"reason,omitempty"`
+	PromptVersion string     `json:"promptVersion,omitempty"`
+	UpdatedAt     time.Time  `json:"updatedAt"`
+}
+
+// Checkpoint tracks per-item progress of an updater run so that an aborted
+// or partially failed run can be continued. It is saved after every recorded
+// item and is not safe for concurrent use; record from the collector only.
+type Checkpoint struct {
+	UpdatedAt time.Time                  `json:"updatedAt"`
+	Items     map[string]CheckpointEntry `json:"items"`
+
+	path string
+}
+
+// NewCheckpoint creates an empty checkpoint stored at path.
+// An empty path keeps the checkpoint in memory only.
+func NewCheckpoint(path string) *Checkpoint {
+	return &Checkpoint{
+		Items: make(map[string]CheckpointEntry),
+		path:  path,
+	}
+}
+
+// LoadCheckpoint reads the checkpoint stored at path.
+// A missing file results in an empty checkpoint.
+func LoadCheckpoint(path string) (*Checkpoint, error) {
+	checkpoint := NewCheckpoint(path)
+
+	// #nosec G304 - path comes from validated command-line arguments
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return checkpoint, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(content, checkpoint); err != nil {
+		return nil, err
+	}
+	if checkpoint.Items == nil {
+		checkpoint.Items = make(map[string]CheckpointEntry)
+	}
+	return checkpoint, nil
+}
+
+// ShouldProcess reports whether an item needs to be processed in the given mode
+func (c *Checkpoint) ShouldProcess(key string, mode ResumeMode) bool {
+	entry, recorded := c.Items[key]
+	switch mode {
+	case ModeResume:
+		return !recorded
+	case ModeRetryFailed:
+		return recorded && entry.Status == StatusFailed
+	default:
+		return true
+	}
+}
+
+// Record stores the outcome of an item and saves the checkpoint
+func (c *Checkpoint) Record(key string, entry CheckpointEntry) error {
+	now := time.Now().UTC()
+	entry.UpdatedAt = now
+	c.Items[key] = entry
+	c.UpdatedAt = now
+	return c.save()
+}
+
+// Keys returns the sorted keys recorded with the given status
+func (c *Checkpoint) Keys(status ItemStatus) []string {
+	keys := make([]string, 0)
+	for key, entry := range c.Items {
+		if entry.Status == status {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// save writes the checkpoint atomically so a crash never leaves a truncated file
+func (c *Checkpoint) save() error {
+	if c.path == "" {
+		return nil
+	}
+
+	content, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	content = append(content, '\n')
+
+	tempFile, err := os.CreateTemp(filepath.Dir(c.path), filepath.Base(c.path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tempFile.Name()) }()
+
+	if _, err := tempFile.Write(content); err != nil {
+		_ = tempFile.Close()
+		return err
+	}
+	if err := tempFile.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tempFile.Name(), c.path)
+}
@@ -0,0 +1,85 @@
+package updater
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadCheckpoint_MissingFile(t *testing.T) {
+	checkpoint, err := LoadCheckpoint(filepath.Join(t.TempDir(), "missing.json"))
+	if err != nil {
+		t.Fatalf("LoadCheckpoint failed: %v", err)
+	}
+	if len(checkpoint.Items) != 0 {
+		t.Errorf("expected empty checkpoint, got %d items", len(checkpoint.Items))
+	}
+}
+
+func TestCheckpoint_RecordAndLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	checkpoint := NewCheckpoint(path)
+
+	if err := checkpoint.Record("metallica", CheckpointEntry{Status: StatusProcessed, Reason: "updated", PromptVersion: "band-v2"}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := checkpoint.Record("slayer", CheckpointEntry{Status: StatusFailed, Reason: "timeout"}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+	if err := checkpoint.Record("megadeth", CheckpointEntry{Status: StatusSkipped, Reason: "already complete"}); err != nil {
+		t.Fatalf("Record failed: %v", err)
+	}
+
+	loaded, err := LoadCheckpoint(path)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint failed: %v", err)
+	}
+	if len(loaded.Items) != 3 {
+		t.Fatalf("expected 3 items, got %d", len(loaded.Items))
+	}
+	if entry := loaded.Items["slayer"]; entry.Status != StatusFailed || entry.Reason != "timeout" {
+		t.Errorf("unexpected entry for slayer: %+v", entry)
+	}
+	if entry := loaded.Items["metallica"]; entry.PromptVersion != "band-v2" || entry.UpdatedAt.IsZero() {
+		t.Errorf("unexpected entry for metallica: %+v", entry)
+	}
+	if failed := loaded.Keys(StatusFailed); len(failed) != 1 || failed[0] != "slayer" {
+		t.Errorf("expected [slayer] failed keys, got %v", failed)
+	}
+
+	// No temporary files should be left behind
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 1 {
+		t.Errorf("expected only the checkpoint file, found %d entries", len(entries))
+	}
+}
+
+func TestCheckpoint_ShouldProcess(t *testing.T) {
+	checkpoint := NewCheckpoint("")
+	checkpoint.Items["done"] = CheckpointEntry{Status: StatusProcessed}
+	checkpoint.Items["broken"] = CheckpointEntry{Status: StatusFailed}
+	checkpoint.Items["complete"] = CheckpointEntry{Status: StatusSkipped}
+
+	tests := []struct {
+		name     string
+		key      string
+		mode     ResumeMode
+		expected bool
+	}{
+		{name: "Fresh run processes recorded items", key: "done", mode: ModeFresh, expected: true},
+		{name: "Resume skips processed items", key: "done", mode: ModeResume, expected: false},
+		{name: "Resume skips failed items", key: "broken", mode: ModeResume, expected: false},
+		{name: "Resume processes new items", key: "new", mode: ModeResume, expected: true},
+		{name: "Retry failed processes failed items", key: "broken", mode: ModeRetryFailed, expected: true},
+		{name: "Retry failed skips skipped items", key: "complete", mode: ModeRetryFailed, expected: false},
+		{name: "Retry failed skips new items", key: "new", mode: ModeRetryFailed, expected: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := checkpoint.ShouldProcess(tt.key, tt.mode); result != tt.expected {
+				t.Errorf("ShouldProcess(%q) = %v, want %v", tt.key, result, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,539 @@
+package discovery
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/data"
+	"github.com/neovasili/metal-fests/internal/model"
+	"github.com/neovasili/metal-fests/internal/openai"
+	"github.com/neovasili/metal-fests/internal/updater"
+	"github.com/neovasili/metal-fests/internal/urlcheck"
+)
+
+// DiscoveredFestival is the structured output requested from the model.
+// Its JSON schema is generated from this type, see festivalDiscoverySchema.
+type DiscoveredFestival struct {
+	Name        string       `json:"name" description:"Official festival name without the year"`
+	Dates       EditionDate  `json:"dates" description:"Dates of the edition"`
+	Location    string       `json:"location" description:"Town and country of the venue"`
+	Coordinates Coordinates  `json:"coordinates" description:"Location of the venue"`
+	Website     string       `json:"website" description:"Official festival website"`
+	Poster      string       `json:"poster" description:"Absolute URL of the edition poster, or an empty string when there is none"`
+	TicketPrice *float64     `json:"ticketPrice" description:"Full festival ticket price without currency, or null when unknown"`
+	Bands       []LineupBand `json:"bands" description:"Every band announced on the lineup"`
+
+	// PromptVersion identifies the prompt that produced this result
+	PromptVersion string `json:"-"`
+	// Model and Sources describe where the answer came from
+	Model   string           `json:"-"`
+	Sources []model.Citation `json:"-"`
+}
+
+// provenance describes the origin of every field of the discovered festival
+func (d *DiscoveredFestival) provenance() model.Provenance {
+	return model.Provenance{
+		Origin:        model.ProvenanceAI,
+		Sources:       d.Sources,
+		Model:         d.Model,
+		PromptVersion: d.PromptVersion,
+		UpdatedAt:     time.Now().UTC(),
+	}
+}
+
+// EditionDate holds the first and last day of a festival edition
+type EditionDate struct {
+	Start string `json:"start" description:"First day as YYYY-MM-DD"`
+	End   string `json:"end" description:"Last day as YYYY-MM-DD"`
+}
+
+// Coordinates locate the festival venue on the map
+type Coordinates struct {
+	Lat float64 `json:"lat" description:"Latitude of the venue"`
+	Lng float64 `json:"lng" description:"Longitude of the venue"`
+}
+
+// LineupBand is a band as it appears on the festival lineup
+type LineupBand struct {
+	Name string `json:"name" description:"Band name as shown on the lineup"`
+	Size int    `json:"size" jsonschema:"enum=1|2|3" description:"Visual tier of the band name, 1 being the smallest text"`
+}
+
+// festivalDiscoverySchema is the JSON schema sent along with the discovery request
+var festivalDiscoverySchema = openai.GenerateSchema(DiscoveredFestival{})
+
+// DiscoveryStats describes the outcome of a discovery run for the summary
+type DiscoveryStats struct {
+	Request        string
+	EditionYear    int
+	Festival       *model.Festival
+	Existing       string
+	Confidence     float64
+	Reasons        []string
+	LinkedBands    []string
+	AmbiguousBands []string
+	Queued         bool
+	DryRun         bool
+	Failure        string
+	TotalTokens    int
+	TotalCost      float64
+	UsedModel      string
+	PromptVersion  string
+	Duration       time.Duration
+}
+
+var openaiClient *openai.OpenAIClient
+
+func searchFestival(ctx context.Context, prompt *openai.Prompt, festivalName, website string, year int, dryRun bool) (*DiscoveredFestival, int, float64, string, error) {
+	usedTokens := 0
+	estimatedCost := 0.0
+	usedModel := ""
+
+	userPrompt, err := prompt.Render(map[string]any{
+		"FestivalName": festivalName,
+		"FestivalURL":  website,
+		"EditionYear":  year,
+	})
+	if err != nil {
+		return nil, usedTokens, estimatedCost, usedModel, err
+	}
+
+	primaryModel, _ := openaiClient.Models()
+	resp, err := openaiClient.AskOpenAI(ctx, userPrompt, festivalDiscoverySchema, primaryModel, dryRun)
+	if err != nil {
+		return nil, usedTokens, estimatedCost, usedModel, err
+	}
+	if resp == nil {
+		if dryRun {
+			return nil, usedTokens, estimatedCost, usedModel, nil
+		}
+		return nil, usedTokens, estimatedCost, usedModel, fmt.Errorf("no response from OpenAI")
+	}
+	usedTokens = resp.TotalUsedTokens
+	estimatedCost = resp.EstimatedCost
+	usedModel = string(resp.UsedModel)
+	if len(resp.OutputText) == 0 {
+		return nil, usedTokens, estimatedCost, usedModel, fmt.Errorf("empty response from OpenAI")
+	}
+
+	var result DiscoveredFestival
+	if err := json.Unmarshal([]byte(strings.TrimSpace(resp.OutputText)), &result); err != nil {
+		return nil, usedTokens, estimatedCost, usedModel, fmt.Errorf("failed to parse OpenAI response: %w", err)
+	}
+	result.PromptVersion = prompt.Version
+	result.Model = usedModel
+	result.Sources = resp.Citations
+
+	return &result, usedTokens, estimatedCost, usedModel, nil
+}
+
+// findExistingFestival returns the stored festival the discovered one most
+// likely is, matching on the key and on the name
+func findExistingFestival(festivals []model.Festival, festival model.Festival) *model.Festival {
+	for i := range festivals {
+		if festivals[i].Key == festival.Key ||
+			data.NameSimilarity(stripYear(festivals[i].Name), festival.Name) >= data.DefaultLinkThreshold {
+			return &festivals[i]
+		}
+	}
+	return nil
+}
+
+// stripYear drops a trailing edition year, e.g. "Hellfest 2026"
+func stripYear(name string) string {
+	fields := strings.Fields(name)
+	if len(fields) > 1 {
+		if _, err := time.Parse("2006", fields[len(fields)-1]); err == nil {
+			fields = fields[:len(fields)-1]
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// buildFestival turns the discovered data into a festival record, linking the
+// lineup to the known bands. Lineup names that could be several bands are
+// left out and listed in the stats for a person to add.
+func buildFestival(discovered *DiscoveredFestival, resolver *data.BandResolver, stats *DiscoveryStats) model.Festival {
+	name := strings.TrimSpace(discovered.Name)
+	festival := model.Festival{
+		Key:  data.GenerateBandKey(name),
+		Name: name,
+		Dates: model.Dates{
+			Start: discovered.Dates.Start,
+			End:   discovered.Dates.End,
+		},
+		Location:    strings.TrimSpace(discovered.Location),
+		Coordinates: model.Coordinates{Lat: discovered.Coordinates.Lat, Lng: discovered.Coordinates.Lng},
+		Poster:      strings.TrimSpace(discovered.Poster),
+		Website:     strings.TrimSpace(discovered.Website),
+		Bands:       make([]model.BandRef, 0, len(discovered.Bands)),
+	}
+	if festival.Dates.End == "" {
+		festival.Dates.End = festival.Dates.Start
+	}
+
+	seen := make(map[string]bool, len(discovered.Bands))
+	for _, lineupBand := range discovered.Bands {
+		normalizedBandName := data.NormalizeBandName(lineupBand.Name)
+		band := model.BandRef{
+			Key:  data.GenerateBandKey(normalizedBandName),
+			Name: normalizedBandName,
+			Size: lineupBand.Size,
+		}
+
+		match, found, candidates := resolver.Resolve(normalizedBandName)
+		switch {
+		case found:
+			if match.Key != band.Key || match.Name != band.Name {
+				stats.LinkedBands = append(stats.LinkedBands, fmt.Sprintf("%s → %s (%.2f)", lineupBand.Name, match.Name, match.Score))
+			}
+			band.Key = match.Key
+			band.Name = match.Name
+		case len(candidates) > 0:
+			names := make([]string, 0, len(candidates))
+			for _, candidate := range candidates {
+				names = append(names, fmt.Sprintf("%s (%.2f)", candidate.Name, candidate.Score))
+			}
+			stats.AmbiguousBands = append(stats.AmbiguousBands, fmt.Sprintf("%s: %s", lineupBand.Name, strings.Join(names, ", ")))
+			continue
+		}
+
+		if seen[band.Key] {
+			continue
+		}
+		seen[band.Key] = true
+		festival.Bands = append(festival.Bands, band)
+	}
+
+	if discovered.TicketPrice != nil && *discovered.TicketPrice > 0 {
+		festival.RecordPrice(*discovered.TicketPrice, time.Now().UTC())
+	}
+
+	festival.Provenance = festival.Provenance.Set(discovered.provenance(), model.ChangedFields(model.Festival{}, festival)...)
+	return festival
+}
+
+// reviewFestival scores a discovered festival: it has to pass the shared
+// validation rules, its website and poster have to resolve and it should
+// come with a lineup. Every failed check lowers the confidence.
+func reviewFestival(ctx context.Context, festival model.Festival, checker *urlcheck.Checker) (float64, []string) {
+	var reasons []string
+	checks, passed := 0, 0
+
+	checks++
+	if problems := data.ValidateFestival(festival); len(problems) > 0 {
+		for _, problem := range problems {
+			reasons = append(reasons, problem.Error())
+		}
+	} else {
+		passed++
+	}
+
+	if checker != nil {
+		urls := []struct{ field, url string }{
+			{"website", festival.Website},
+			{"poster", festival.Poster},
+		}
+		for _, u := range urls {
+			if u.url == "" {
+				continue
+			}
+			checks++
+			check := checker.Check(ctx, u.url)
+			switch {
+			case check.Valid:
+				passed++
+			case check.Status != 0:
+				reasons = append(reasons, fmt.Sprintf("%s does not resolve (status %d)", u.field, check.Status))
+			default:
+				reasons = append(reasons, fmt.Sprintf("%s does not resolve (%s)", u.field, check.Error))
+			}
+		}
+	}
+
+	checks++
+	if len(festival.Bands) > 0 {
+		passed++
+	} else {
+		reasons = append(reasons, "no lineup announced")
+	}
+
+	return float64(passed) / float64(checks), reasons
+}
+
+// discoverFestival looks up a festival and queues it for review unless it
+// is already in the database
+func discoverFestival(ctx context.Context, prompt *openai.Prompt, festivalName, website string, year int, linkThreshold float64, checker *urlcheck.Checker, dryRun bool, openaiResponseFilePath string) *DiscoveryStats {
+	request := strings.TrimSpace(strings.Join([]string{festivalName, website}, " "))
+	stats := &DiscoveryStats{Request: request, EditionYear: year, DryRun: dryRun, PromptVersion: prompt.Version}
+	started := time.Now()
+	defer func() { stats.Duration = time.Since(started) }()
+
+	var discovered *DiscoveredFestival
+	if openaiResponseFilePath != "" {
+		// Load OpenAI response from file for testing
+		// #nosec G304 -- This is a command-line script where the file path is provided by the user
+		content, err := os.ReadFile(openaiResponseFilePath)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to read OpenAI response file", "error", err)
+			stats.Failure = err.Error()
+			return stats
+		}
+		discovered = &DiscoveredFestival{PromptVersion: prompt.Version}
+		if err := json.Unmarshal(content, discovered); err != nil {
+			slog.ErrorContext(ctx, "failed to parse OpenAI response file", "error", err)
+			stats.Failure = err.Error()
+			return stats
+		}
+		slog.InfoContext(ctx, "OpenAI response loaded from file", "path", openaiResponseFilePath)
+	} else {
+		var err error
+		discovered, stats.TotalTokens, stats.TotalCost, stats.UsedModel, err = searchFestival(ctx, prompt, festivalName, website, year, dryRun)
+		if err != nil {
+			slog.ErrorContext(ctx, "festival search failed", "error", err)
+			stats.Failure = err.Error()
+			return stats
+		}
+		if discovered == nil {
+			slog.InfoContext(ctx, "festival discovery skipped", "reason", "dry run")
+			return stats
+		}
+	}
+
+	if strings.TrimSpace(discovered.Name) == "" {
+		stats.Failure = "no festival found"
+		slog.WarnContext(ctx, "no festival found")
+		return stats
+	}
+
+	bands, err := data.GetBands(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to read bands", "error", err)
+	}
+	festivals, err := data.GetFestivals(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to read festivals", "error", err)
+	}
+	refs := make([]model.BandRef, 0)
+	for _, festival := range festivals {
+		refs = append(refs, festival.Bands...)
+	}
+
+	festival := buildFestival(discovered, data.NewBandResolver(bands, refs, linkThreshold), stats)
+	stats.Festival = &festival
+
+	if existing := findExistingFestival(festivals, festival); existing != nil {
+		stats.Existing = existing.Key
+		slog.InfoContext(ctx, "festival already in the database", "name", festival.Name, "existing", existing.Key)
+		return stats
+	}
+
+	stats.Confidence, stats.Reasons = reviewFestival(ctx, festival, checker)
+	slog.InfoContext(ctx, "festival found", "name", festival.Name, "start", festival.Dates.Start, "end", festival.Dates.End,
+		"location", festival.Location, "bands", len(festival.Bands), "confidence", stats.Confidence, "reasons", stats.Reasons)
+
+	if dryRun {
+		slog.InfoContext(ctx, "festival not sent to the review queue", "reason", "dry run")
+		return stats
+	}
+
+	review := model.PendingReview{
+		Kind:       model.ReviewFestival,
+		Key:        festival.Key,
+		Festival:   &festival,
+		Confidence: stats.Confidence,
+		Reasons:    stats.Reasons,
+	}
+	if err := data.AddPendingReview(ctx, review); err != nil {
+		slog.ErrorContext(ctx, "failed to add festival to the review queue", "festival", festival.Key, "error", err)
+		stats.Failure = err.Error()
+		return stats
+	}
+	stats.Queued = true
+	slog.InfoContext(ctx, "festival sent to the review queue", "key", festival.Key)
+	return stats
+}
+
+// summaryTemplate renders the pull request summary from the run report
+//
+//go:embed summary.md.tmpl
+var summaryTemplate string
+
+// buildReport turns the outcome of a discovery into its report, with the
+// proposed festival as the record of its only item
+func buildReport(stats *DiscoveryStats, startedAt time.Time) *updater.Report {
+	report := updater.NewReport("festival_discovery", startedAt)
+	report.Parameters = map[string]string{
+		"request": stats.Request,
+		"year":    fmt.Sprintf("%d", stats.EditionYear),
+	}
+	report.DryRun = stats.DryRun
+	report.PromptVersion = stats.PromptVersion
+	report.Model = stats.UsedModel
+	report.Tokens = stats.TotalTokens
+	report.Cost = stats.TotalCost
+	report.Total = 1
+	report.Processed = 1
+
+	item := updater.ItemReport{
+		Name:       stats.Request,
+		Status:     updater.StatusSkipped,
+		Confidence: stats.Confidence,
+		Notes:      stats.Reasons,
+		Model:      stats.UsedModel,
+		Tokens:     stats.TotalTokens,
+		Cost:       stats.TotalCost,
+		DurationMs: stats.Duration.Milliseconds(),
+	}
+	if stats.Festival != nil {
+		item.Key = stats.Festival.Key
+		item.Name = stats.Festival.Name
+		item.Record = stats.Festival
+	}
+	for _, band := range stats.LinkedBands {
+		item.Changes = append(item.Changes, updater.Change{Kind: "linkedBand", New: band})
+	}
+	for _, band := range stats.AmbiguousBands {
+		item.Changes = append(item.Changes, updater.Change{Kind: "ambiguousBand", New: band})
+	}
+	switch {
+	case stats.Failure != "":
+		item.Status = updater.StatusFailed
+		item.Outcome = "failed"
+		item.Error = stats.Failure
+	case stats.Festival == nil:
+		item.Outcome = "skipped"
+		item.Reason = "dry-run mode, nothing was looked up"
+	case stats.Existing != "":
+		item.Key = stats.Existing
+		item.Outcome = "existing"
+		item.Reason = "already in the database"
+	case stats.Queued:
+		item.Status = updater.StatusProcessed
+		item.Outcome = "queued"
+	default:
+		item.Outcome = "dryRun"
+		item.Reason = "dry-run mode, not queued"
+	}
+	report.Items = []updater.ItemReport{item}
+	report.Counts = map[string]int{"queued": 0, "existing": 0, "dryRun": 0, "skipped": 0, "failed": 0}
+	report.Counts[item.Outcome]++
+	return report
+}
+
+func generateSummary(report *updater.Report) (string, error) {
+	return report.Render(summaryTemplate)
+}
+
+// Options configures a festival discovery run
+type Options struct {
+	DryRun         bool
+	Festival       string
+	Website        string
+	OpenAIResponse string
+	Year           int
+	LinkThreshold  float64
+	CheckURLs      bool
+	RequestTimeout time.Duration
+	Model          string
+	FallbackModel  string
+	Report         string
+	Summary        string
+	Prompt         string
+}
+
+// RegisterFlags defines the command line flags of a festival discovery run
+func (o *Options) RegisterFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&o.DryRun, "dry-run", false, "Enable dry run mode")
+	fs.StringVar(&o.Festival, "festival", "", "Name of the festival to discover")
+	fs.StringVar(&o.Website, "website", "", "Website of the festival to discover")
+	fs.StringVar(&o.OpenAIResponse, "openai-response", "", "Specify OpenAI response file path for testing")
+	fs.IntVar(&o.Year, "year", time.Now().Year(), "Festival edition year to look up")
+	fs.Float64Var(&o.LinkThreshold, "link-threshold", data.DefaultLinkThreshold, "Name similarity above which lineup names are linked to existing bands")
+	fs.BoolVar(&o.CheckURLs, "check-urls", true, "Check that the website and poster resolve")
+	fs.DurationVar(&o.RequestTimeout, "request-timeout", openai.DefaultRequestTimeout, "Maximum duration of the OpenAI request")
+	fs.StringVar(&o.Model, "model", openai.PrimaryModel, "OpenAI model used to look up the festival")
+	fs.StringVar(&o.FallbackModel, "fallback-model", openai.FallbackModel, "OpenAI model used when the primary one is rate limited or finds nothing")
+	fs.StringVar(&o.Report, "report", "festival_discovery_report.json", "JSON report of the run")
+	fs.StringVar(&o.Summary, "summary", "festival_discovery_summary.md", "Markdown summary of the run")
+	fs.StringVar(&o.Prompt, "prompt", "scripts/festival_discovery_prompt.md", "Prompt template used to look up the festival")
+}
+
+// Run looks up a festival and adds it to the review queue unless it is
+// already in the database. It returns an error when the run cannot start,
+// or when the discovery fails; the report and the summary are written anyway.
+func Run(ctx context.Context, opts Options) error {
+	startedAt := time.Now()
+
+	if opts.Festival == "" && opts.Website == "" {
+		return errors.New("--festival or --website is required")
+	}
+
+	if opts.DryRun {
+		slog.InfoContext(ctx, "dry-run mode, no API calls are made and no files are modified")
+	}
+
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" && !opts.DryRun && opts.OpenAIResponse == "" {
+		return errors.New("OPENAI_API_KEY environment variable not set")
+	}
+
+	openaiClient = openai.NewOpenAIClient(apiKey)
+	openaiClient.SetRequestTimeout(opts.RequestTimeout)
+	openaiClient.SetModels(opts.Model, opts.FallbackModel)
+
+	// Load prompt template
+	prompt, err := openai.LoadPrompt(opts.Prompt)
+	if err != nil {
+		return fmt.Errorf("loading prompt template: %w", err)
+	}
+
+	// Stop on SIGINT/SIGTERM
+	_, abort, release := updater.NotifyShutdown(ctx)
+	defer release()
+
+	var checker *urlcheck.Checker
+	if opts.CheckURLs {
+		checker = urlcheck.NewChecker(urlcheck.Options{})
+	}
+
+	target := strings.TrimSpace(opts.Festival + " " + opts.Website)
+	itemCtx, span := updater.StartItem(updater.ItemContext(abort, nil, "discovery", target), "discovery", target)
+	slog.InfoContext(itemCtx, "discovering festival", "edition", opts.Year)
+	stats := discoverFestival(itemCtx, prompt, opts.Festival, opts.Website, opts.Year, opts.LinkThreshold, checker, opts.DryRun, opts.OpenAIResponse)
+	status := updater.StatusProcessed
+	if stats.Failure != "" {
+		status = updater.StatusFailed
+	}
+	updater.EndItem(span, status, stats.Failure)
+
+	// Generate the report and the summary rendered from it
+	report := buildReport(stats, startedAt)
+	report.Finish(time.Now())
+	if err := report.WriteJSON(opts.Report); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	summary, err := generateSummary(report)
+	if err != nil {
+		return fmt.Errorf("generating summary: %w", err)
+	}
+	if err := os.WriteFile(opts.Summary, []byte(summary), 0600); err != nil {
+		return fmt.Errorf("writing summary: %w", err)
+	}
+
+	if stats.Failure != "" {
+		slog.ErrorContext(ctx, "festival discovery failed", "error", stats.Failure, "summary", opts.Summary, "report", opts.Report)
+		return fmt.Errorf("festival discovery failed: %s", stats.Failure)
+	}
+
+	slog.InfoContext(ctx, "festival discovery completed", "summary", opts.Summary, "report", opts.Report)
+	return nil
+}
@@ -0,0 +1,361 @@
+package discovery
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/data"
+	"github.com/neovasili/metal-fests/internal/model"
+	"github.com/neovasili/metal-fests/internal/openai"
+	"github.com/neovasili/metal-fests/internal/urlcheck"
+)
+
+const testDatabase = `{
+  "bands": [{"key": "motley-crue", "name": "Mötley Crüe", "country": "", "description": "", "logo": "", "headlineImage": "", "website": "", "spotify": "", "genres": [], "members": [], "reviewed": true}],
+  "festivals": [{"key": "hellfest", "name": "Hellfest 2026", "dates": {"start": "2026-06-18", "end": "2026-06-21"}, "location": "Clisson, France", "coordinates": {"lat": 47.0988, "lng": -1.2641}, "poster": "", "website": "https://hellfest.fr", "bands": [{"key": "sepultura", "name": "Sepultura", "size": 3}], "ticketPrice": 329}]
+}`
+
+const testResponse = `{"name":"Test Fest","dates":{"start":"2026-07-02","end":"2026-07-04"},"location":"Nantes, France","coordinates":{"lat":47.2184,"lng":-1.5536},"website":"https://testfest.com","poster":"https://testfest.com/poster.jpg","ticketPrice":120,"bands":[{"name":"MOTLEY CRUE","size":3},{"name":"Sepulturra","size":2},{"name":"Gojira","size":3}]}`
+
+// setupTestDatabase points the data layer to a copy of testDatabase
+func setupTestDatabase(t *testing.T) string {
+	t.Helper()
+	tempDir := t.TempDir()
+	dbFile := filepath.Join(tempDir, "db.json")
+	if err := os.WriteFile(dbFile, []byte(testDatabase), 0600); err != nil {
+		t.Fatalf("failed to create test db: %v", err)
+	}
+	originalDBFile := data.SetDBFilePathForTesting(dbFile)
+	t.Cleanup(func() { data.SetDBFilePathForTesting(originalDBFile) })
+	return tempDir
+}
+
+func testPrompt() *openai.Prompt {
+	return &openai.Prompt{Version: "festival-discovery-v1"}
+}
+
+func TestFestivalDiscoverySchema(t *testing.T) {
+	required := festivalDiscoverySchema["required"].([]string)
+	expected := []string{"name", "dates", "location", "coordinates", "website", "poster", "ticketPrice", "bands"}
+	if !reflect.DeepEqual(required, expected) {
+		t.Errorf("unexpected required properties: %v", required)
+	}
+
+	properties := festivalDiscoverySchema["properties"].(map[string]any)
+	coordinates := properties["coordinates"].(map[string]any)
+	if coordinates["type"] != "object" {
+		t.Errorf("coordinates should be an object, got %v", coordinates["type"])
+	}
+	if _, exists := properties["key"]; exists {
+		t.Errorf("the model should not be asked for the festival key")
+	}
+}
+
+func TestBuildFestival(t *testing.T) {
+	resolver := data.NewBandResolver(
+		[]model.Band{{Key: "sodom", Name: "Sodom"}, {Key: "sodoma", Name: "Sodoma"}},
+		[]model.BandRef{{Key: "sepultura", Name: "Sepultura"}},
+		data.DefaultLinkThreshold,
+	)
+	price := 120.0
+	discovered := &DiscoveredFestival{
+		Name:          " Test Fest ",
+		Dates:         EditionDate{Start: "2026-07-02"},
+		Location:      "Nantes, France",
+		Coordinates:   Coordinates{Lat: 47.2184, Lng: -1.5536},
+		Website:       "https://testfest.com",
+		TicketPrice:   &price,
+		Bands:         []LineupBand{{Name: "Sepulturra", Size: 2}, {Name: "Sodomy", Size: 1}, {Name: "gojira", Size: 3}, {Name: "Sepultura", Size: 2}},
+		Model:         "gpt-5",
+		PromptVersion: "festival-discovery-v1",
+	}
+
+	stats := &DiscoveryStats{}
+	festival := buildFestival(discovered, resolver, stats)
+
+	if festival.Key != "test-fest" || festival.Name != "Test Fest" {
+		t.Errorf("buildFestival() key, name = %q, %q, want test-fest, Test Fest", festival.Key, festival.Name)
+	}
+	if festival.Dates != (model.Dates{Start: "2026-07-02", End: "2026-07-02"}) {
+		t.Errorf("buildFestival() dates = %+v, want a one day festival", festival.Dates)
+	}
+	expectedBands := []model.BandRef{
+		{Key: "sepultura", Name: "Sepultura", Size: 2},
+		{Key: "gojira", Name: "Gojira", Size: 3},
+	}
+	if !reflect.DeepEqual(festival.Bands, expectedBands) {
+		t.Errorf("buildFestival() bands = %+v, want %+v", festival.Bands, expectedBands)
+	}
+	if len(stats.LinkedBands) != 1 || len(stats.AmbiguousBands) != 1 {
+		t.Errorf("buildFestival() linked %v, ambiguous %v, want one of each", stats.LinkedBands, stats.AmbiguousBands)
+	}
+	if festival.TicketPrice != 120 || len(festival.PriceHistory) != 1 {
+		t.Errorf("buildFestival() price = %v, history %+v, want 120 recorded once", festival.TicketPrice, festival.PriceHistory)
+	}
+	for _, field := range []string{"name", "dates", "location", "coordinates", "website", "bands", "ticketPrice"} {
+		if festival.Provenance[field].Origin != model.ProvenanceAI || festival.Provenance[field].Model != "gpt-5" {
+			t.Errorf("%s provenance = %+v, want ai from gpt-5", field, festival.Provenance[field])
+		}
+	}
+	if _, exists := festival.Provenance["poster"]; exists {
+		t.Errorf("poster provenance = %+v, want none for an empty poster", festival.Provenance["poster"])
+	}
+}
+
+func TestFindExistingFestival(t *testing.T) {
+	festivals := []model.Festival{
+		{Key: "hellfest", Name: "Hellfest 2026"},
+		{Key: "wacken-open-air", Name: "Wacken Open Air"},
+	}
+
+	tests := []struct {
+		name     string
+		festival model.Festival
+		expected string
+	}{
+		{name: "Same key", festival: model.Festival{Key: "wacken-open-air", Name: "W:O:A"}, expected: "wacken-open-air"},
+		{name: "Same name without the year", festival: model.Festival{Key: "hellfest-open-air", Name: "Hellfest"}, expected: "hellfest"},
+		{name: "New festival", festival: model.Festival{Key: "test-fest", Name: "Test Fest"}, expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := findExistingFestival(festivals, tt.festival)
+			key := ""
+			if existing != nil {
+				key = existing.Key
+			}
+			if key != tt.expected {
+				t.Errorf("findExistingFestival() = %q, want %q", key, tt.expected)
+			}
+		})
+	}
+}
+
+func TestStripYear(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{name: "Hellfest 2026", expected: "Hellfest"},
+		{name: "Hellfest", expected: "Hellfest"},
+		{name: "1914", expected: "1914"},
+		{name: "Festival Open Air", expected: "Festival Open Air"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := stripYear(tt.name); result != tt.expected {
+				t.Errorf("stripYear(%q) = %q, want %q", tt.name, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReviewFestival(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/missing.jpg" {
+			http.NotFound(w, r)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	checker := urlcheck.NewChecker(urlcheck.Options{AllowLoopback: true})
+
+	valid := model.Festival{
+		Key:         "test-fest",
+		Name:        "Test Fest",
+		Dates:       model.Dates{Start: "2026-07-02", End: "2026-07-04"},
+		Location:    "Nantes, France",
+		Coordinates: model.Coordinates{Lat: 47.2184, Lng: -1.5536},
+		Website:     server.URL,
+		Poster:      server.URL + "/poster.jpg",
+		Bands:       []model.BandRef{{Key: "gojira", Name: "Gojira", Size: 3}},
+	}
+
+	tests := []struct {
+		name       string
+		modify     func(festival *model.Festival)
+		checker    *urlcheck.Checker
+		confidence float64
+		reasons    int
+	}{
+		{name: "Everything checks out", modify: func(*model.Festival) {}, checker: checker, confidence: 1},
+		{
+			name:       "Poster does not resolve",
+			modify:     func(festival *model.Festival) { festival.Poster = server.URL + "/missing.jpg" },
+			checker:    checker,
+			confidence: 0.75,
+			reasons:    1,
+		},
+		{
+			name: "Invalid and without lineup",
+			modify: func(festival *model.Festival) {
+				festival.Coordinates = model.Coordinates{}
+				festival.Bands = nil
+			},
+			confidence: 0,
+			reasons:    2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			festival := valid
+			tt.modify(&festival)
+			confidence, reasons := reviewFestival(context.Background(), festival, tt.checker)
+			if confidence != tt.confidence || len(reasons) != tt.reasons {
+				t.Errorf("reviewFestival() = %.2f, %v; want %.2f with %d reasons", confidence, reasons, tt.confidence, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestDiscoverFestival_QueuesNewFestival(t *testing.T) {
+	tempDir := setupTestDatabase(t)
+	responseFile := filepath.Join(tempDir, "response.json")
+	if err := os.WriteFile(responseFile, []byte(testResponse), 0600); err != nil {
+		t.Fatalf("failed to write response file: %v", err)
+	}
+
+	stats := discoverFestival(context.Background(), testPrompt(), "Test Fest", "", 2026, data.DefaultLinkThreshold, nil, false, responseFile)
+	if stats.Failure != "" || !stats.Queued {
+		t.Fatalf("discoverFestival() = %+v, want the festival queued", stats)
+	}
+
+	reviews, err := data.GetPendingReviews(context.Background())
+	if err != nil {
+		t.Fatalf("GetPendingReviews failed: %v", err)
+	}
+	if len(reviews) != 1 || reviews[0].Kind != model.ReviewFestival || reviews[0].Key != "test-fest" || reviews[0].Festival == nil {
+		t.Fatalf("expected the festival in the review queue, got %+v", reviews)
+	}
+	bands := reviews[0].Festival.Bands
+	if len(bands) != 3 || bands[0].Key != "motley-crue" || bands[1].Key != "sepultura" {
+		t.Errorf("expected the lineup linked to the known bands, got %+v", bands)
+	}
+
+	// The database itself is left untouched
+	festivals, err := data.GetFestivals(context.Background())
+	if err != nil {
+		t.Fatalf("GetFestivals failed: %v", err)
+	}
+	if len(festivals) != 1 {
+		t.Errorf("expected the database to keep a single festival, got %d", len(festivals))
+	}
+}
+
+func TestDiscoverFestival_SkipsExistingFestival(t *testing.T) {
+	tempDir := setupTestDatabase(t)
+	responseFile := filepath.Join(tempDir, "response.json")
+	response := strings.Replace(testResponse, `"name":"Test Fest"`, `"name":"Hellfest"`, 1)
+	if err := os.WriteFile(responseFile, []byte(response), 0600); err != nil {
+		t.Fatalf("failed to write response file: %v", err)
+	}
+
+	stats := discoverFestival(context.Background(), testPrompt(), "Hellfest", "", 2026, data.DefaultLinkThreshold, nil, false, responseFile)
+	if stats.Existing != "hellfest" || stats.Queued {
+		t.Errorf("discoverFestival() = %+v, want the existing festival found", stats)
+	}
+	reviews, err := data.GetPendingReviews(context.Background())
+	if err != nil {
+		t.Fatalf("GetPendingReviews failed: %v", err)
+	}
+	if len(reviews) != 0 {
+		t.Errorf("expected an empty review queue, got %+v", reviews)
+	}
+}
+
+func TestDiscoverFestival_DryRunDoesNotQueue(t *testing.T) {
+	tempDir := setupTestDatabase(t)
+	responseFile := filepath.Join(tempDir, "response.json")
+	if err := os.WriteFile(responseFile, []byte(testResponse), 0600); err != nil {
+		t.Fatalf("failed to write response file: %v", err)
+	}
+
+	stats := discoverFestival(context.Background(), testPrompt(), "Test Fest", "", 2026, data.DefaultLinkThreshold, nil, true, responseFile)
+	if stats.Queued || stats.Festival == nil {
+		t.Errorf("discoverFestival() = %+v, want the festival found but not queued", stats)
+	}
+	if _, err := os.Stat(filepath.Join(tempDir, "pending_review.json")); !os.IsNotExist(err) {
+		t.Errorf("expected no review queue file in dry-run mode, got %v", err)
+	}
+}
+
+func TestGenerateSummary(t *testing.T) {
+	festival := &model.Festival{
+		Key:         "test-fest",
+		Name:        "Test Fest",
+		Dates:       model.Dates{Start: "2026-07-02", End: "2026-07-04"},
+		Location:    "Nantes, France",
+		Coordinates: model.Coordinates{Lat: 47.2184, Lng: -1.5536},
+		Website:     "https://testfest.com",
+		Bands:       []model.BandRef{{Key: "gojira", Name: "Gojira", Size: 3}},
+	}
+
+	tests := []struct {
+		name     string
+		stats    DiscoveryStats
+		contains []string
+	}{
+		{
+			name: "Festival queued",
+			stats: DiscoveryStats{
+				Request:        "Test Fest",
+				EditionYear:    2026,
+				Festival:       festival,
+				Queued:         true,
+				Confidence:     0.75,
+				Reasons:        []string{"poster: must be an http or https URL"},
+				AmbiguousBands: []string{"Sodomy: Sodom (0.83), Sodoma (0.83)"},
+				PromptVersion:  "festival-discovery-v1",
+			},
+			contains: []string{
+				"new festival found for **Test Fest** (2026 edition)",
+				"- **Added to the review queue** as `test-fest`",
+				"- **Confidence**: 0.75",
+				"| Coordinates | 47.2184, -1.5536 |",
+				"## ⚠️ Needs Attention\n\n- poster: must be an http or https URL",
+				"## ❓ Ambiguous Lineup Names",
+				"- Sodomy: Sodom (0.83), Sodoma (0.83)",
+				"- **Prompt Version**: festival-discovery-v1",
+				"waiting in `pending_review.json`",
+			},
+		},
+		{
+			name:  "Festival already known",
+			stats: DiscoveryStats{Request: "Hellfest", EditionYear: 2026, Festival: festival, Existing: "hellfest"},
+			contains: []string{
+				"- **Already in the database** as `hellfest`, nothing was added",
+				"*No festival was added to the review queue.*",
+			},
+		},
+		{
+			name:     "Discovery failed",
+			stats:    DiscoveryStats{Request: "Nope Fest", EditionYear: 2026, Failure: "no festival found"},
+			contains: []string{"- **Failed**: no festival found"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := generateSummary(buildReport(&tt.stats, time.Now()))
+			if err != nil {
+				t.Fatalf("generateSummary() failed: %v", err)
+			}
+			for _, substr := range tt.contains {
+				if !strings.Contains(summary, substr) {
+					t.Errorf("generateSummary() missing expected substring: %q", substr)
+				}
+			}
+		})
+	}
+}
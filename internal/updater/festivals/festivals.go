@@ -0,0 +1,951 @@
+package festivals
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/neovasili/metal-fests/internal/data"
+	"github.com/neovasili/metal-fests/internal/logging"
+	"github.com/neovasili/metal-fests/internal/model"
+	"github.com/neovasili/metal-fests/internal/openai"
+	"github.com/neovasili/metal-fests/internal/updater"
+)
+
+// FestivalUpdateResult is the structured output requested from the model.
+// Its JSON schema is generated from this type, see festivalUpdateSchema.
+type FestivalUpdateResult struct {
+	Bands       []LineupBand `json:"bands" description:"Every band announced on the lineup"`
+	TicketPrice *float64     `json:"ticketPrice" description:"Full festival ticket price without currency, or null when unknown"`
+	Dates       *EditionDate `json:"dates" description:"Dates of the edition, or null when not announced"`
+	Location    *string      `json:"location" description:"Town and country of the venue, or null when unknown"`
+	Website     *string      `json:"website" description:"Official festival website, or null when unknown"`
+	Poster      *string      `json:"poster" description:"Absolute URL of the edition poster, or null when unknown"`
+
+	// PromptVersion identifies the prompt that produced this result
+	PromptVersion string `json:"-"`
+	// Model and Sources describe where the answer came from
+	Model   string           `json:"-"`
+	Sources []model.Citation `json:"-"`
+}
+
+// provenance describes the origin of the fields updated from this result
+func (r *FestivalUpdateResult) provenance() model.Provenance {
+	return model.Provenance{
+		Origin:        model.ProvenanceAI,
+		Sources:       r.Sources,
+		Model:         r.Model,
+		PromptVersion: r.PromptVersion,
+		UpdatedAt:     time.Now().UTC(),
+	}
+}
+
+// LineupBand is a band as it appears on the festival lineup
+type LineupBand struct {
+	Name string `json:"name" description:"Band name as shown on the lineup"`
+	Size int    `json:"size" jsonschema:"enum=1|2|3" description:"Visual tier of the band name, 1 being the smallest text"`
+}
+
+// EditionDate holds the first and last day of a festival edition
+type EditionDate struct {
+	Start string `json:"start" description:"First day as YYYY-MM-DD"`
+	End   string `json:"end" description:"Last day as YYYY-MM-DD"`
+}
+
+// festivalUpdateSchema is the JSON schema sent along with every festival search
+var festivalUpdateSchema = openai.GenerateSchema(FestivalUpdateResult{})
+
+type FestivalChange struct {
+	Name              string
+	NewBands          []string
+	UpdatedBands      []string
+	PossiblyCancelled []string
+	RemovedBands      []string
+	ReturnedBands     []string
+	LinkedBands       []string
+	AmbiguousBands    []string
+	FilledDetails     []DetailChange
+	DetailChanges     []DetailChange
+	OldPrice          float64
+	NewPrice          float64
+	PriceUpdated      bool
+}
+
+// DetailChange is a festival detail that differs from the official website
+type DetailChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+// needsReview tells whether the change holds something a person has to look at
+func (c FestivalChange) needsReview() bool {
+	return len(c.AmbiguousBands) > 0 || len(c.DetailChanges) > 0
+}
+
+// reportChanges lists the change for the run report
+func (c FestivalChange) reportChanges() []updater.Change {
+	var changes []updater.Change
+	bands := func(kind string, names []string) {
+		for _, name := range names {
+			changes = append(changes, updater.Change{Kind: kind, New: name})
+		}
+	}
+	bands("newBand", c.NewBands)
+	bands("updatedBand", c.UpdatedBands)
+	bands("possiblyCancelled", c.PossiblyCancelled)
+	bands("removedBand", c.RemovedBands)
+	bands("returnedBand", c.ReturnedBands)
+	bands("linkedBand", c.LinkedBands)
+	bands("ambiguousBand", c.AmbiguousBands)
+	for _, detail := range c.FilledDetails {
+		changes = append(changes, updater.Change{Kind: "filledDetail", Field: detail.Field, New: detail.New})
+	}
+	for _, detail := range c.DetailChanges {
+		changes = append(changes, updater.Change{Kind: "changedDetail", Field: detail.Field, Old: detail.Old, New: detail.New})
+	}
+	if c.PriceUpdated {
+		changes = append(changes, updater.Change{
+			Kind:  "price",
+			Field: "ticketPrice",
+			Old:   fmt.Sprintf("%.2f", c.OldPrice),
+			New:   fmt.Sprintf("%.2f", c.NewPrice),
+		})
+	}
+	return changes
+}
+
+type UpdateStats struct {
+	TotalFestivals   int
+	UpdatedFestivals int
+	NewBands         int
+	RemovedBands     int
+	FlaggedBands     int
+	LinkedBands      int
+	AmbiguousBands   int
+	FilledDetails    int
+	DetailChanges    int
+	UpdatedPrices    int
+	FailedFestivals  int
+	Resumed          int
+	TotalTokens      int
+	TotalCost        float64
+	UsedModel        string
+	PromptTokens     int
+	CompletionTokens int
+	PromptVersion    string
+	Processed        int
+	Interrupted      bool
+	// Items holds the outcome of every festival, in processing order
+	Items []updater.ItemReport
+}
+
+// festivalResult is produced by a worker and applied to the database by the collector
+type festivalResult struct {
+	key            string
+	name           string
+	festival       model.Festival
+	change         FestivalChange
+	failure        string
+	updated        bool
+	newBands       int
+	removedBands   int
+	flaggedBands   int
+	linkedBands    int
+	ambiguousBands int
+	priceUpdated   bool
+	tokens         int
+	cost           float64
+	usedModel      string
+	promptVersion  string
+	duration       time.Duration
+	// span covers the festival until its result is stored
+	span trace.Span
+}
+
+var openaiClient *openai.OpenAIClient
+
+// editionYear returns the festival edition to ask for: the override when
+// set, otherwise the year of the stored start date, otherwise the current
+// year. Once the stored edition has ended, the next one is asked for, as the
+// dates found are never applied and would keep pointing at the past edition.
+func editionYear(festival model.Festival, override int, now time.Time) int {
+	if override > 0 {
+		return override
+	}
+	start, err := time.Parse("2006-01-02", festival.Dates.Start)
+	if err != nil {
+		return now.Year()
+	}
+	end, err := time.Parse("2006-01-02", festival.Dates.End)
+	if err != nil || end.Before(start) {
+		end = start
+	}
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	if !end.Before(today) {
+		return start.Year()
+	}
+	return max(start.Year()+1, now.Year())
+}
+
+func searchFestivalInfo(ctx context.Context, prompt *openai.Prompt, festival model.Festival, year int, useFallbackModel bool, dryRun bool) (*FestivalUpdateResult, int, float64, string, error) {
+	usedTokens := 0
+	estimatedCost := 0.0
+	usedModel := ""
+
+	userPrompt, err := prompt.Render(map[string]any{
+		"FestivalName":     festival.Name,
+		"FestivalLocation": festival.Location,
+		"FestivalURL":      festival.Website,
+		"EditionYear":      year,
+	})
+	if err != nil {
+		return nil, usedTokens, estimatedCost, usedModel, err
+	}
+
+	modelToUse, fallbackModel := openaiClient.Models()
+	if useFallbackModel {
+		modelToUse = fallbackModel
+	}
+
+	resp, err := openaiClient.AskOpenAI(ctx, userPrompt, festivalUpdateSchema, modelToUse, dryRun)
+	if err != nil {
+		return nil, usedTokens, estimatedCost, usedModel, err
+	}
+
+	if resp == nil {
+		return nil, usedTokens, estimatedCost, usedModel, fmt.Errorf("no response from OpenAI")
+	}
+	usedTokens = resp.TotalUsedTokens
+	estimatedCost = resp.EstimatedCost
+	usedModel = string(resp.UsedModel)
+	if len(resp.OutputText) == 0 {
+		return nil, usedTokens, estimatedCost, usedModel, fmt.Errorf("empty response from OpenAI")
+	}
+
+	content := strings.TrimSpace(resp.OutputText)
+	var result FestivalUpdateResult
+	if err := json.Unmarshal([]byte(content), &result); err != nil {
+		return nil, usedTokens, estimatedCost, usedModel, fmt.Errorf("failed to parse OpenAI response: %w", err)
+	}
+	result.PromptVersion = prompt.Version
+	result.Model = usedModel
+	result.Sources = resp.Citations
+
+	return &result, usedTokens, estimatedCost, usedModel, nil
+}
+
+// processFestival fetches the latest information for a single festival and
+// works out the resulting changes. It runs concurrently with other festivals,
+// so it must not touch the database.
+func processFestival(ctx context.Context, prompt *openai.Prompt, festival model.Festival, year int, resolver *data.BandResolver, removeAfter int, dryRun bool, openaiResponseFilePath string) festivalResult {
+	outcome := festivalResult{promptVersion: prompt.Version}
+
+	var result = &FestivalUpdateResult{}
+	var tokens int
+	var cost float64
+	var usedModel string
+	var err error
+	if openaiResponseFilePath != "" {
+		// Load OpenAI response from file for testing
+		// #nosec G304 -- This is a command-line script where the file path is provided by the user
+		content, err := os.ReadFile(openaiResponseFilePath)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to read OpenAI response file", "error", err)
+			outcome.failure = err.Error()
+			return outcome
+		}
+		slog.DebugContext(ctx, "OpenAI response file", "path", openaiResponseFilePath, "content", string(content))
+		if err := json.Unmarshal(content, result); err != nil {
+			slog.ErrorContext(ctx, "failed to parse OpenAI response file", "error", err)
+			outcome.failure = err.Error()
+			return outcome
+		}
+		slog.InfoContext(ctx, "OpenAI response loaded from file", "path", openaiResponseFilePath)
+	} else {
+		result, tokens, cost, usedModel, err = searchFestivalInfo(ctx, prompt, festival, year, false, dryRun)
+		outcome.tokens += tokens
+		outcome.cost += cost
+		outcome.usedModel = usedModel
+		if err != nil {
+			slog.ErrorContext(ctx, "festival search failed", "error", err)
+			outcome.failure = err.Error()
+			return outcome
+		}
+		if result == nil {
+			slog.InfoContext(ctx, "festival skipped", "reason", "dry run")
+			return outcome
+		}
+		// If no bands or ticket price found, retry with fallback model
+		if len(result.Bands) == 0 && result.TicketPrice == nil {
+			result, tokens, cost, usedModel, err = searchFestivalInfo(ctx, prompt, festival, year, true, dryRun)
+			outcome.tokens += tokens
+			outcome.cost += cost
+			outcome.usedModel = usedModel
+			if err != nil {
+				slog.ErrorContext(ctx, "festival search failed", "error", err)
+				outcome.failure = err.Error()
+				return outcome
+			}
+		}
+	}
+
+	return applyFestivalResult(ctx, festival, result, resolver, removeAfter, outcome)
+}
+
+// applyFestivalResult merges the fetched lineup and price into the festival.
+// Lineup names are linked to the known bands through the resolver.
+// Bands missing from the fetched lineup are flagged as possibly cancelled and
+// removed after removeAfter consecutive misses (0 never removes them).
+func applyFestivalResult(ctx context.Context, festival model.Festival, result *FestivalUpdateResult, resolver *data.BandResolver, removeAfter int, outcome festivalResult) festivalResult {
+	festivalChange := FestivalChange{
+		Name: festival.Name,
+	}
+	var updatedFields []string
+
+	// Update bands if new ones found
+	if len(result.Bands) > 0 {
+		oldBandCount := len(festival.Bands)
+		newBands := make([]model.BandRef, 0)
+		updatedBands := make([]model.BandRef, 0)
+
+		lineup, fetchedKeys := resolveLineup(result.Bands, resolver, &festivalChange)
+		outcome.linkedBands = len(festivalChange.LinkedBands)
+		outcome.ambiguousBands = len(festivalChange.AmbiguousBands)
+		for _, band := range lineup {
+			if !containsBand(festival.Bands, band.Name) && !containsBandKey(festival.Bands, band.Key) {
+				newBands = append(newBands, band)
+				festivalChange.NewBands = append(festivalChange.NewBands, band.Name)
+			} else {
+				if bandHasBeenUpdated(festival.Bands, band) {
+					updatedBands = append(updatedBands, band)
+					festivalChange.UpdatedBands = append(festivalChange.UpdatedBands, band.Name)
+				}
+			}
+		}
+
+		if len(newBands) > 0 {
+			festival.Bands = append(festival.Bands, newBands...)
+			outcome.newBands = len(newBands)
+			outcome.updated = true
+			slog.InfoContext(ctx, "festival bands added", "added", len(newBands), "old_total", oldBandCount, "total", len(festival.Bands))
+		}
+
+		if len(updatedBands) > 0 {
+			for _, bandRef := range updatedBands {
+				festival = updateBandData(festival, bandRef)
+			}
+			slog.InfoContext(ctx, "festival bands updated", "updated", len(updatedBands))
+			outcome.updated = true
+		}
+
+		if diffLineup(ctx, &festival, fetchedKeys, removeAfter, &festivalChange, &outcome) {
+			outcome.updated = true
+		}
+
+		if outcome.updated {
+			updatedFields = append(updatedFields, "bands")
+		}
+	}
+
+	// Update ticket price if available and different, keeping track of every price seen
+	if result.TicketPrice != nil && *result.TicketPrice > 0 && *result.TicketPrice != festival.TicketPrice {
+		festivalChange.OldPrice = festival.TicketPrice
+		festivalChange.NewPrice = *result.TicketPrice
+		festivalChange.PriceUpdated = true
+		// Prices stored before the history existed are dated by their
+		// provenance. Without one, there is no date to give them and the
+		// history starts with the new price.
+		if setAt := festival.Provenance["ticketPrice"].UpdatedAt; len(festival.PriceHistory) == 0 && festival.TicketPrice > 0 && !setAt.IsZero() {
+			festival.PriceHistory = []model.PricePoint{{Price: festival.TicketPrice, ObservedAt: setAt}}
+		}
+		festival.RecordPrice(*result.TicketPrice, time.Now().UTC())
+		outcome.priceUpdated = true
+		outcome.updated = true
+		updatedFields = append(updatedFields, "ticketPrice")
+		slog.InfoContext(ctx, "festival ticket price updated", "old", festivalChange.OldPrice, "new", festivalChange.NewPrice)
+	}
+
+	if filled := diffDetails(ctx, &festival, result, &festivalChange); len(filled) > 0 {
+		outcome.updated = true
+		updatedFields = append(updatedFields, filled...)
+	}
+
+	if len(updatedFields) > 0 {
+		festival.Provenance = festival.Provenance.Set(result.provenance(), updatedFields...)
+	}
+
+	outcome.festival = festival
+	outcome.change = festivalChange
+	return outcome
+}
+
+// collectFestivalResult applies a worker result to the database and stats and
+// returns the status to record in the checkpoint.
+// It is only ever called from a single goroutine.
+func collectFestivalResult(ctx context.Context, stats *UpdateStats, result festivalResult) (updater.ItemStatus, string) {
+	status, reason := storeFestivalResult(ctx, stats, result)
+
+	item := updater.ItemReport{
+		Key:        result.key,
+		Name:       result.name,
+		Status:     status,
+		Reason:     reason,
+		Changes:    result.change.reportChanges(),
+		Model:      result.usedModel,
+		Tokens:     result.tokens,
+		Cost:       result.cost,
+		DurationMs: result.duration.Milliseconds(),
+	}
+	switch {
+	case status == updater.StatusFailed:
+		item.Outcome = "failed"
+		item.Error = reason
+	case status == updater.StatusProcessed:
+		item.Outcome = "updated"
+	case result.change.needsReview():
+		item.Outcome = "review"
+	default:
+		item.Outcome = "unchanged"
+	}
+	stats.Items = append(stats.Items, item)
+
+	return status, reason
+}
+
+// storeFestivalResult writes a single result to the database and counts it
+func storeFestivalResult(ctx context.Context, stats *UpdateStats, result festivalResult) (updater.ItemStatus, string) {
+	stats.TotalTokens += result.tokens
+	stats.TotalCost += result.cost
+	if result.usedModel != "" {
+		stats.UsedModel = result.usedModel
+	}
+
+	if result.failure != "" {
+		stats.FailedFestivals++
+		return updater.StatusFailed, result.failure
+	}
+	// Ambiguous names and detail changes are reported even when nothing else
+	// changed, so someone can look into them
+	stats.AmbiguousBands += result.ambiguousBands
+	stats.DetailChanges += len(result.change.DetailChanges)
+	if !result.updated {
+		return updater.StatusSkipped, "no changes"
+	}
+
+	if err := data.UpdateFestivalInDatabase(ctx, result.festival); err != nil {
+		slog.ErrorContext(ctx, "failed to update festival in database", "error", err)
+		stats.FailedFestivals++
+		return updater.StatusFailed, err.Error()
+	}
+	// Only the changes that were written count as done
+	stats.NewBands += result.newBands
+	stats.RemovedBands += result.removedBands
+	stats.FlaggedBands += result.flaggedBands
+	stats.LinkedBands += result.linkedBands
+	stats.FilledDetails += len(result.change.FilledDetails)
+	if result.priceUpdated {
+		stats.UpdatedPrices++
+	}
+	stats.UpdatedFestivals++
+	return updater.StatusProcessed, "updated"
+}
+
+// newBandResolver indexes the bands in the database and the ones referenced by festival lineups
+func newBandResolver(ctx context.Context, linkThreshold float64) *data.BandResolver {
+	bands, err := data.GetBands(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to read bands", "error", err)
+	}
+	festivals, err := data.GetFestivals(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "failed to read festivals", "error", err)
+	}
+
+	refs := make([]model.BandRef, 0)
+	for _, festival := range festivals {
+		refs = append(refs, festival.Bands...)
+	}
+	return data.NewBandResolver(bands, refs, linkThreshold)
+}
+
+// updateExistingFestivals stops picking up new festivals once stop is done;
+// abort bounds the requests of festivals that are already in progress.
+func updateExistingFestivals(stop, abort context.Context, prompt *openai.Prompt, year int, linkThreshold float64, removeAfter int, dryRun bool, festivalName string, openaiResponseFilePath string, concurrency int, checkpoint *updater.Checkpoint, mode updater.ResumeMode) *UpdateStats {
+	festivals, err := data.GetFestivals(stop)
+	if err != nil {
+		slog.ErrorContext(stop, "failed to read festivals", "error", err)
+	}
+
+	// Filter by festival name if provided
+	if festivalName != "" {
+		filteredFestivals := make([]model.Festival, 0)
+		for _, festival := range festivals {
+			if festival.Name == festivalName {
+				filteredFestivals = append(filteredFestivals, festival)
+				break
+			}
+		}
+		if len(filteredFestivals) == 0 {
+			slog.WarnContext(stop, "festival not found", "festival", festivalName)
+			return &UpdateStats{}
+		}
+		festivals = filteredFestivals
+	} else {
+		// A canned response only makes sense for a single festival
+		openaiResponseFilePath = ""
+	}
+
+	// Lineup names are resolved against every known band, not only the selected festivals
+	resolver := newBandResolver(stop, linkThreshold)
+
+	stats := &UpdateStats{PromptVersion: prompt.Version}
+
+	// Leave out festivals already handled according to the checkpoint
+	if mode != updater.ModeFresh {
+		pending := make([]model.Festival, 0, len(festivals))
+		for _, festival := range festivals {
+			if checkpoint.ShouldProcess(festival.Key, mode) {
+				pending = append(pending, festival)
+			}
+		}
+		stats.Resumed = len(festivals) - len(pending)
+		festivals = pending
+		slog.InfoContext(stop, "continuing from the checkpoint", "festivals", len(festivals), "resumed", stats.Resumed)
+	}
+
+	stats.TotalFestivals = len(festivals)
+
+	// Process festivals concurrently; database writes happen in order in the collector
+	stats.Processed = updater.Run(stop, stats.TotalFestivals, concurrency,
+		func(i int, log *logging.Buffer) festivalResult {
+			festival := festivals[i]
+			edition := editionYear(festival, year, time.Now())
+			started := time.Now()
+			ctx, span := updater.StartItem(updater.ItemContext(abort, log, "festival", festival.Key), "festival", festival.Key)
+			slog.InfoContext(ctx, "processing festival", "name", festival.Name, "edition", edition, "position", i+1, "total", stats.TotalFestivals)
+			result := processFestival(ctx, prompt, festival, edition, resolver, removeAfter, dryRun, openaiResponseFilePath)
+			result.key = festival.Key
+			result.name = festival.Name
+			result.duration = time.Since(started)
+			result.span = span
+			return result
+		},
+		func(i int, result festivalResult, log *logging.Buffer) {
+			ctx := updater.ItemContext(trace.ContextWithSpan(stop, result.span), log, "festival", festivals[i].Key)
+			status, reason := collectFestivalResult(ctx, stats, result)
+			updater.EndItem(result.span, status, reason)
+			// Festivals cut short by a shutdown stay unrecorded so a resumed run picks them up
+			if dryRun || (status == updater.StatusFailed && abort.Err() != nil) {
+				return
+			}
+			entry := updater.CheckpointEntry{Status: status, Reason: reason, PromptVersion: result.promptVersion}
+			if err := checkpoint.Record(festivals[i].Key, entry); err != nil {
+				slog.ErrorContext(ctx, "failed to save checkpoint", "error", err)
+			}
+		},
+	)
+	stats.Interrupted = stats.Processed < stats.TotalFestivals
+
+	return stats
+}
+
+// Check if a band is already in the festival's band list
+func containsBand(bands []model.BandRef, bandName string) bool {
+	for _, band := range bands {
+		if band.Name == bandName {
+			return true
+		}
+	}
+	return false
+}
+
+// diffDetails compares the dates, location, website and poster found with the
+// stored ones. Missing values are filled in and their fields returned. Values
+// that differ are only reported, since a postponed edition or a moved venue
+// needs a person to double check it, and to fix the coordinates.
+func diffDetails(ctx context.Context, festival *model.Festival, result *FestivalUpdateResult, change *FestivalChange) []string {
+	var filled []string
+	compare := func(field, stored, fetched string, same func(a, b string) bool, apply func(value string)) {
+		switch {
+		case fetched == "" || same(stored, fetched):
+			return
+		case stored == "":
+			apply(fetched)
+			filled = append(filled, field)
+			change.FilledDetails = append(change.FilledDetails, DetailChange{Field: field, New: fetched})
+			slog.InfoContext(ctx, "festival detail filled", "field", field, "new", fetched)
+		default:
+			change.DetailChanges = append(change.DetailChanges, DetailChange{Field: field, Old: stored, New: fetched})
+			slog.WarnContext(ctx, "festival detail changed, not applied", "field", field, "old", stored, "new", fetched)
+		}
+	}
+
+	if result.Dates != nil {
+		if fetched, ok := editionDates(*result.Dates); ok {
+			stored := ""
+			if festival.Dates.Start != "" {
+				stored = formatDates(festival.Dates)
+			}
+			compare("dates", stored, formatDates(fetched), sameText, func(string) { festival.Dates = fetched })
+		} else {
+			slog.WarnContext(ctx, "invalid festival dates ignored", "start", result.Dates.Start, "end", result.Dates.End)
+		}
+	}
+	if result.Location != nil {
+		compare("location", festival.Location, strings.TrimSpace(*result.Location), sameText, func(value string) { festival.Location = value })
+	}
+	if result.Website != nil {
+		compare("website", festival.Website, strings.TrimSpace(*result.Website), sameURL, func(value string) { festival.Website = value })
+	}
+	if result.Poster != nil {
+		compare("poster", festival.Poster, strings.TrimSpace(*result.Poster), sameURL, func(value string) { festival.Poster = value })
+	}
+
+	return filled
+}
+
+// editionDates validates the dates found, filling in the end of one day festivals
+func editionDates(dates EditionDate) (model.Dates, bool) {
+	if dates.End == "" {
+		dates.End = dates.Start
+	}
+	start, err := time.Parse("2006-01-02", dates.Start)
+	if err != nil {
+		return model.Dates{}, false
+	}
+	end, err := time.Parse("2006-01-02", dates.End)
+	if err != nil || end.Before(start) {
+		return model.Dates{}, false
+	}
+	return model.Dates{Start: dates.Start, End: dates.End}, true
+}
+
+func formatDates(dates model.Dates) string {
+	return fmt.Sprintf("%s – %s", dates.Start, dates.End)
+}
+
+func sameText(a, b string) bool {
+	return strings.EqualFold(strings.TrimSpace(a), strings.TrimSpace(b))
+}
+
+// sameURL ignores the differences that do not point to another page:
+// the scheme, a leading www, letter case and a trailing slash
+func sameURL(a, b string) bool {
+	normalize := func(value string) string {
+		value = strings.ToLower(strings.TrimSpace(value))
+		value = strings.TrimPrefix(strings.TrimPrefix(value, "https://"), "http://")
+		return strings.TrimSuffix(strings.TrimPrefix(value, "www."), "/")
+	}
+	return normalize(a) == normalize(b)
+}
+
+// resolveLineup links the fetched band names to the bands already known,
+// dropping the names that are too close to several bands to pick one.
+// It returns the resolved lineup and the keys of every band the lineup may refer to.
+func resolveLineup(fetched []LineupBand, resolver *data.BandResolver, change *FestivalChange) ([]model.BandRef, map[string]bool) {
+	lineup := make([]model.BandRef, 0, len(fetched))
+	fetchedKeys := make(map[string]bool, len(fetched))
+
+	for _, lineupBand := range fetched {
+		normalizedBandName := data.NormalizeBandName(lineupBand.Name)
+		band := model.BandRef{
+			Key:  data.GenerateBandKey(normalizedBandName),
+			Name: normalizedBandName,
+			Size: lineupBand.Size,
+		}
+
+		match, found, candidates := resolver.Resolve(normalizedBandName)
+		switch {
+		case found:
+			if match.Key != band.Key || match.Name != band.Name {
+				change.LinkedBands = append(change.LinkedBands, fmt.Sprintf("%s → %s (%.2f)", lineupBand.Name, match.Name, match.Score))
+			}
+			band.Key = match.Key
+			band.Name = match.Name
+		case len(candidates) > 0:
+			// Leave it to a person, but do not flag any of the candidates as missing
+			names := make([]string, 0, len(candidates))
+			for _, candidate := range candidates {
+				names = append(names, fmt.Sprintf("%s (%.2f)", candidate.Name, candidate.Score))
+				fetchedKeys[candidate.Key] = true
+			}
+			change.AmbiguousBands = append(change.AmbiguousBands, fmt.Sprintf("%s: %s", lineupBand.Name, strings.Join(names, ", ")))
+			continue
+		}
+
+		if fetchedKeys[band.Key] {
+			continue
+		}
+		fetchedKeys[band.Key] = true
+		lineup = append(lineup, band)
+	}
+
+	return lineup, fetchedKeys
+}
+
+// diffLineup compares the stored lineup with the fetched one, flagging the
+// bands that disappeared and clearing the flag of those that came back.
+// It returns whether the stored lineup changed.
+func diffLineup(ctx context.Context, festival *model.Festival, fetchedKeys map[string]bool, removeAfter int, change *FestivalChange, outcome *festivalResult) bool {
+	changed := false
+	lineup := make([]model.BandRef, 0, len(festival.Bands))
+	for _, band := range festival.Bands {
+		if fetchedKeys[band.Key] {
+			if band.PossiblyCancelled || band.Misses > 0 {
+				band.PossiblyCancelled = false
+				band.Misses = 0
+				change.ReturnedBands = append(change.ReturnedBands, band.Name)
+				changed = true
+			}
+			lineup = append(lineup, band)
+			continue
+		}
+
+		band.Misses++
+		changed = true
+		if removeAfter > 0 && band.Misses >= removeAfter {
+			change.RemovedBands = append(change.RemovedBands, band.Name)
+			outcome.removedBands++
+			continue
+		}
+		if !band.PossiblyCancelled {
+			outcome.flaggedBands++
+		}
+		band.PossiblyCancelled = true
+		change.PossiblyCancelled = append(change.PossiblyCancelled, fmt.Sprintf("%s (missing %d time(s))", band.Name, band.Misses))
+		lineup = append(lineup, band)
+	}
+	festival.Bands = lineup
+
+	if len(change.RemovedBands) > 0 {
+		slog.InfoContext(ctx, "bands no longer on the lineup removed", "removed", len(change.RemovedBands))
+	}
+	if len(change.PossiblyCancelled) > 0 {
+		slog.WarnContext(ctx, "bands missing from the lineup, possibly cancelled", "missing", len(change.PossiblyCancelled))
+	}
+	if len(change.ReturnedBands) > 0 {
+		slog.InfoContext(ctx, "bands back on the lineup", "returned", len(change.ReturnedBands))
+	}
+	return changed
+}
+
+// Check if a festival lists a band key
+func containsBandKey(bands []model.BandRef, key string) bool {
+	for _, band := range bands {
+		if band.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Check if a band has been updated
+func bandHasBeenUpdated(bands []model.BandRef, band model.BandRef) bool {
+	for _, b := range bands {
+		if b.Key == band.Key {
+			if b.Name != band.Name || b.Size != band.Size {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Updates a band data in a festival's band list
+func updateBandData(festival model.Festival, band model.BandRef) model.Festival {
+	for i, b := range festival.Bands {
+		if b.Key == band.Key {
+			// Keep the cancellation tracking of the stored band
+			festival.Bands[i].Name = band.Name
+			festival.Bands[i].Size = band.Size
+			break
+		}
+	}
+	return festival
+}
+
+// summaryTemplate renders the pull request summary from the run report
+//
+//go:embed summary.md.tmpl
+var summaryTemplate string
+
+// buildReport turns the stats of a run into its report
+func buildReport(stats *UpdateStats, startedAt time.Time, dryRun bool) *updater.Report {
+	report := updater.NewReport("festival_updater", startedAt)
+	report.DryRun = dryRun
+	report.PromptVersion = stats.PromptVersion
+	report.Model = stats.UsedModel
+	report.Tokens = stats.TotalTokens
+	report.Cost = stats.TotalCost
+	report.Total = stats.TotalFestivals
+	report.Processed = stats.Processed
+	report.Resumed = stats.Resumed
+	report.Interrupted = stats.Interrupted
+	report.Counts = map[string]int{
+		"updated":        stats.UpdatedFestivals,
+		"failed":         stats.FailedFestivals,
+		"newBands":       stats.NewBands,
+		"flaggedBands":   stats.FlaggedBands,
+		"removedBands":   stats.RemovedBands,
+		"linkedBands":    stats.LinkedBands,
+		"ambiguousBands": stats.AmbiguousBands,
+		"updatedPrices":  stats.UpdatedPrices,
+		"filledDetails":  stats.FilledDetails,
+		"detailChanges":  stats.DetailChanges,
+	}
+	if stats.Items != nil {
+		report.Items = stats.Items
+	}
+	return report
+}
+
+func generatePRSummary(report *updater.Report) (string, error) {
+	return report.Render(summaryTemplate)
+}
+
+// Options configures a festival update run
+type Options struct {
+	DryRun            bool
+	Festival          string
+	OpenAIResponse    string
+	Concurrency       int
+	RequestsPerMinute int
+	RequestTimeout    time.Duration
+	Model             string
+	FallbackModel     string
+	Timeout           time.Duration
+	Checkpoint        string
+	Report            string
+	Summary           string
+	Prompt            string
+	Resume            bool
+	RetryFailed       bool
+	Year              int
+	RemoveAfter       int
+	LinkThreshold     float64
+}
+
+// RegisterFlags defines the command line flags of a festival update run
+func (o *Options) RegisterFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&o.DryRun, "dry-run", false, "Enable dry run mode")
+	fs.StringVar(&o.Festival, "festival", "", "Specify festival name")
+	fs.StringVar(&o.OpenAIResponse, "openai-response", "", "Specify OpenAI response file path for testing")
+	fs.IntVar(&o.Concurrency, "concurrency", 4, "Number of festivals processed in parallel")
+	fs.IntVar(&o.RequestsPerMinute, "rpm", 60, "Maximum OpenAI requests per minute (0 disables the limit)")
+	fs.DurationVar(&o.RequestTimeout, "request-timeout", openai.DefaultRequestTimeout, "Maximum duration of a single OpenAI request")
+	fs.StringVar(&o.Model, "model", openai.PrimaryModel, "OpenAI model used to look up festivals")
+	fs.StringVar(&o.FallbackModel, "fallback-model", openai.FallbackModel, "OpenAI model used when the primary one is rate limited or finds nothing")
+	fs.DurationVar(&o.Timeout, "timeout", 0, "Maximum duration of the whole run (0 means no limit)")
+	fs.StringVar(&o.Checkpoint, "checkpoint", "festival_update_checkpoint.json", "Checkpoint file recording per-festival progress")
+	fs.StringVar(&o.Report, "report", "festival_update_report.json", "JSON report of the run")
+	fs.StringVar(&o.Summary, "summary", "festival_update_summary.md", "Markdown summary of the run")
+	fs.StringVar(&o.Prompt, "prompt", "scripts/festival_prompt.md", "Prompt template used to look up festivals")
+	fs.BoolVar(&o.Resume, "resume", false, "Continue from the checkpoint, skipping festivals already handled")
+	fs.BoolVar(&o.RetryFailed, "retry-failed", false, "Only retry festivals that failed in the checkpointed run")
+	fs.IntVar(&o.Year, "year", 0, "Festival edition year to look up (defaults to the year of each festival's start date, or the next one once it has passed)")
+	fs.IntVar(&o.RemoveAfter, "remove-after", 3, "Remove bands missing from this many consecutive lineup updates (0 only flags them)")
+	fs.Float64Var(&o.LinkThreshold, "link-threshold", data.DefaultLinkThreshold, "Name similarity above which lineup names are linked to existing bands")
+}
+
+// Run looks up the lineup, price and details of every festival in the
+// database and applies the changes. It returns an error when the run cannot
+// start, or when it is interrupted by a signal.
+func Run(ctx context.Context, opts Options) error {
+	startedAt := time.Now()
+
+	if opts.Resume && opts.RetryFailed {
+		return errors.New("--resume and --retry-failed cannot be combined")
+	}
+
+	if opts.Concurrency < 1 {
+		return errors.New("--concurrency must be at least 1")
+	}
+
+	if opts.RemoveAfter < 0 {
+		return errors.New("--remove-after cannot be negative")
+	}
+
+	if opts.DryRun {
+		slog.InfoContext(ctx, "dry-run mode, no API calls are made and no files are modified")
+	}
+
+	if opts.Festival != "" {
+		slog.InfoContext(ctx, "single festival mode", "festival", opts.Festival)
+	}
+
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" && !opts.DryRun {
+		return errors.New("OPENAI_API_KEY environment variable not set")
+	}
+
+	openaiClient = openai.NewOpenAIClient(apiKey)
+	openaiClient.SetRequestsPerMinute(opts.RequestsPerMinute)
+	openaiClient.SetRequestTimeout(opts.RequestTimeout)
+	openaiClient.SetModels(opts.Model, opts.FallbackModel)
+
+	// Load prompt template
+	prompt, err := openai.LoadPrompt(opts.Prompt)
+	if err != nil {
+		return fmt.Errorf("loading prompt template: %w", err)
+	}
+
+	// Load the checkpoint of a previous run when continuing from it
+	mode := updater.ModeFresh
+	checkpoint := updater.NewCheckpoint(opts.Checkpoint)
+	if opts.Resume || opts.RetryFailed {
+		mode = updater.ModeResume
+		if opts.RetryFailed {
+			mode = updater.ModeRetryFailed
+		}
+		checkpoint, err = updater.LoadCheckpoint(opts.Checkpoint)
+		if err != nil {
+			return fmt.Errorf("loading checkpoint: %w", err)
+		}
+	}
+
+	// Stop on SIGINT/SIGTERM or when the whole-run deadline expires
+	if opts.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+		defer cancel()
+	}
+	stop, abort, release := updater.NotifyShutdown(ctx)
+	defer release()
+
+	stats := updateExistingFestivals(stop, abort, prompt, opts.Year, opts.LinkThreshold, opts.RemoveAfter, opts.DryRun, opts.Festival, opts.OpenAIResponse, opts.Concurrency, checkpoint, mode)
+
+	// Generate the report and the PR summary rendered from it
+	report := buildReport(stats, startedAt, opts.DryRun)
+	report.Finish(time.Now())
+	if err := report.WriteJSON(opts.Report); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
+	summary, err := generatePRSummary(report)
+	if err != nil {
+		return fmt.Errorf("generating summary: %w", err)
+	}
+	if err := os.WriteFile(opts.Summary, []byte(summary), 0600); err != nil {
+		return fmt.Errorf("writing summary: %w", err)
+	}
+
+	if stats.Interrupted {
+		slog.WarnContext(ctx, "festival update stopped early", "cause", context.Cause(stop), "summary", opts.Summary, "report", opts.Report)
+		if errors.Is(context.Cause(stop), updater.ErrInterrupted) {
+			return fmt.Errorf("festival update stopped early: %w", context.Cause(stop))
+		}
+		return nil
+	}
+
+	slog.InfoContext(ctx, "festival update completed", "summary", opts.Summary, "report", opts.Report)
+	return nil
+}
@@ -0,0 +1,936 @@
+package festivals
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/data"
+	"github.com/neovasili/metal-fests/internal/model"
+	"github.com/neovasili/metal-fests/internal/updater"
+)
+
+func TestContainsBand(t *testing.T) {
+	tests := []struct {
+		name     string
+		bands    []model.BandRef
+		bandName string
+		expected bool
+	}{
+		{
+			name: "Band exists in list",
+			bands: []model.BandRef{
+				{Key: "metallica", Name: "Metallica"},
+				{Key: "iron-maiden", Name: "Iron Maiden"},
+			},
+			bandName: "Metallica",
+			expected: true,
+		},
+		{
+			name: "Band does not exist in list",
+			bands: []model.BandRef{
+				{Key: "metallica", Name: "Metallica"},
+				{Key: "iron-maiden", Name: "Iron Maiden"},
+			},
+			bandName: "Slayer",
+			expected: false,
+		},
+		{
+			name:     "Empty band list",
+			bands:    []model.BandRef{},
+			bandName: "Metallica",
+			expected: false,
+		},
+		{
+			name:     "Nil band list",
+			bands:    nil,
+			bandName: "Metallica",
+			expected: false,
+		},
+		{
+			name: "Case sensitive match",
+			bands: []model.BandRef{
+				{Key: "metallica", Name: "Metallica"},
+			},
+			bandName: "metallica",
+			expected: false,
+		},
+		{
+			name: "Band with spaces",
+			bands: []model.BandRef{
+				{Key: "black-sabbath", Name: "Black Sabbath"},
+			},
+			bandName: "Black Sabbath",
+			expected: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := containsBand(tt.bands, tt.bandName)
+			if result != tt.expected {
+				t.Errorf("containsBand() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBandNameToKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		bandName string
+		expected string
+	}{
+		{
+			name:     "Simple band name",
+			bandName: "Metallica",
+			expected: "metallica",
+		},
+		{
+			name:     "Band name with spaces",
+			bandName: "Iron Maiden",
+			expected: "iron-maiden",
+		},
+		{
+			name:     "Band name with ampersand",
+			bandName: "Foo & Bar",
+			expected: "foo-bar",
+		},
+		{
+			name:     "Band name with multiple ampersands",
+			bandName: "A & B & C",
+			expected: "a-b-c",
+		},
+		{
+			name:     "Band name with consecutive spaces",
+			bandName: "Foo   Bar",
+			expected: "foo-bar",
+		},
+		{
+			name:     "Band name all uppercase",
+			bandName: "BLACK SABBATH",
+			expected: "black-sabbath",
+		},
+		{
+			name:     "Band name with mixed case",
+			bandName: "DeAd KeNnEdYs",
+			expected: "dead-kennedys",
+		},
+		{
+			name:     "Band name with consecutive hyphens",
+			bandName: "Foo--Bar",
+			expected: "foo-bar",
+		},
+		{
+			name:     "Band name with triple hyphens",
+			bandName: "Foo---Bar",
+			expected: "foo-bar",
+		},
+		{
+			name:     "Complex band name",
+			bandName: "Foo & Bar  Baz",
+			expected: "foo-bar-baz",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := data.GenerateBandKey(tt.bandName)
+			if result != tt.expected {
+				t.Errorf("data.GenerateBandKey(%q) = %q, want %q", tt.bandName, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGeneratePRSummary(t *testing.T) {
+	tests := []struct {
+		name     string
+		stats    UpdateStats
+		contains []string
+	}{
+		{
+			name: "Summary with updates",
+			stats: UpdateStats{
+				TotalFestivals:   5,
+				UpdatedFestivals: 3,
+				NewBands:         10,
+				UpdatedPrices:    2,
+				TotalTokens:      3000,
+				TotalCost:        0.10,
+				UsedModel:        "gpt-4o-mini",
+			},
+			contains: []string{
+				"# 🤖 Automated Festival Information Update",
+				"**Total Festivals Processed**: 5",
+				"**Festivals Updated**: 3",
+				"**New Bands Added**: 10",
+				"**Ticket Prices Updated**: 2",
+				"**Total Tokens**: 3000",
+				"**Total Cost**: 0.10 €",
+				"gpt-4o-mini",
+				"`metal-fests update festivals`",
+				"*This PR was automatically generated. Please review the changes before merging.*",
+			},
+		},
+		{
+			name: "Summary with no updates",
+			stats: UpdateStats{
+				TotalFestivals:   5,
+				UpdatedFestivals: 0,
+				NewBands:         0,
+				UpdatedPrices:    0,
+				TotalTokens:      0,
+			},
+			contains: []string{
+				"# 🤖 Automated Festival Information Update",
+				"**Total Festivals Processed**: 5",
+				"**Festivals Updated**: 0",
+				"**New Bands Added**: 0",
+				"**Ticket Prices Updated**: 0",
+				"*No updates were needed. All festival information is up to date.*",
+			},
+		},
+		{
+			name: "Summary with only band updates",
+			stats: UpdateStats{
+				TotalFestivals:   3,
+				UpdatedFestivals: 2,
+				NewBands:         5,
+				UpdatedPrices:    0,
+				TotalTokens:      1500,
+			},
+			contains: []string{
+				"# 🤖 Automated Festival Information Update",
+				"**Total Festivals Processed**: 3",
+				"**Festivals Updated**: 2",
+				"**New Bands Added**: 5",
+				"**Ticket Prices Updated**: 0",
+			},
+		},
+		{
+			name: "Summary with only price updates",
+			stats: UpdateStats{
+				TotalFestivals:   3,
+				UpdatedFestivals: 1,
+				NewBands:         0,
+				UpdatedPrices:    1,
+				TotalTokens:      800,
+			},
+			contains: []string{
+				"# 🤖 Automated Festival Information Update",
+				"**Total Festivals Processed**: 3",
+				"**Festivals Updated**: 1",
+				"**New Bands Added**: 0",
+				"**Ticket Prices Updated**: 1",
+			},
+		},
+		{
+			name: "Summary with lineup removals and cancellations",
+			stats: UpdateStats{
+				TotalFestivals:   2,
+				UpdatedFestivals: 1,
+				RemovedBands:     1,
+				FlaggedBands:     1,
+				Items: []updater.ItemReport{
+					{Name: "Test Fest", Outcome: "updated", Changes: FestivalChange{
+						Name:              "Test Fest",
+						PossiblyCancelled: []string{"Slayer (missing 1 time(s))"},
+						RemovedBands:      []string{"Pantera"},
+						ReturnedBands:     []string{"Sepultura"},
+					}.reportChanges()},
+				},
+			},
+			contains: []string{
+				"**Bands Possibly Cancelled** (newly missing from a lineup): 1",
+				"**Bands Removed** (missing from too many updates): 1",
+				"- **Possibly Cancelled** (1):\n  - Slayer (missing 1 time(s))",
+				"- **Removed From Lineup** (1):\n  - Pantera",
+				"- **Back on the Lineup** (1):\n  - Sepultura",
+			},
+		},
+		{
+			name: "Summary with linked and ambiguous lineup names",
+			stats: UpdateStats{
+				TotalFestivals:   1,
+				UpdatedFestivals: 1,
+				LinkedBands:      1,
+				AmbiguousBands:   1,
+				Items: []updater.ItemReport{
+					{Name: "Test Fest", Outcome: "updated", Changes: FestivalChange{
+						Name:           "Test Fest",
+						LinkedBands:    []string{"Motley Crue → Mötley Crüe (1.00)"},
+						AmbiguousBands: []string{"Sodomy: Sodom (0.83), Sodoma (0.83)"},
+					}.reportChanges()},
+				},
+			},
+			contains: []string{
+				"**Lineup Names Linked to Existing Bands**: 1",
+				"**Ambiguous Lineup Names** (not added, need a human): 1",
+				"- **Linked to Existing Bands** (1):\n  - Motley Crue → Mötley Crüe (1.00)",
+				"- **Ambiguous Names** (1), add the right one by hand or as an alias:\n  - Sodomy: Sodom (0.83), Sodoma (0.83)",
+			},
+		},
+		{
+			name: "Summary with festival detail changes",
+			stats: UpdateStats{
+				TotalFestivals:   1,
+				UpdatedFestivals: 1,
+				FilledDetails:    1,
+				DetailChanges:    1,
+				Items: []updater.ItemReport{
+					{Name: "Test Fest", Outcome: "updated", Changes: FestivalChange{
+						Name:          "Test Fest",
+						FilledDetails: []DetailChange{{Field: "poster", New: "https://testfest.com/poster.jpg"}},
+						DetailChanges: []DetailChange{{Field: "dates", Old: "2026-06-04 – 2026-06-06", New: "2026-07-02 – 2026-07-04"}},
+					}.reportChanges()},
+				},
+			},
+			contains: []string{
+				"**Missing Festival Details Filled**: 1",
+				"**Festival Details Changed** (not applied, need a human): 1",
+				"- **Details Filled** (1):\n  - poster: https://testfest.com/poster.jpg",
+				"- **Details Changed** (1), not applied, update them by hand if confirmed:\n  - dates: 2026-06-04 – 2026-06-06 → 2026-07-02 – 2026-07-04",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			summary, err := generatePRSummary(buildReport(&tt.stats, time.Now(), false))
+			if err != nil {
+				t.Fatalf("generatePRSummary() failed: %v", err)
+			}
+			for _, substr := range tt.contains {
+				if !strings.Contains(summary, substr) {
+					t.Errorf("generatePRSummary() missing expected substring: %q", substr)
+				}
+			}
+		})
+	}
+}
+
+func TestBandHasBeenUpdated(t *testing.T) {
+	tests := []struct {
+		name     string
+		bands    []model.BandRef
+		band     model.BandRef
+		expected bool
+	}{
+		{
+			name: "Band size changed",
+			bands: []model.BandRef{
+				{Key: "metallica", Name: "Metallica", Size: 1},
+				{Key: "iron-maiden", Name: "Iron Maiden", Size: 2},
+			},
+			band:     model.BandRef{Key: "metallica", Name: "Metallica", Size: 3},
+			expected: true,
+		},
+		{
+			name: "Band name changed",
+			bands: []model.BandRef{
+				{Key: "metallica", Name: "Metallica", Size: 1},
+			},
+			band:     model.BandRef{Key: "metallica", Name: "Metallica!", Size: 1},
+			expected: true,
+		},
+		{
+			name: "Band unchanged",
+			bands: []model.BandRef{
+				{Key: "metallica", Name: "Metallica", Size: 1},
+			},
+			band:     model.BandRef{Key: "metallica", Name: "Metallica", Size: 1},
+			expected: false,
+		},
+		{
+			name: "Band not in list",
+			bands: []model.BandRef{
+				{Key: "metallica", Name: "Metallica", Size: 1},
+			},
+			band:     model.BandRef{Key: "slayer", Name: "Slayer", Size: 2},
+			expected: false,
+		},
+		{
+			name:     "Empty band list",
+			bands:    []model.BandRef{},
+			band:     model.BandRef{Key: "metallica", Name: "Metallica", Size: 1},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := bandHasBeenUpdated(tt.bands, tt.band)
+			if result != tt.expected {
+				t.Errorf("bandHasBeenUpdated() = %v, want %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestUpdateBandData(t *testing.T) {
+	tests := []struct {
+		name             string
+		festival         model.Festival
+		band             model.BandRef
+		expectedBandSize int
+		expectedBandName string
+	}{
+		{
+			name: "Update existing band size",
+			festival: model.Festival{
+				Key:  "wacken-2026",
+				Name: "Wacken 2026",
+				Bands: []model.BandRef{
+					{Key: "metallica", Name: "Metallica", Size: 1},
+					{Key: "iron-maiden", Name: "Iron Maiden", Size: 2},
+				},
+			},
+			band:             model.BandRef{Key: "metallica", Name: "Metallica", Size: 3},
+			expectedBandSize: 3,
+			expectedBandName: "Metallica",
+		},
+		{
+			name: "Update existing band name",
+			festival: model.Festival{
+				Key:  "wacken-2026",
+				Name: "Wacken 2026",
+				Bands: []model.BandRef{
+					{Key: "metallica", Name: "Metalica", Size: 1},
+				},
+			},
+			band:             model.BandRef{Key: "metallica", Name: "Metallica", Size: 1},
+			expectedBandSize: 1,
+			expectedBandName: "Metallica",
+		},
+		{
+			name: "Update band in middle of list",
+			festival: model.Festival{
+				Key:  "test-fest",
+				Name: "Test Fest",
+				Bands: []model.BandRef{
+					{Key: "band1", Name: "Band 1", Size: 1},
+					{Key: "band2", Name: "Band 2", Size: 2},
+					{Key: "band3", Name: "Band 3", Size: 3},
+				},
+			},
+			band:             model.BandRef{Key: "band2", Name: "Band Two", Size: 5},
+			expectedBandSize: 5,
+			expectedBandName: "Band Two",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := updateBandData(tt.festival, tt.band)
+
+			// Find the updated band in the result
+			found := false
+			for _, b := range result.Bands {
+				if b.Key == tt.band.Key {
+					found = true
+					if b.Size != tt.expectedBandSize {
+						t.Errorf("Band size = %v, want %v", b.Size, tt.expectedBandSize)
+					}
+					if b.Name != tt.expectedBandName {
+						t.Errorf("Band name = %v, want %v", b.Name, tt.expectedBandName)
+					}
+					break
+				}
+			}
+
+			if !found {
+				t.Errorf("Band with key %q not found in updated festival", tt.band.Key)
+			}
+		})
+	}
+}
+
+func TestFestivalUpdateSchema(t *testing.T) {
+	required := festivalUpdateSchema["required"].([]string)
+	if !reflect.DeepEqual(required, []string{"bands", "ticketPrice", "dates", "location", "website", "poster"}) {
+		t.Errorf("unexpected required properties: %v", required)
+	}
+
+	properties := festivalUpdateSchema["properties"].(map[string]any)
+	price := properties["ticketPrice"].(map[string]any)
+	if !reflect.DeepEqual(price["type"], []any{"number", "null"}) {
+		t.Errorf("ticketPrice should be a nullable number, got %v", price["type"])
+	}
+	dates := properties["dates"].(map[string]any)
+	if !reflect.DeepEqual(dates["type"], []any{"object", "null"}) {
+		t.Errorf("dates should be a nullable object, got %v", dates["type"])
+	}
+	for _, field := range []string{"location", "website", "poster"} {
+		property := properties[field].(map[string]any)
+		if !reflect.DeepEqual(property["type"], []any{"string", "null"}) {
+			t.Errorf("%s should be a nullable string, got %v", field, property["type"])
+		}
+	}
+
+	bands := properties["bands"].(map[string]any)
+	items := bands["items"].(map[string]any)
+	itemProperties := items["properties"].(map[string]any)
+	size := itemProperties["size"].(map[string]any)
+	if !reflect.DeepEqual(size["enum"], []any{1, 2, 3}) {
+		t.Errorf("size should be limited to tiers 1-3, got %v", size["enum"])
+	}
+	if _, exists := itemProperties["key"]; exists {
+		t.Errorf("the model should not be asked for band keys")
+	}
+}
+
+func TestEditionYear(t *testing.T) {
+	tests := []struct {
+		name     string
+		festival model.Festival
+		override int
+		expected int
+	}{
+		{
+			name:     "Override wins",
+			festival: model.Festival{Dates: model.Dates{Start: "2026-06-03", End: "2026-06-06"}},
+			override: 2027,
+			expected: 2027,
+		},
+		{
+			name:     "Year of the start date",
+			festival: model.Festival{Dates: model.Dates{Start: "2026-06-03", End: "2026-06-06"}},
+			expected: 2026,
+		},
+		{
+			name:     "Edition on its last day",
+			festival: model.Festival{Dates: model.Dates{Start: "2026-05-01", End: "2026-05-10"}},
+			expected: 2026,
+		},
+		{
+			name:     "Next edition once the stored one has passed",
+			festival: model.Festival{Dates: model.Dates{Start: "2026-04-30", End: "2026-05-03"}},
+			expected: 2027,
+		},
+		{
+			name:     "Passed edition without end date",
+			festival: model.Festival{Dates: model.Dates{Start: "2026-04-30"}},
+			expected: 2027,
+		},
+		{
+			name:     "Current year when the stored edition is years old",
+			festival: model.Festival{Dates: model.Dates{Start: "2023-06-03", End: "2023-06-06"}},
+			expected: 2026,
+		},
+		{
+			name:     "Current year without dates",
+			festival: model.Festival{},
+			expected: 2026,
+		},
+	}
+
+	// The runs happen on May 10th 2026
+	now := time.Date(2026, time.May, 10, 18, 0, 0, 0, time.UTC)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := editionYear(tt.festival, tt.override, now); result != tt.expected {
+				t.Errorf("editionYear() = %d, want %d", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyFestivalResult_RecordsProvenance(t *testing.T) {
+	price := 120.0
+	festival := model.Festival{
+		Key:        "test-fest",
+		Name:       "Test Fest",
+		Bands:      []model.BandRef{{Key: "iron-maiden", Name: "Iron Maiden", Size: 3}},
+		Provenance: model.ProvenanceMap{"website": {Origin: model.ProvenanceManual}},
+	}
+	result := &FestivalUpdateResult{
+		Bands:         []LineupBand{{Name: "Iron Maiden", Size: 3}, {Name: "Metallica", Size: 3}},
+		TicketPrice:   &price,
+		Model:         "gpt-5",
+		PromptVersion: "festival-v2",
+		Sources:       []model.Citation{{URL: "https://testfest.com/lineup"}},
+	}
+
+	outcome := applyFestivalResult(context.Background(), festival, result, nil, 0, festivalResult{})
+
+	provenance := outcome.festival.Provenance
+	if provenance["website"].Origin != model.ProvenanceManual {
+		t.Errorf("website provenance = %+v, want the manual one kept", provenance["website"])
+	}
+	for _, field := range []string{"bands", "ticketPrice"} {
+		if provenance[field].Origin != model.ProvenanceAI || provenance[field].PromptVersion != "festival-v2" {
+			t.Errorf("%s provenance = %+v, want ai from festival-v2", field, provenance[field])
+		}
+		if len(provenance[field].Sources) != 1 {
+			t.Errorf("%s sources = %v, want the cited page", field, provenance[field].Sources)
+		}
+	}
+	if len(festival.Provenance) != 1 {
+		t.Errorf("applyFestivalResult() modified the original provenance: %v", festival.Provenance)
+	}
+}
+
+func TestDiffDetails(t *testing.T) {
+	stored := model.Festival{
+		Name:     "Test Fest",
+		Dates:    model.Dates{Start: "2026-06-04", End: "2026-06-06"},
+		Location: "Clisson, France",
+		Website:  "https://www.testfest.com/",
+	}
+	text := func(value string) *string { return &value }
+
+	tests := []struct {
+		name     string
+		festival model.Festival
+		result   FestivalUpdateResult
+		filled   []string
+		changes  []DetailChange
+		expected model.Festival
+	}{
+		{
+			name:     "Nothing found",
+			festival: stored,
+			expected: stored,
+		},
+		{
+			name:     "Same values written differently",
+			festival: stored,
+			result: FestivalUpdateResult{
+				Dates:    &EditionDate{Start: "2026-06-04", End: "2026-06-06"},
+				Location: text("clisson, france"),
+				Website:  text("http://testfest.com"),
+			},
+			expected: stored,
+		},
+		{
+			name:     "Missing poster is filled",
+			festival: stored,
+			result:   FestivalUpdateResult{Poster: text("https://testfest.com/poster.jpg")},
+			filled:   []string{"poster"},
+			expected: func() model.Festival {
+				festival := stored
+				festival.Poster = "https://testfest.com/poster.jpg"
+				return festival
+			}(),
+		},
+		{
+			name:     "Postponed dates and moved venue are only reported",
+			festival: stored,
+			result: FestivalUpdateResult{
+				Dates:    &EditionDate{Start: "2026-07-02", End: "2026-07-04"},
+				Location: text("Nantes, France"),
+			},
+			changes: []DetailChange{
+				{Field: "dates", Old: "2026-06-04 – 2026-06-06", New: "2026-07-02 – 2026-07-04"},
+				{Field: "location", Old: "Clisson, France", New: "Nantes, France"},
+			},
+			expected: stored,
+		},
+		{
+			name:     "Invalid dates are ignored",
+			festival: stored,
+			result:   FestivalUpdateResult{Dates: &EditionDate{Start: "June 2026", End: ""}},
+			expected: stored,
+		},
+		{
+			name:     "One day festival without end date",
+			festival: model.Festival{Name: "Test Fest"},
+			result:   FestivalUpdateResult{Dates: &EditionDate{Start: "2026-09-12"}},
+			filled:   []string{"dates"},
+			expected: model.Festival{Name: "Test Fest", Dates: model.Dates{Start: "2026-09-12", End: "2026-09-12"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			festival := tt.festival
+			var change FestivalChange
+			filled := diffDetails(context.Background(), &festival, &tt.result, &change)
+
+			if !reflect.DeepEqual(filled, tt.filled) {
+				t.Errorf("diffDetails() filled = %v, want %v", filled, tt.filled)
+			}
+			if !reflect.DeepEqual(change.DetailChanges, tt.changes) {
+				t.Errorf("diffDetails() changes = %+v, want %+v", change.DetailChanges, tt.changes)
+			}
+			if !reflect.DeepEqual(festival, tt.expected) {
+				t.Errorf("diffDetails() festival = %+v, want %+v", festival, tt.expected)
+			}
+		})
+	}
+}
+
+func TestApplyFestivalResult_TracksPriceChanges(t *testing.T) {
+	priceSetAt := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	festival := model.Festival{
+		Key:         "test-fest",
+		Name:        "Test Fest",
+		TicketPrice: 150,
+		Provenance:  model.ProvenanceMap{"ticketPrice": {Origin: model.ProvenanceAI, UpdatedAt: priceSetAt}},
+	}
+
+	price := 150.0
+	outcome := applyFestivalResult(context.Background(), festival, &FestivalUpdateResult{TicketPrice: &price}, nil, 0, festivalResult{})
+	if outcome.updated || outcome.priceUpdated {
+		t.Errorf("applyFestivalResult() updated an unchanged price: %+v", outcome.festival)
+	}
+
+	price = 175.0
+	outcome = applyFestivalResult(context.Background(), festival, &FestivalUpdateResult{TicketPrice: &price}, nil, 0, festivalResult{})
+	if !outcome.priceUpdated || outcome.festival.TicketPrice != 175 {
+		t.Fatalf("applyFestivalResult() price = %v, want 175", outcome.festival.TicketPrice)
+	}
+	history := outcome.festival.PriceHistory
+	if len(history) != 2 || history[0].Price != 150 || !history[0].ObservedAt.Equal(priceSetAt) || history[1].Price != 175 {
+		t.Errorf("applyFestivalResult() history = %+v, want 150 from the provenance date then 175", history)
+	}
+	if outcome.change.OldPrice != 150 || outcome.change.NewPrice != 175 {
+		t.Errorf("applyFestivalResult() change = %v → %v, want 150 → 175", outcome.change.OldPrice, outcome.change.NewPrice)
+	}
+}
+
+func TestApplyFestivalResult_PriceWithoutProvenance(t *testing.T) {
+	// Festivals stored before provenance existed have no date for their price
+	festival := model.Festival{Key: "test-fest", Name: "Test Fest", TicketPrice: 150}
+
+	price := 175.0
+	outcome := applyFestivalResult(context.Background(), festival, &FestivalUpdateResult{TicketPrice: &price}, nil, 0, festivalResult{})
+	if !outcome.priceUpdated || outcome.festival.TicketPrice != 175 {
+		t.Fatalf("applyFestivalResult() price = %v, want 175", outcome.festival.TicketPrice)
+	}
+	history := outcome.festival.PriceHistory
+	if len(history) != 1 || history[0].Price != 175 || history[0].ObservedAt.IsZero() {
+		t.Errorf("applyFestivalResult() history = %+v, want only 175 with its date", history)
+	}
+	if outcome.change.OldPrice != 150 {
+		t.Errorf("applyFestivalResult() old price = %v, want 150", outcome.change.OldPrice)
+	}
+}
+
+func TestResolveLineup(t *testing.T) {
+	resolver := data.NewBandResolver(
+		[]model.Band{
+			{Key: "motley-crue", Name: "Mötley Crüe"},
+			{Key: "sodom", Name: "Sodom"},
+			{Key: "sodoma", Name: "Sodoma"},
+		},
+		[]model.BandRef{{Key: "sepultura", Name: "Sepultura"}},
+		data.DefaultLinkThreshold,
+	)
+	fetched := []LineupBand{
+		{Name: "Motley Crue", Size: 3},
+		{Name: "Sepulturra", Size: 2},
+		{Name: "Sodomy", Size: 1},
+		{Name: "Gojira", Size: 3},
+		{Name: "Sepultura", Size: 2},
+	}
+
+	var change FestivalChange
+	lineup, fetchedKeys := resolveLineup(fetched, resolver, &change)
+
+	expected := []model.BandRef{
+		{Key: "motley-crue", Name: "Mötley Crüe", Size: 3},
+		{Key: "sepultura", Name: "Sepultura", Size: 2},
+		{Key: "gojira", Name: "Gojira", Size: 3},
+	}
+	if !reflect.DeepEqual(lineup, expected) {
+		t.Errorf("resolveLineup() lineup = %+v, want %+v", lineup, expected)
+	}
+	for _, key := range []string{"motley-crue", "sepultura", "sodom", "sodoma", "gojira"} {
+		if !fetchedKeys[key] {
+			t.Errorf("resolveLineup() keys missing %q: %v", key, fetchedKeys)
+		}
+	}
+	if len(change.LinkedBands) != 2 {
+		t.Errorf("resolveLineup() linked = %v, want 2 names", change.LinkedBands)
+	}
+	if len(change.AmbiguousBands) != 1 || !strings.HasPrefix(change.AmbiguousBands[0], "Sodomy: ") {
+		t.Errorf("resolveLineup() ambiguous = %v, want Sodomy", change.AmbiguousBands)
+	}
+}
+
+func TestResolveLineup_WithoutResolver(t *testing.T) {
+	var change FestivalChange
+	lineup, _ := resolveLineup([]LineupBand{{Name: "Iron Maiden", Size: 3}}, nil, &change)
+
+	expected := []model.BandRef{{Key: "iron-maiden", Name: "Iron Maiden", Size: 3}}
+	if !reflect.DeepEqual(lineup, expected) {
+		t.Errorf("resolveLineup() lineup = %+v, want %+v", lineup, expected)
+	}
+	if len(change.LinkedBands) != 0 || len(change.AmbiguousBands) != 0 {
+		t.Errorf("resolveLineup() change = %+v, want no links", change)
+	}
+}
+
+func TestDiffLineup(t *testing.T) {
+	fetched := map[string]bool{"iron-maiden": true, "sepultura": true}
+
+	tests := []struct {
+		name        string
+		stored      []model.BandRef
+		removeAfter int
+		changed     bool
+		lineup      []model.BandRef
+		flagged     int
+		removed     int
+		returned    int
+	}{
+		{
+			name:    "Lineup unchanged",
+			stored:  []model.BandRef{{Key: "iron-maiden", Name: "Iron Maiden", Size: 3}},
+			changed: false,
+			lineup:  []model.BandRef{{Key: "iron-maiden", Name: "Iron Maiden", Size: 3}},
+		},
+		{
+			name:        "Missing band is flagged",
+			stored:      []model.BandRef{{Key: "iron-maiden", Name: "Iron Maiden", Size: 3}, {Key: "slayer", Name: "Slayer", Size: 3}},
+			removeAfter: 3,
+			changed:     true,
+			lineup: []model.BandRef{
+				{Key: "iron-maiden", Name: "Iron Maiden", Size: 3},
+				{Key: "slayer", Name: "Slayer", Size: 3, PossiblyCancelled: true, Misses: 1},
+			},
+			flagged: 1,
+		},
+		{
+			name:        "Band still missing is counted but not flagged again",
+			stored:      []model.BandRef{{Key: "slayer", Name: "Slayer", Size: 3, PossiblyCancelled: true, Misses: 1}},
+			removeAfter: 3,
+			changed:     true,
+			lineup:      []model.BandRef{{Key: "slayer", Name: "Slayer", Size: 3, PossiblyCancelled: true, Misses: 2}},
+		},
+		{
+			name:        "Band removed after enough misses",
+			stored:      []model.BandRef{{Key: "slayer", Name: "Slayer", Size: 3, PossiblyCancelled: true, Misses: 2}},
+			removeAfter: 3,
+			changed:     true,
+			lineup:      []model.BandRef{},
+			removed:     1,
+		},
+		{
+			name:        "Bands are never removed with removeAfter 0",
+			stored:      []model.BandRef{{Key: "slayer", Name: "Slayer", Size: 3, PossiblyCancelled: true, Misses: 9}},
+			removeAfter: 0,
+			changed:     true,
+			lineup:      []model.BandRef{{Key: "slayer", Name: "Slayer", Size: 3, PossiblyCancelled: true, Misses: 10}},
+		},
+		{
+			name:     "Band back on the lineup is cleared",
+			stored:   []model.BandRef{{Key: "sepultura", Name: "Sepultura", Size: 2, PossiblyCancelled: true, Misses: 1}},
+			changed:  true,
+			lineup:   []model.BandRef{{Key: "sepultura", Name: "Sepultura", Size: 2}},
+			returned: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			festival := model.Festival{Name: "Test Fest", Bands: tt.stored}
+			var change FestivalChange
+			var outcome festivalResult
+
+			changed := diffLineup(context.Background(), &festival, fetched, tt.removeAfter, &change, &outcome)
+
+			if changed != tt.changed {
+				t.Errorf("diffLineup() = %v, want %v", changed, tt.changed)
+			}
+			if !reflect.DeepEqual(festival.Bands, tt.lineup) {
+				t.Errorf("diffLineup() lineup = %+v, want %+v", festival.Bands, tt.lineup)
+			}
+			if outcome.flaggedBands != tt.flagged || outcome.removedBands != tt.removed || len(change.ReturnedBands) != tt.returned {
+				t.Errorf("diffLineup() flagged %d, removed %d, returned %d; want %d, %d, %d",
+					outcome.flaggedBands, outcome.removedBands, len(change.ReturnedBands), tt.flagged, tt.removed, tt.returned)
+			}
+		})
+	}
+}
+
+func TestCollectFestivalResult_ReportsItems(t *testing.T) {
+	stats := &UpdateStats{}
+	results := []festivalResult{
+		{key: "hellfest", name: "Hellfest", failure: "request timed out", tokens: 100},
+		{key: "copenhell", name: "Copenhell", change: FestivalChange{
+			Name:          "Copenhell",
+			DetailChanges: []DetailChange{{Field: "location", Old: "Copenhagen, Denmark", New: "Aarhus, Denmark"}},
+		}},
+		{key: "graspop", name: "Graspop"},
+	}
+	for _, result := range results {
+		collectFestivalResult(context.Background(), stats, result)
+	}
+
+	want := []struct{ outcome, err string }{{"failed", "request timed out"}, {"review", ""}, {"unchanged", ""}}
+	if len(stats.Items) != len(want) {
+		t.Fatalf("collectFestivalResult() recorded %d items, want %d", len(stats.Items), len(want))
+	}
+	for i, item := range stats.Items {
+		if item.Outcome != want[i].outcome || item.Error != want[i].err {
+			t.Errorf("item %d = %s/%q, want %s/%q", i, item.Outcome, item.Error, want[i].outcome, want[i].err)
+		}
+	}
+	if changes := stats.Items[1].Changes; len(changes) != 1 || changes[0].Kind != "changedDetail" || changes[0].New != "Aarhus, Denmark" {
+		t.Errorf("unexpected changes for the festival to review: %+v", changes)
+	}
+}
+
+func TestStoreFestivalResult_CountsWrittenChanges(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "db.json")
+	if err := os.WriteFile(dbPath, []byte(`{"bands":[],"festivals":[{"key":"hellfest","name":"Hellfest"}]}`), 0600); err != nil {
+		t.Fatalf("failed to create test db: %v", err)
+	}
+	oldPath := data.SetDBFilePathForTesting(dbPath)
+	t.Cleanup(func() { data.SetDBFilePathForTesting(oldPath) })
+
+	result := func(key string) festivalResult {
+		return festivalResult{
+			key:          key,
+			festival:     model.Festival{Key: key, Name: key},
+			change:       FestivalChange{FilledDetails: []DetailChange{{Field: "poster", New: "https://example.com/poster.jpg"}}},
+			updated:      true,
+			newBands:     2,
+			removedBands: 1,
+			flaggedBands: 1,
+			linkedBands:  3,
+			priceUpdated: true,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		wantStatus updater.ItemStatus
+		wantStats  UpdateStats
+	}{
+		{
+			name:       "Written",
+			key:        "hellfest",
+			wantStatus: updater.StatusProcessed,
+			wantStats:  UpdateStats{UpdatedFestivals: 1, NewBands: 2, RemovedBands: 1, FlaggedBands: 1, LinkedBands: 3, FilledDetails: 1, UpdatedPrices: 1},
+		},
+		{
+			// The festival is not in the database, so the write fails
+			name:       "Failed write",
+			key:        "wacken",
+			wantStatus: updater.StatusFailed,
+			wantStats:  UpdateStats{FailedFestivals: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stats := &UpdateStats{}
+			status, _ := storeFestivalResult(context.Background(), stats, result(tt.key))
+			if status != tt.wantStatus {
+				t.Errorf("storeFestivalResult() status = %v, want %v", status, tt.wantStatus)
+			}
+			if !reflect.DeepEqual(*stats, tt.wantStats) {
+				t.Errorf("storeFestivalResult() stats = %+v, want %+v", *stats, tt.wantStats)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package updater
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/neovasili/metal-fests/internal/logging"
+)
+
+// ItemContext returns ctx with the records logged about an item named after
+// it, e.g. band=gojira, and kept in log, when not nil, until the item is
+// collected
+func ItemContext(ctx context.Context, log *logging.Buffer, kind, key string) context.Context {
+	ctx = logging.WithAttrs(ctx, slog.String(kind, key))
+	if log != nil {
+		ctx = logging.WithBuffer(ctx, log)
+	}
+	return ctx
+}
@@ -0,0 +1,76 @@
+package updater
+
+import (
+	"context"
+
+	"github.com/neovasili/metal-fests/internal/logging"
+)
+
+// Run processes total items using at most concurrency workers.
+//
+// work is called concurrently, once per item, and receives a buffer for the
+// item's log records: the ones logged with a context from logging.WithBuffer.
+// collect is called from the calling goroutine in item order, so it is the
+// single place where results are written to the database and stats are
+// aggregated. Once collect returns, the item's records are flushed, which
+// keeps the logs readable regardless of the order in which workers finish.
+//
+// Once ctx is done no new items are started; items already in progress are
+// still collected. Run returns the number of items that were processed.
+func Run[T any](ctx context.Context, total, concurrency int, work func(index int, log *logging.Buffer) T, collect func(index int, result T, log *logging.Buffer)) int {
+	if total <= 0 {
+		return 0
+	}
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > total {
+		concurrency = total
+	}
+
+	results := make([]T, total)
+	buffers := make([]logging.Buffer, total)
+	skipped := make([]bool, total)
+	done := make([]chan struct{}, total)
+	for i := range done {
+		done[i] = make(chan struct{})
+	}
+
+	jobs := make(chan int)
+	go func() {
+		defer close(jobs)
+		for i := 0; i < total; i++ {
+			select {
+			case jobs <- i:
+			case <-ctx.Done():
+				// Release the collector for every item that will never start
+				for j := i; j < total; j++ {
+					skipped[j] = true
+					close(done[j])
+				}
+				return
+			}
+		}
+	}()
+
+	for w := 0; w < concurrency; w++ {
+		go func() {
+			for i := range jobs {
+				results[i] = work(i, &buffers[i])
+				close(done[i])
+			}
+		}()
+	}
+
+	processed := 0
+	for i := 0; i < total; i++ {
+		<-done[i]
+		if skipped[i] {
+			continue
+		}
+		collect(i, results[i], &buffers[i])
+		_ = buffers[i].Flush()
+		processed++
+	}
+	return processed
+}
@@ -0,0 +1,122 @@
+package updater
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/logging"
+)
+
+func TestRun_CollectsInOrder(t *testing.T) {
+	var out bytes.Buffer
+	logger, err := logging.New(&out, logging.FormatText, "info")
+	if err != nil {
+		t.Fatalf("logging.New() failed: %v", err)
+	}
+	collected := make([]int, 0)
+
+	processed := Run(context.Background(), 5, 3, func(index int, log *logging.Buffer) int {
+		// Finish later items first to make sure ordering does not depend on timing
+		time.Sleep(time.Duration(5-index) * time.Millisecond)
+		logger.InfoContext(ItemContext(context.Background(), log, "item", strconv.Itoa(index)), "work")
+		return index * 10
+	}, func(index int, result int, log *logging.Buffer) {
+		collected = append(collected, result)
+		logger.InfoContext(ItemContext(context.Background(), log, "item", strconv.Itoa(index)), "collect")
+	})
+
+	if processed != 5 {
+		t.Errorf("expected 5 processed items, got %d", processed)
+	}
+
+	expected := []int{0, 10, 20, 30, 40}
+	if len(collected) != len(expected) {
+		t.Fatalf("expected %d results, got %d", len(expected), len(collected))
+	}
+	for i := range expected {
+		if collected[i] != expected[i] {
+			t.Errorf("result %d = %d, want %d", i, collected[i], expected[i])
+		}
+	}
+
+	var want []string
+	for i := 0; i < 5; i++ {
+		want = append(want, fmt.Sprintf("msg=work item=%d", i), fmt.Sprintf("msg=collect item=%d", i))
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("logged %d lines, want %d:\n%s", len(lines), len(want), out.String())
+	}
+	for i := range want {
+		if !strings.HasSuffix(lines[i], want[i]) {
+			t.Errorf("unexpected log order, line %d = %q, want %q", i, lines[i], want[i])
+		}
+	}
+}
+
+func TestRun_BoundsConcurrency(t *testing.T) {
+	var running, maxRunning int32
+
+	Run(context.Background(), 20, 4, func(_ int, _ *logging.Buffer) struct{} {
+		current := atomic.AddInt32(&running, 1)
+		for {
+			previous := atomic.LoadInt32(&maxRunning)
+			if current <= previous || atomic.CompareAndSwapInt32(&maxRunning, previous, current) {
+				break
+			}
+		}
+		time.Sleep(2 * time.Millisecond)
+		atomic.AddInt32(&running, -1)
+		return struct{}{}
+	}, func(_ int, _ struct{}, _ *logging.Buffer) {})
+
+	if maxRunning > 4 {
+		t.Errorf("expected at most 4 concurrent workers, got %d", maxRunning)
+	}
+}
+
+func TestRun_NoItems(t *testing.T) {
+	called := false
+	Run(context.Background(), 0, 4, func(_ int, _ *logging.Buffer) int {
+		called = true
+		return 0
+	}, func(_ int, _ int, _ *logging.Buffer) {
+		called = true
+	})
+	if called {
+		t.Error("expected no calls for an empty run")
+	}
+}
+
+func TestRun_StopsDispatchingWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	collected := make([]int, 0)
+
+	processed := Run(ctx, 10, 1, func(index int, _ *logging.Buffer) int {
+		if index == 2 {
+			cancel()
+		}
+		return index
+	}, func(_ int, result int, _ *logging.Buffer) {
+		collected = append(collected, result)
+	})
+
+	// Items already handed to a worker are finished, nothing else is started
+	if processed >= 10 || processed < 3 {
+		t.Errorf("expected between 3 and 9 processed items, got %d", processed)
+	}
+	if len(collected) != processed {
+		t.Errorf("expected %d collected items, got %d", processed, len(collected))
+	}
+	for i, result := range collected {
+		if result != i {
+			t.Errorf("collected[%d] = %d, want %d", i, result, i)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package updater
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Report is the machine-readable outcome of an updater run. The markdown
+// summary is rendered from it, so follow-up automation sees exactly what
+// reviewers see.
+type Report struct {
+	Updater       string            `json:"updater"`
+	Source        string            `json:"source"`
+	RunURL        string            `json:"runUrl,omitempty"`
+	Parameters    map[string]string `json:"parameters,omitempty"`
+	DryRun        bool              `json:"dryRun"`
+	StartedAt     time.Time         `json:"startedAt"`
+	FinishedAt    time.Time         `json:"finishedAt"`
+	DurationMs    int64             `json:"durationMs"`
+	PromptVersion string            `json:"promptVersion"`
+	Model         string            `json:"model"`
+	Tokens        int               `json:"tokens"`
+	Cost          float64           `json:"cost"`
+	Total         int               `json:"total"`
+	Processed     int               `json:"processed"`
+	Resumed       int               `json:"resumed"`
+	Interrupted   bool              `json:"interrupted"`
+	Counts        map[string]int    `json:"counts"`
+	Items         []ItemReport      `json:"items"`
+}
+
+// ItemReport is what happened to a single item of the run
+type ItemReport struct {
+	Key        string     `json:"key"`
+	Name       string     `json:"name"`
+	Status     ItemStatus `json:"status"`
+	Outcome    string     `json:"outcome"`
+	Reason     string     `json:"reason,omitempty"`
+	Confidence float64    `json:"confidence,omitempty"`
+	Changes    []Change   `json:"changes,omitempty"`
+	Notes      []string   `json:"notes,omitempty"`
+	Record     any        `json:"record,omitempty"`
+	Model      string     `json:"model,omitempty"`
+	Tokens     int        `json:"tokens"`
+	Cost       float64    `json:"cost"`
+	Error      string     `json:"error,omitempty"`
+	DurationMs int64      `json:"durationMs"`
+}
+
+// Change is a single change made or proposed for an item. Kind tells what
+// happened, e.g. "newBand" or "refreshed"; each updater defines its own.
+type Change struct {
+	Kind  string `json:"kind"`
+	Field string `json:"field,omitempty"`
+	Old   string `json:"old,omitempty"`
+	New   string `json:"new,omitempty"`
+}
+
+// NewReport starts the report of a run
+func NewReport(updater string, startedAt time.Time) *Report {
+	source, runURL := RunSource()
+	return &Report{
+		Updater:   updater,
+		Source:    source,
+		RunURL:    runURL,
+		StartedAt: startedAt.UTC(),
+		Counts:    make(map[string]int),
+		Items:     make([]ItemReport, 0),
+	}
+}
+
+// Finish records the end of the run
+func (r *Report) Finish(finishedAt time.Time) {
+	r.FinishedAt = finishedAt.UTC()
+	r.DurationMs = r.FinishedAt.Sub(r.StartedAt).Milliseconds()
+}
+
+// WriteJSON saves the report to path
+func (r *Report) WriteJSON(path string) error {
+	content, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	content = append(content, '\n')
+	return os.WriteFile(path, content, 0600)
+}
+
+// Render executes a text template with the report as its data. Besides the
+// standard functions, the template can use the ones in reportFuncs.
+func (r *Report) Render(text string) (string, error) {
+	tmpl, err := template.New(r.Updater).Option("missingkey=error").Funcs(reportFuncs).Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, r); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// RunSource describes where the run happens: the GitHub Actions workflow and
+// a link to its run, or a local run
+func RunSource() (string, string) {
+	if os.Getenv("GITHUB_ACTIONS") != "true" {
+		return "Local run", ""
+	}
+
+	source := "GitHub Actions"
+	if workflow := os.Getenv("GITHUB_WORKFLOW"); workflow != "" {
+		source = fmt.Sprintf("GitHub Actions workflow %q", workflow)
+	}
+	server, repository, runID := os.Getenv("GITHUB_SERVER_URL"), os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID")
+	if server == "" || repository == "" || runID == "" {
+		return source, ""
+	}
+	return source, fmt.Sprintf("%s/%s/actions/runs/%s", server, repository, runID)
+}
+
+var reportFuncs = template.FuncMap{
+	"cell": MarkdownCell,
+	"date": func(t time.Time) string {
+		return t.UTC().Format("2006-01-02 15:04:05 UTC")
+	},
+	"join": func(values []string, separator string) string {
+		return strings.Join(values, separator)
+	},
+	// items keeps the items with one of the given outcomes
+	"items": func(items []ItemReport, outcomes ...string) []ItemReport {
+		var matching []ItemReport
+		for _, item := range items {
+			for _, outcome := range outcomes {
+				if item.Outcome == outcome {
+					matching = append(matching, item)
+					break
+				}
+			}
+		}
+		return matching
+	},
+	// changes keeps the changes of an item with one of the given kinds
+	"changes": func(item ItemReport, kinds ...string) []Change {
+		var matching []Change
+		for _, change := range item.Changes {
+			for _, kind := range kinds {
+				if change.Kind == kind {
+					matching = append(matching, change)
+					break
+				}
+			}
+		}
+		return matching
+	},
+	// hasChanges tells whether any item has a change of the given kind
+	"hasChanges": func(items []ItemReport, kind string) bool {
+		for _, item := range items {
+			for _, change := range item.Changes {
+				if change.Kind == kind {
+					return true
+				}
+			}
+		}
+		return false
+	},
+}
+
+// MarkdownCell shortens a value and escapes it for a markdown table cell
+func MarkdownCell(value string) string {
+	const maxLength = 80
+	value = strings.Join(strings.Fields(value), " ")
+	if runes := []rune(value); len(runes) > maxLength {
+		value = string(runes[:maxLength]) + "…"
+	}
+	return strings.ReplaceAll(value, "|", "\\|")
+}
@@ -0,0 +1,137 @@
+package updater
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReport_WriteJSON(t *testing.T) {
+	started := time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC)
+	report := NewReport("band_updater", started)
+	report.Counts["added"] = 1
+	report.Items = append(report.Items, ItemReport{
+		Key:        "metallica",
+		Name:       "Metallica",
+		Status:     StatusProcessed,
+		Outcome:    "added",
+		Changes:    []Change{{Kind: "refreshed", Field: "website", Old: "http://old", New: "https://new"}},
+		Tokens:     1200,
+		Cost:       0.02,
+		DurationMs: 3500,
+	})
+	report.Finish(started.Add(90 * time.Second))
+
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := report.WriteJSON(path); err != nil {
+		t.Fatalf("WriteJSON failed: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("failed to read report: %v", err)
+	}
+	var loaded Report
+	if err := json.Unmarshal(content, &loaded); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+	if loaded.DurationMs != 90000 {
+		t.Errorf("DurationMs = %d, want 90000", loaded.DurationMs)
+	}
+	if len(loaded.Items) != 1 || loaded.Items[0].Changes[0].New != "https://new" || loaded.Counts["added"] != 1 {
+		t.Errorf("unexpected report content: %+v", loaded)
+	}
+}
+
+func TestReport_Render(t *testing.T) {
+	report := NewReport("festival_updater", time.Date(2026, 5, 1, 6, 0, 0, 0, time.UTC))
+	report.Counts["updated"] = 2
+	report.Items = []ItemReport{
+		{Name: "Hellfest", Outcome: "updated", Changes: []Change{{Kind: "newBand", New: "Gojira"}, {Kind: "price", Old: "300.00", New: "329.00"}}},
+		{Name: "Wacken | Open Air", Outcome: "updated", Changes: []Change{{Kind: "newBand", New: "Sepultura"}}},
+		{Name: "Copenhell", Outcome: "unchanged", Notes: []string{"lineup not announced", "price unknown"}},
+	}
+
+	tests := []struct {
+		name     string
+		template string
+		expected string
+	}{
+		{
+			name:     "Counts and dates",
+			template: "{{ .Counts.updated }} at {{ date .StartedAt }}",
+			expected: "2 at 2026-05-01 06:00:00 UTC",
+		},
+		{
+			name:     "Items by outcome",
+			template: `{{ range items .Items "updated" }}{{ cell .Name }};{{ end }}`,
+			expected: `Hellfest;Wacken \| Open Air;`,
+		},
+		{
+			name:     "Changes by kind",
+			template: `{{ range .Items }}{{ range changes . "price" }}{{ .Old }} → {{ .New }}{{ end }}{{ end }}`,
+			expected: "300.00 → 329.00",
+		},
+		{
+			name:     "Any change of a kind",
+			template: `{{ hasChanges .Items "newBand" }} {{ hasChanges .Items "removedBand" }}`,
+			expected: "true false",
+		},
+		{
+			name:     "Join",
+			template: `{{ join (index .Items 2).Notes "; " }}`,
+			expected: "lineup not announced; price unknown",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := report.Render(tt.template)
+			if err != nil {
+				t.Fatalf("Render failed: %v", err)
+			}
+			if result != tt.expected {
+				t.Errorf("Render() = %q, want %q", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestReport_RenderMissingCount(t *testing.T) {
+	report := NewReport("band_updater", time.Now())
+	if _, err := report.Render("{{ .Counts.typo }}"); err == nil {
+		t.Error("Render() should fail on counts the updater does not set")
+	}
+}
+
+func TestRunSource(t *testing.T) {
+	t.Setenv("GITHUB_ACTIONS", "")
+	if source, runURL := RunSource(); source != "Local run" || runURL != "" {
+		t.Errorf("RunSource() = %q, %q, want a local run", source, runURL)
+	}
+
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("GITHUB_WORKFLOW", "Bands Information Update")
+	t.Setenv("GITHUB_SERVER_URL", "https://github.com")
+	t.Setenv("GITHUB_REPOSITORY", "neovasili/metal-fests")
+	t.Setenv("GITHUB_RUN_ID", "42")
+	source, runURL := RunSource()
+	if !strings.Contains(source, "Bands Information Update") {
+		t.Errorf("RunSource() source = %q, want the workflow name", source)
+	}
+	if runURL != "https://github.com/neovasili/metal-fests/actions/runs/42" {
+		t.Errorf("RunSource() run URL = %q", runURL)
+	}
+}
+
+func TestMarkdownCell(t *testing.T) {
+	if result := MarkdownCell("a | b\nc"); result != "a \\| b c" {
+		t.Errorf("MarkdownCell() = %q", result)
+	}
+	if result := MarkdownCell(strings.Repeat("x", 100)); len([]rune(result)) != 81 {
+		t.Errorf("MarkdownCell() should truncate long values, got %d runes", len([]rune(result)))
+	}
+}
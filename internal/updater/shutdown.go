@@ -0,0 +1,50 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ErrInterrupted is the cancellation cause when a run is stopped by a signal
+var ErrInterrupted = errors.New("interrupted by signal")
+
+// NotifyShutdown wires SIGINT and SIGTERM into two contexts derived from parent.
+//
+// stop is cancelled on the first signal, telling the run to stop picking up
+// new items while in-flight ones finish. abort is cancelled on the second
+// signal and abandons in-flight requests. Both are also cancelled when parent
+// is, e.g. when a whole-run deadline expires. release must be called once the
+// run is over.
+func NotifyShutdown(parent context.Context) (stop, abort context.Context, release func()) {
+	abort, cancelAbort := context.WithCancelCause(parent)
+	stop, cancelStop := context.WithCancelCause(abort)
+
+	signals := make(chan os.Signal, 2)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		select {
+		case <-signals:
+			slog.WarnContext(parent, "shutdown requested, finishing the items in progress (repeat to abort them)")
+			cancelStop(ErrInterrupted)
+		case <-abort.Done():
+			return
+		}
+		select {
+		case <-signals:
+			slog.WarnContext(parent, "aborting the items in progress")
+			cancelAbort(ErrInterrupted)
+		case <-abort.Done():
+		}
+	}()
+
+	release = func() {
+		signal.Stop(signals)
+		cancelAbort(context.Canceled)
+	}
+	return stop, abort, release
+}
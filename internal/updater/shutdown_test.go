@@ -0,0 +1,52 @@
+package updater
+
+import (
+	"context"
+	"errors"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestNotifyShutdown_TwoStageSignals(t *testing.T) {
+	stop, abort, release := NotifyShutdown(context.Background())
+	defer release()
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGINT); err != nil {
+		t.Fatalf("failed to send signal: %v", err)
+	}
+	select {
+	case <-stop.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected stop context to be cancelled after first signal")
+	}
+	if !errors.Is(context.Cause(stop), ErrInterrupted) {
+		t.Errorf("expected ErrInterrupted cause, got %v", context.Cause(stop))
+	}
+	if abort.Err() != nil {
+		t.Fatal("abort context should survive the first signal")
+	}
+
+	if err := syscall.Kill(syscall.Getpid(), syscall.SIGTERM); err != nil {
+		t.Fatalf("failed to send signal: %v", err)
+	}
+	select {
+	case <-abort.Done():
+	case <-time.After(time.Second):
+		t.Fatal("expected abort context to be cancelled after second signal")
+	}
+}
+
+func TestNotifyShutdown_ParentDeadline(t *testing.T) {
+	parent, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	stop, abort, release := NotifyShutdown(parent)
+	defer release()
+
+	<-abort.Done()
+	<-stop.Done()
+	if !errors.Is(context.Cause(stop), context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded cause, got %v", context.Cause(stop))
+	}
+}
@@ -0,0 +1,29 @@
+package updater
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/neovasili/metal-fests/internal/tracing"
+)
+
+// StartItem starts the span covering the update of a single item, from its
+// AI requests to its database write
+func StartItem(ctx context.Context, kind, key string) (context.Context, trace.Span) {
+	return tracing.Tracer().Start(ctx, "update "+kind, trace.WithAttributes(
+		attribute.String("metal_fests.item.kind", kind),
+		attribute.String("metal_fests.item.key", key),
+	))
+}
+
+// EndItem records the status of an item on its span and ends it
+func EndItem(span trace.Span, status ItemStatus, reason string) {
+	span.SetAttributes(attribute.String("metal_fests.item.status", string(status)))
+	if status == StatusFailed {
+		span.SetStatus(codes.Error, reason)
+	}
+	span.End()
+}
@@ -0,0 +1,249 @@
+package urlcheck
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"syscall"
+	"time"
+
+	"github.com/neovasili/metal-fests/internal/tracing"
+)
+
+// Limits of a Fetcher, when its Options leave them out
+const (
+	DefaultMaxRedirects     = 5
+	DefaultMaxResponseBytes = 1 << 20 // 1 MB
+)
+
+// DefaultSchemes are the URL schemes a Fetcher requests when its Options
+// list none
+var DefaultSchemes = []string{"http", "https"}
+
+// Errors of a Fetcher, to be matched with errors.Is
+var (
+	// ErrSchemeNotAllowed is returned for URLs whose scheme is not allowed
+	ErrSchemeNotAllowed = errors.New("scheme not allowed")
+	// ErrAddressNotAllowed is returned for hosts resolving to a loopback,
+	// private, link-local or reserved address
+	ErrAddressNotAllowed = errors.New("address not allowed")
+	// ErrTooManyRedirects is returned once the redirects exceed MaxRedirects
+	ErrTooManyRedirects = errors.New("too many redirects")
+	// ErrResponseTooLarge is returned by the body of responses larger than
+	// MaxResponseBytes
+	ErrResponseTooLarge = errors.New("response too large")
+)
+
+// reservedPrefixes are the ranges not reachable on the internet that the
+// netip.Addr methods don't tell apart
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // "this" network
+	netip.MustParsePrefix("100.64.0.0/10"),   // carrier-grade NAT, home of some cloud metadata services
+	netip.MustParsePrefix("192.0.0.0/24"),    // IETF protocol assignments
+	netip.MustParsePrefix("192.0.2.0/24"),    // documentation, TEST-NET-1
+	netip.MustParsePrefix("198.18.0.0/15"),   // benchmarking
+	netip.MustParsePrefix("198.51.100.0/24"), // documentation, TEST-NET-2
+	netip.MustParsePrefix("203.0.113.0/24"),  // documentation, TEST-NET-3
+	netip.MustParsePrefix("240.0.0.0/4"),     // reserved, and the broadcast address
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64, which could reach any IPv4 address
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // local NAT64
+	netip.MustParsePrefix("2002::/16"),       // 6to4, which could reach any IPv4 address
+	netip.MustParsePrefix("2001:db8::/32"),   // documentation
+	netip.MustParsePrefix("fec0::/10"),       // deprecated site-local
+	netip.MustParsePrefix("100::/64"),        // discard-only
+}
+
+// Options configures a Fetcher
+type Options struct {
+	// Timeout bounds a request, redirects included, DefaultTimeout when 0
+	Timeout time.Duration
+	// MaxRedirects is how many redirects are followed, DefaultMaxRedirects
+	// when 0
+	MaxRedirects int
+	// MaxResponseBytes bounds the body read from a response,
+	// DefaultMaxResponseBytes when 0
+	MaxResponseBytes int64
+	// Schemes lists the URL schemes allowed, DefaultSchemes when empty
+	Schemes []string
+	// AllowLoopback lets requests reach the loopback addresses, for tests
+	// against httptest servers. The other internal addresses stay blocked.
+	AllowLoopback bool
+	// Resolver looks up the hosts, net.DefaultResolver when nil
+	Resolver *net.Resolver
+}
+
+// Fetcher requests URLs given by users or found by the AI without letting
+// them reach the network of the server: only hosts on the internet are
+// requested, and redirects are checked the same way. The addresses are
+// checked again when connecting, so a host resolving to another address the
+// second time is still blocked.
+type Fetcher struct {
+	opts   Options
+	client *http.Client
+}
+
+// NewFetcher returns a fetcher with opts
+func NewFetcher(opts Options) *Fetcher {
+	if opts.Timeout == 0 {
+		opts.Timeout = DefaultTimeout
+	}
+	if opts.MaxRedirects == 0 {
+		opts.MaxRedirects = DefaultMaxRedirects
+	}
+	if opts.MaxResponseBytes == 0 {
+		opts.MaxResponseBytes = DefaultMaxResponseBytes
+	}
+	if len(opts.Schemes) == 0 {
+		opts.Schemes = DefaultSchemes
+	}
+	if opts.Resolver == nil {
+		opts.Resolver = net.DefaultResolver
+	}
+
+	f := &Fetcher{opts: opts}
+	dialer := &net.Dialer{
+		Timeout:   opts.Timeout,
+		KeepAlive: 30 * time.Second,
+		Resolver:  opts.Resolver,
+		Control:   f.controlDial,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	// Through a proxy, the address dialed would be the proxy's
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	f.client = &http.Client{
+		Timeout:       opts.Timeout,
+		Transport:     tracing.Transport(transport),
+		CheckRedirect: f.checkRedirect,
+	}
+	return f
+}
+
+// Get requests rawURL
+func (f *Fetcher) Get(ctx context.Context, rawURL string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	return f.Do(req)
+}
+
+// Do sends req once its URL is checked. Reading more than MaxResponseBytes
+// of the response body fails with ErrResponseTooLarge.
+func (f *Fetcher) Do(req *http.Request) (*http.Response, error) {
+	if err := f.checkURL(req.Context(), req.URL); err != nil {
+		return nil, err
+	}
+	resp, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: f.opts.MaxResponseBytes}
+	return resp, nil
+}
+
+func (f *Fetcher) checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) > f.opts.MaxRedirects {
+		return fmt.Errorf("%w: stopped after %d", ErrTooManyRedirects, f.opts.MaxRedirects)
+	}
+	return f.checkURL(req.Context(), req.URL)
+}
+
+// checkURL rejects the URLs with a scheme not allowed, or whose host
+// resolves to an address not allowed
+func (f *Fetcher) checkURL(ctx context.Context, u *url.URL) error {
+	if !slices.Contains(f.opts.Schemes, u.Scheme) {
+		return fmt.Errorf("%w: %q", ErrSchemeNotAllowed, u.Scheme)
+	}
+	host := u.Hostname()
+	if host == "" {
+		return fmt.Errorf("URL %q has no host", u.Redacted())
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return f.checkAddr(addr)
+	}
+
+	addrs, err := f.opts.Resolver.LookupNetIP(ctx, "ip", host)
+	if err != nil {
+		return fmt.Errorf("failed to resolve %s: %w", host, err)
+	}
+	// Any of the addresses may be dialed, so all of them must be allowed
+	for _, addr := range addrs {
+		if err := f.checkAddr(addr); err != nil {
+			return fmt.Errorf("%s: %w", host, err)
+		}
+	}
+	return nil
+}
+
+// controlDial checks the address about to be dialed, the one the host
+// resolved to this time
+func (f *Fetcher) controlDial(_, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %q is not an IP address", ErrAddressNotAllowed, address)
+	}
+	return f.checkAddr(addrPort.Addr())
+}
+
+// checkAddr rejects the addresses not on the internet
+func (f *Fetcher) checkAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+	var kind string
+	switch {
+	case addr.IsLoopback():
+		if f.opts.AllowLoopback {
+			return nil
+		}
+		kind = "a loopback"
+	case addr.IsPrivate():
+		kind = "a private"
+	case addr.IsLinkLocalUnicast(), addr.IsLinkLocalMulticast():
+		kind = "a link-local"
+	case addr.IsUnspecified():
+		kind = "an unspecified"
+	case addr.IsMulticast():
+		kind = "a multicast"
+	default:
+		for _, prefix := range reservedPrefixes {
+			if prefix.Contains(addr) {
+				kind = "a reserved"
+				break
+			}
+		}
+	}
+	if kind != "" {
+		return fmt.Errorf("%w: %s is %s address", ErrAddressNotAllowed, addr, kind)
+	}
+	return nil
+}
+
+// limitedBody fails the reads going past the size cap of the responses
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining <= 0 {
+		// A body of exactly the cap is fine, one more byte is not
+		var probe [1]byte
+		n, err := b.ReadCloser.Read(probe[:])
+		if n > 0 {
+			return 0, ErrResponseTooLarge
+		}
+		return 0, err
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err := b.ReadCloser.Read(p)
+	b.remaining -= int64(n)
+	return n, err
+}
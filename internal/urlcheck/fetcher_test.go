@@ -0,0 +1,161 @@
+package urlcheck
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+)
+
+func TestFetcher_BlockedURLs(t *testing.T) {
+	fetcher := NewFetcher(Options{})
+
+	tests := []struct {
+		name string
+		url  string
+		want error
+	}{
+		{name: "Loopback", url: "http://127.0.0.1:8000/api/admin/stats", want: ErrAddressNotAllowed},
+		{name: "Localhost", url: "http://localhost/", want: ErrAddressNotAllowed},
+		{name: "IPv6 loopback", url: "http://[::1]/", want: ErrAddressNotAllowed},
+		{name: "IPv4-mapped loopback", url: "http://[::ffff:127.0.0.1]/", want: ErrAddressNotAllowed},
+		{name: "Private", url: "http://10.0.0.1/", want: ErrAddressNotAllowed},
+		{name: "IPv6 unique local", url: "http://[fd00::1]/", want: ErrAddressNotAllowed},
+		{name: "Metadata service", url: "http://169.254.169.254/latest/meta-data/", want: ErrAddressNotAllowed},
+		{name: "Carrier-grade NAT", url: "http://100.100.100.200/", want: ErrAddressNotAllowed},
+		{name: "Unspecified", url: "http://0.0.0.0:8000/", want: ErrAddressNotAllowed},
+		{name: "File", url: "file:///etc/passwd", want: ErrSchemeNotAllowed},
+		{name: "FTP", url: "ftp://example.com/", want: ErrSchemeNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := fetcher.Get(context.Background(), tt.url)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Get() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetcher_CheckAddr(t *testing.T) {
+	tests := []struct {
+		addr          string
+		allowLoopback bool
+		wantErr       bool
+	}{
+		{addr: "93.184.215.14"},
+		{addr: "2606:2800:21f:cb07:6820:80da:af6b:8b2c"},
+		{addr: "127.0.0.1", wantErr: true},
+		{addr: "127.0.0.1", allowLoopback: true},
+		{addr: "::1", allowLoopback: true},
+		{addr: "10.0.0.1", allowLoopback: true, wantErr: true},
+		{addr: "172.16.5.4", wantErr: true},
+		{addr: "192.168.1.1", wantErr: true},
+		{addr: "169.254.169.254", allowLoopback: true, wantErr: true},
+		{addr: "fe80::1", wantErr: true},
+		{addr: "224.0.0.1", wantErr: true},
+		{addr: "255.255.255.255", wantErr: true},
+		{addr: "64:ff9b::a00:1", wantErr: true},
+		{addr: "192.0.2.10", wantErr: true},
+		{addr: "198.18.0.1", wantErr: true},
+		{addr: "198.51.100.7", wantErr: true},
+		{addr: "203.0.113.42", wantErr: true},
+		{addr: "2001:db8::1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		f := NewFetcher(Options{AllowLoopback: tt.allowLoopback})
+		err := f.checkAddr(netip.MustParseAddr(tt.addr))
+		if (err != nil) != tt.wantErr {
+			t.Errorf("checkAddr(%s) with loopback allowed %v: error = %v, want error %v", tt.addr, tt.allowLoopback, err, tt.wantErr)
+		}
+	}
+}
+
+func TestFetcher_ControlDial(t *testing.T) {
+	// The address dialed is checked again, whatever the host resolved to before
+	f := NewFetcher(Options{AllowLoopback: true})
+	if err := f.controlDial("tcp4", "10.1.2.3:443", nil); !errors.Is(err, ErrAddressNotAllowed) {
+		t.Errorf("controlDial() error = %v, want %v", err, ErrAddressNotAllowed)
+	}
+	if err := f.controlDial("tcp6", "[::1]:443", nil); err != nil {
+		t.Errorf("controlDial() error = %v, want none", err)
+	}
+}
+
+func TestFetcher_Redirects(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			w.WriteHeader(http.StatusOK)
+		case "/twice":
+			http.Redirect(w, r, "/once", http.StatusFound)
+		case "/once":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		case "/loop":
+			http.Redirect(w, r, "/loop", http.StatusFound)
+		case "/metadata":
+			http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+		case "/file":
+			http.Redirect(w, r, "file:///etc/passwd", http.StatusFound)
+		}
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(Options{MaxRedirects: 2, AllowLoopback: true})
+
+	tests := []struct {
+		name string
+		path string
+		want error
+	}{
+		{name: "Within the cap", path: "/twice"},
+		{name: "Redirect loop", path: "/loop", want: ErrTooManyRedirects},
+		{name: "To the metadata service", path: "/metadata", want: ErrAddressNotAllowed},
+		{name: "To a file", path: "/file", want: ErrSchemeNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := fetcher.Get(context.Background(), server.URL+tt.path)
+			if err == nil {
+				_ = resp.Body.Close()
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("Get() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetcher_MaxResponseBytes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, strings.Repeat("x", len(r.URL.Path)-1))
+	}))
+	defer server.Close()
+
+	fetcher := NewFetcher(Options{MaxResponseBytes: 8, AllowLoopback: true})
+
+	for path, want := range map[string]error{"/12345678": nil, "/123456789": ErrResponseTooLarge} {
+		resp, err := fetcher.Get(context.Background(), server.URL+path)
+		if err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+		body, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		if !errors.Is(err, want) {
+			t.Errorf("reading %d bytes: error = %v, want %v", len(path)-1, err, want)
+		}
+		if want == nil && len(body) != 8 {
+			t.Errorf("read %d bytes, want 8", len(body))
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package urlcheck
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+
+	"github.com/neovasili/metal-fests/internal/model"
+	"github.com/neovasili/metal-fests/internal/tracing"
+)
+
+// DefaultTimeout bounds a single URL check, redirects included
+const DefaultTimeout = 5 * time.Second
+
+const userAgent = "Mozilla/5.0 (compatible; URLValidator/1.0)"
+
+// Checker tells whether a URL resolves to a successful response
+type Checker struct {
+	fetcher *Fetcher
+}
+
+// NewChecker returns a checker requesting the URLs through a Fetcher with
+// opts, so the URLs cannot reach the network of the server
+func NewChecker(opts Options) *Checker {
+	return &Checker{fetcher: NewFetcher(opts)}
+}
+
+// Check requests the URL and reports whether it answered with a 2xx or 3xx status
+func (c *Checker) Check(ctx context.Context, rawURL string) (response model.ValidateURLResponse) {
+	// Every redirect followed gets a span of its own under this one
+	ctx, span := tracing.Tracer().Start(ctx, "urlcheck.Check")
+	defer func() {
+		span.SetAttributes(attribute.Bool("metal_fests.url.valid", response.Valid))
+		if response.Status != 0 {
+			span.SetAttributes(semconv.HTTPResponseStatusCode(response.Status))
+		}
+		span.End()
+	}()
+
+	response = model.ValidateURLResponse{URL: rawURL}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		response.Error = fmt.Sprintf("Invalid URL: %v", err)
+		return response
+	}
+	httpReq.Header.Set("User-Agent", userAgent)
+
+	resp, err := c.fetcher.Do(httpReq)
+	if err != nil {
+		response.Error = err.Error()
+		return response
+	}
+	defer func() {
+		if err := resp.Body.Close(); err != nil {
+			slog.WarnContext(ctx, "failed to close response body", "url", rawURL, "error", err)
+		}
+	}()
+
+	response.Status = resp.StatusCode
+	response.Valid = resp.StatusCode >= 200 && resp.StatusCode < 400
+	return response
+}
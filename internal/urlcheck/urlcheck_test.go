@@ -0,0 +1,61 @@
+package urlcheck
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestCheck(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/ok":
+			if !strings.Contains(r.UserAgent(), "URLValidator") {
+				t.Errorf("unexpected User-Agent %q", r.UserAgent())
+			}
+			w.WriteHeader(http.StatusOK)
+		case "/moved":
+			http.Redirect(w, r, "/ok", http.StatusFound)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name      string
+		url       string
+		wantValid bool
+		status    int
+		wantError bool
+	}{
+		{name: "Successful response", url: server.URL + "/ok", wantValid: true, status: http.StatusOK},
+		{name: "Redirects are followed", url: server.URL + "/moved", wantValid: true, status: http.StatusOK},
+		{name: "Not found", url: server.URL + "/missing", wantValid: false, status: http.StatusNotFound},
+		{name: "Invalid URL", url: "http://[::1", wantValid: false, wantError: true},
+		{name: "Unreachable host", url: "http://127.0.0.1:1", wantValid: false, wantError: true},
+		{name: "Blocked address", url: "http://169.254.169.254/latest/meta-data/", wantValid: false, wantError: true},
+	}
+
+	checker := NewChecker(Options{Timeout: 2 * time.Second, AllowLoopback: true})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := checker.Check(context.Background(), tt.url)
+			if result.Valid != tt.wantValid {
+				t.Errorf("Check() valid = %v, want %v (%+v)", result.Valid, tt.wantValid, result)
+			}
+			if result.Status != tt.status {
+				t.Errorf("Check() status = %d, want %d", result.Status, tt.status)
+			}
+			if (result.Error != "") != tt.wantError {
+				t.Errorf("Check() error = %q, want error %v", result.Error, tt.wantError)
+			}
+			if result.URL != tt.url {
+				t.Errorf("Check() url = %q, want %q", result.URL, tt.url)
+			}
+		})
+	}
+}
@@ -0,0 +1,497 @@
+package validate
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	modelData "github.com/neovasili/metal-fests/internal/data"
+	"github.com/neovasili/metal-fests/internal/model"
+)
+
+// ANSI color codes for terminal output
+const (
+	ColorHeader    = "\033[95m"
+	ColorBlue      = "\033[94m"
+	ColorCyan      = "\033[96m"
+	ColorGreen     = "\033[92m"
+	ColorYellow    = "\033[93m"
+	ColorRed       = "\033[91m"
+	ColorBold      = "\033[1m"
+	ColorUnderline = "\033[4m"
+	ColorEnd       = "\033[0m"
+)
+
+// ValidationResult tracks errors and warnings
+type ValidationResult struct {
+	Errors   int
+	Warnings int
+}
+
+// Issue is a problem found in the database
+type Issue struct {
+	Check    string `json:"check"`
+	Severity string `json:"severity"`
+	Message  string `json:"message"`
+}
+
+// Result is the outcome of a validation run
+type Result struct {
+	Errors   int     `json:"errors"`
+	Warnings int     `json:"warnings"`
+	Fixed    bool    `json:"fixed"`
+	Issues   []Issue `json:"issues"`
+}
+
+// Options configures a validation run
+type Options struct {
+	// Fix corrects the formatting issues and saves the database
+	Fix bool
+	// HideWarnings leaves the warning details out of the output
+	HideWarnings bool
+	// Color adds ANSI colors to the output
+	Color bool
+	// Out receives the validation output
+	Out io.Writer
+}
+
+// printer writes the validation output and records the issues found
+type printer struct {
+	out    io.Writer
+	color  bool
+	check  string
+	issues []Issue
+}
+
+// Helper functions for colorized output
+func colorize(text, color string) string {
+	return fmt.Sprintf("%s%s%s", color, text, ColorEnd)
+}
+
+func (p *printer) paint(text, color string) string {
+	if !p.color {
+		return text
+	}
+	return colorize(text, color)
+}
+
+func (p *printer) printHeader(text string) {
+	p.check = text
+	_, _ = fmt.Fprintf(p.out, "\n%s\n", p.paint(strings.Repeat("═", 80), ColorBold))
+	_, _ = fmt.Fprintf(p.out, "%s\n", p.paint(fmt.Sprintf("  %s", text), ColorBold+ColorCyan))
+	_, _ = fmt.Fprintf(p.out, "%s\n\n", p.paint(strings.Repeat("═", 80), ColorBold))
+}
+
+func (p *printer) printSuccess(text string) {
+	_, _ = fmt.Fprintf(p.out, "%s %s\n", p.paint("✓", ColorGreen), text)
+}
+
+func (p *printer) printWarning(text string) {
+	p.record("warning", text)
+	_, _ = fmt.Fprintf(p.out, "%s %s\n", p.paint("⚠", ColorYellow), p.paint(text, ColorYellow))
+}
+
+func (p *printer) printError(text string) {
+	p.record("error", text)
+	_, _ = fmt.Fprintf(p.out, "%s %s\n", p.paint("✗", ColorRed), p.paint(text, ColorRed))
+}
+
+func (p *printer) printInfo(text string) {
+	_, _ = fmt.Fprintf(p.out, "%s %s\n", p.paint("i", ColorBlue), text)
+}
+
+// record keeps an issue of the current check
+func (p *printer) record(severity, text string) {
+	p.issues = append(p.issues, Issue{Check: p.check, Severity: severity, Message: strings.TrimSpace(text)})
+}
+
+// isProperlyCapitalized checks if text matches cases.Title capitalization
+func isProperlyCapitalized(text string) bool {
+	if text == "" {
+		return false
+	}
+
+	// Use the same capitalization as the band updater
+	expected := modelData.NormalizeBandName(text)
+	return text == expected
+}
+
+// validateJSONStructure validates that the file is valid JSON and loads it
+func (p *printer) validateJSONStructure(filePath string) (*model.Database, error) {
+	p.printHeader("JSON STRUCTURE VALIDATION")
+
+	// #nosec G304 - filePath comes from validated command-line arguments
+	file, err := os.ReadFile(filePath)
+	if err != nil {
+		p.printError(fmt.Sprintf("Error reading file: %v", err))
+		return nil, err
+	}
+
+	var data model.Database
+	if err := json.Unmarshal(file, &data); err != nil {
+		p.printError(fmt.Sprintf("Invalid JSON: %v", err))
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	p.printSuccess("Valid JSON structure")
+	return &data, nil
+}
+
+// validateBandNames validates band names in both festivals and bands sections
+func (p *printer) validateBandNames(data *model.Database, fix bool) ValidationResult {
+	p.printHeader("BAND NAME CAPITALIZATION")
+
+	result := ValidationResult{}
+	fixed := 0
+
+	// Check band names in festivals
+	p.printInfo("Checking band names in festivals...")
+	for i, festival := range data.Festivals {
+		for j, band := range festival.Bands {
+			expected := modelData.NormalizeBandName(band.Name)
+			if band.Name != expected {
+				if fix {
+					p.printInfo(fmt.Sprintf("  Festival '%s', Band: Fixing '%s' → '%s'", festival.Name, band.Name, expected))
+					data.Festivals[i].Bands[j].Name = expected
+					fixed++
+				} else {
+					p.printError(fmt.Sprintf("  Festival '%s', Band: '%s' not properly capitalized (should be '%s')", festival.Name, band.Name, expected))
+					result.Errors++
+				}
+			}
+		}
+	}
+
+	// Check band names in bands section
+	p.printInfo("Checking band names in bands section...")
+	for i, band := range data.Bands {
+		expected := modelData.NormalizeBandName(band.Name)
+		if band.Name != expected {
+			if fix {
+				p.printInfo(fmt.Sprintf("  Band: Fixing '%s' → '%s'", band.Name, expected))
+				data.Bands[i].Name = expected
+				fixed++
+			} else {
+				p.printError(fmt.Sprintf("  Band: '%s' not properly capitalized (should be '%s')", band.Name, expected))
+				result.Errors++
+			}
+		}
+	}
+
+	if fix && fixed > 0 {
+		p.printSuccess(fmt.Sprintf("Fixed %d band name(s)", fixed))
+	} else if result.Errors == 0 {
+		p.printSuccess("All band names are properly capitalized")
+	}
+
+	return result
+}
+
+// validateGenres validates music genre capitalization
+func (p *printer) validateGenres(data *model.Database, fix bool) ValidationResult {
+	p.printHeader("MUSIC GENRE CAPITALIZATION")
+
+	result := ValidationResult{}
+	totalGenres := 0
+	fixed := 0
+
+	for i, band := range data.Bands {
+		for j, genre := range band.Genres {
+			totalGenres++
+			if !isProperlyCapitalized(genre) {
+				expected := modelData.NormalizeBandName(genre)
+				if fix {
+					p.printInfo(fmt.Sprintf("  Band '%s': Fixing genre '%s' → '%s'", band.Name, genre, expected))
+					data.Bands[i].Genres[j] = expected
+					fixed++
+				} else {
+					p.printError(fmt.Sprintf("  Band '%s': Genre '%s' not properly capitalized (should be '%s')", band.Name, genre, expected))
+					result.Errors++
+				}
+			}
+		}
+	}
+
+	if fix && fixed > 0 {
+		p.printSuccess(fmt.Sprintf("Fixed %d genre(s)", fixed))
+	} else if result.Errors == 0 {
+		p.printSuccess(fmt.Sprintf("All %d genre entries are properly capitalized", totalGenres))
+	}
+
+	return result
+}
+
+// validateMemberRoles validates band member role capitalization
+func (p *printer) validateMemberRoles(data *model.Database, fix bool) ValidationResult {
+	p.printHeader("BAND MEMBER ROLE CAPITALIZATION")
+
+	result := ValidationResult{}
+	totalMembers := 0
+	fixed := 0
+
+	for i, band := range data.Bands {
+		for j, member := range band.Members {
+			totalMembers++
+			if !isProperlyCapitalized(member.Role) {
+				expected := modelData.NormalizeBandName(member.Role)
+				if fix {
+					p.printInfo(fmt.Sprintf("  Band '%s', Member '%s': Fixing role '%s' → '%s'", band.Name, member.Name, member.Role, expected))
+					data.Bands[i].Members[j].Role = expected
+					fixed++
+				} else {
+					p.printError(fmt.Sprintf("  Band '%s', Member '%s': Role '%s' not properly capitalized (should be '%s')", band.Name, member.Name, member.Role, expected))
+					result.Errors++
+				}
+			}
+		}
+	}
+
+	if fix && fixed > 0 {
+		p.printSuccess(fmt.Sprintf("Fixed %d member role(s)", fixed))
+	} else if result.Errors == 0 {
+		p.printSuccess(fmt.Sprintf("All %d member roles are properly capitalized", totalMembers))
+	}
+
+	return result
+}
+
+// validateBandKeys validates that band keys are compliant with the generateBandKey format
+func (p *printer) validateBandKeys(data *model.Database, fix bool) ValidationResult {
+	p.printHeader("BAND KEY COMPLIANCE")
+
+	result := ValidationResult{}
+	fixed := 0
+
+	// Check band keys in festivals
+	p.printInfo("Checking band keys in festivals...")
+	for i, festival := range data.Festivals {
+		for j, band := range festival.Bands {
+			expected := modelData.GenerateBandKey(band.Name)
+			if band.Key != expected {
+				if fix {
+					p.printInfo(fmt.Sprintf("  Festival '%s', Band '%s': Fixing key '%s' → '%s'", festival.Name, band.Name, band.Key, expected))
+					data.Festivals[i].Bands[j].Key = expected
+					fixed++
+				} else {
+					p.printError(fmt.Sprintf("  Festival '%s', Band '%s': Key '%s' not compliant (should be '%s')", festival.Name, band.Name, band.Key, expected))
+					result.Errors++
+				}
+			}
+		}
+	}
+
+	// Check band keys in bands section
+	p.printInfo("Checking band keys in bands section...")
+	for i, band := range data.Bands {
+		expected := modelData.GenerateBandKey(band.Name)
+		if band.Key != expected {
+			if fix {
+				p.printInfo(fmt.Sprintf("  Band '%s': Fixing key '%s' → '%s'", band.Name, band.Key, expected))
+				data.Bands[i].Key = expected
+				fixed++
+			} else {
+				p.printError(fmt.Sprintf("  Band '%s': Key '%s' not compliant (should be '%s')", band.Name, band.Key, expected))
+				result.Errors++
+			}
+		}
+	}
+
+	if fix && fixed > 0 {
+		p.printSuccess(fmt.Sprintf("Fixed %d band key(s)", fixed))
+	} else if result.Errors == 0 {
+		p.printSuccess("All band keys are compliant")
+	}
+
+	return result
+}
+
+// detectDuplicates detects potential duplicate band names using Levenshtein distance
+func (p *printer) detectDuplicates(data *model.Database, threshold int, hideWarnings bool) ValidationResult {
+	p.printHeader("DUPLICATE DETECTION (Levenshtein Distance)")
+
+	result := ValidationResult{}
+
+	// Collect all band names from both sections
+	type BandEntry struct {
+		Name   string
+		Source string
+	}
+	var allBands []BandEntry
+
+	// From festivals
+	for _, festival := range data.Festivals {
+		for _, bandRef := range festival.Bands {
+			allBands = append(allBands, BandEntry{
+				Name:   bandRef.Name,
+				Source: fmt.Sprintf("Festival: %s", festival.Name),
+			})
+		}
+	}
+
+	// From bands section
+	for _, band := range data.Bands {
+		allBands = append(allBands, BandEntry{
+			Name:   band.Name,
+			Source: "Bands section",
+		})
+	}
+
+	p.printInfo(fmt.Sprintf("Checking %d band entries for potential duplicates (threshold: %d)...", len(allBands), threshold))
+
+	checkedPairs := make(map[string]bool)
+	duplicatesFound := 0
+
+	for i := 0; i < len(allBands); i++ {
+		for j := i + 1; j < len(allBands); j++ {
+			name1 := allBands[i].Name
+			name2 := allBands[j].Name
+			source1 := allBands[i].Source
+			source2 := allBands[j].Source
+
+			// Create normalized pair key
+			lower1 := strings.ToLower(name1)
+			lower2 := strings.ToLower(name2)
+			var pairKey string
+			if lower1 < lower2 {
+				pairKey = lower1 + "|" + lower2
+			} else {
+				pairKey = lower2 + "|" + lower1
+			}
+
+			if checkedPairs[pairKey] {
+				continue
+			}
+			checkedPairs[pairKey] = true
+
+			// Skip if exactly the same (legitimate duplicates across festivals)
+			if name1 == name2 {
+				continue
+			}
+
+			distance := modelData.LevenshteinDistance(lower1, lower2)
+
+			if distance <= threshold {
+				message := fmt.Sprintf("  Potential duplicate (distance=%d): '%s' (%s) ↔ '%s' (%s)", distance, name1, source1, name2, source2)
+				if hideWarnings {
+					p.record("warning", message)
+				} else {
+					p.printWarning(message)
+				}
+				result.Warnings++
+				duplicatesFound++
+			}
+		}
+	}
+
+	if duplicatesFound == 0 {
+		p.printSuccess("No potential duplicates detected")
+	} else {
+		if hideWarnings {
+			p.printInfo(fmt.Sprintf("Found %d potential duplicate(s) (use without --hide-warnings to see details)", duplicatesFound))
+		} else {
+			p.printInfo(fmt.Sprintf("Found %d potential duplicate(s)", duplicatesFound))
+		}
+	}
+
+	return result
+}
+
+// Run validates the database file at dbPath. With opts.Fix the formatting
+// issues are corrected and the database is saved. The error is only set when
+// the database cannot be read or saved; issues are counted in the result.
+func Run(dbPath string, opts Options) (Result, error) {
+	p := &printer{out: opts.Out, color: opts.Color}
+	if p.out == nil {
+		p.out = io.Discard
+	}
+
+	_, _ = fmt.Fprintf(p.out, "\n%s\n", p.paint("🎸 Metal Festivals Database Validator", ColorBold+ColorCyan))
+	_, _ = fmt.Fprintf(p.out, "%s\n", p.paint(strings.Repeat("=", 80), ColorBold))
+
+	if opts.Fix {
+		p.printInfo("🔧 Fix mode enabled: Formatting issues will be automatically corrected")
+		_, _ = fmt.Fprintln(p.out)
+	}
+
+	if opts.HideWarnings {
+		p.printInfo("🔇 Hide warnings mode enabled: Warning details will be hidden")
+		_, _ = fmt.Fprintln(p.out)
+	}
+
+	// Validate JSON structure
+	data, err := p.validateJSONStructure(dbPath)
+	if err != nil {
+		_, _ = fmt.Fprintf(p.out, "\n%s\n", p.paint("❌ Validation failed: Invalid JSON structure", ColorRed))
+		return Result{Errors: 1, Issues: p.issues}, err
+	}
+
+	// Print data summary
+	p.printInfo(fmt.Sprintf("Loaded %d festivals and %d bands", len(data.Festivals), len(data.Bands)))
+
+	// Run all validation checks
+	result := Result{Fixed: opts.Fix}
+	for _, check := range []ValidationResult{
+		p.validateBandNames(data, opts.Fix),
+		p.validateGenres(data, opts.Fix),
+		p.validateMemberRoles(data, opts.Fix),
+		p.validateBandKeys(data, opts.Fix),
+		p.detectDuplicates(data, 2, opts.HideWarnings),
+	} {
+		result.Errors += check.Errors
+		result.Warnings += check.Warnings
+	}
+	result.Issues = p.issues
+	if result.Issues == nil {
+		result.Issues = []Issue{}
+	}
+
+	// Save fixed data if in fix mode
+	if opts.Fix {
+		p.printHeader("SAVING CHANGES")
+		jsonData, err := json.MarshalIndent(data, "", "  ")
+		if err != nil {
+			p.printError(fmt.Sprintf("Error marshaling JSON: %v", err))
+			return result, err
+		}
+
+		// #nosec G306 - db.json needs to be readable by other processes
+		if err := os.WriteFile(dbPath, jsonData, 0644); err != nil {
+			p.printError(fmt.Sprintf("Error writing file: %v", err))
+			return result, err
+		}
+
+		p.printSuccess(fmt.Sprintf("Changes saved successfully to %s", dbPath))
+	}
+
+	// Print summary
+	p.printHeader("VALIDATION SUMMARY")
+
+	if opts.Fix {
+		if result.Errors == 0 && result.Warnings == 0 {
+			_, _ = fmt.Fprintf(p.out, "%s\n", p.paint("✅ All validations passed! No issues found.", ColorBold+ColorGreen))
+		} else {
+			_, _ = fmt.Fprintf(p.out, "%s\n", p.paint("✅ All fixable issues have been corrected!", ColorBold+ColorGreen))
+			if result.Warnings > 0 {
+				_, _ = fmt.Fprintf(p.out, "%s\n", p.paint(fmt.Sprintf("⚠️  Found %d warning(s) (not auto-fixable)", result.Warnings), ColorBold+ColorYellow))
+			}
+		}
+		return result, nil
+	}
+
+	if result.Errors == 0 && result.Warnings == 0 {
+		_, _ = fmt.Fprintf(p.out, "%s\n", p.paint("✅ All validations passed! No issues found.", ColorBold+ColorGreen))
+		return result, nil
+	}
+
+	if result.Errors > 0 {
+		_, _ = fmt.Fprintf(p.out, "%s\n", p.paint(fmt.Sprintf("❌ Found %d error(s)", result.Errors), ColorBold+ColorRed))
+		_, _ = fmt.Fprintf(p.out, "%s\n", p.paint("💡 Tip: Run with --fix flag to automatically correct these issues", ColorBlue))
+	}
+	if result.Warnings > 0 {
+		_, _ = fmt.Fprintf(p.out, "%s\n", p.paint(fmt.Sprintf("⚠️  Found %d warning(s)", result.Warnings), ColorBold+ColorYellow))
+	}
+
+	return result, nil
+}
@@ -1,125 +1,20 @@
-package main
+package validate
 
 import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 
 	"github.com/neovasili/metal-fests/internal/data"
+	"github.com/neovasili/metal-fests/internal/model"
 )
 
-func TestLevenshteinDistance(t *testing.T) {
-	tests := []struct {
-		name     string
-		s1       string
-		s2       string
-		expected int
-	}{
-		{
-			name:     "Identical strings",
-			s1:       "hello",
-			s2:       "hello",
-			expected: 0,
-		},
-		{
-			name:     "One character difference",
-			s1:       "hello",
-			s2:       "hallo",
-			expected: 1,
-		},
-		{
-			name:     "Empty strings",
-			s1:       "",
-			s2:       "",
-			expected: 0,
-		},
-		{
-			name:     "Empty to non-empty",
-			s1:       "",
-			s2:       "hello",
-			expected: 5,
-		},
-		{
-			name:     "Multiple operations",
-			s1:       "kitten",
-			s2:       "sitting",
-			expected: 3,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := levenshteinDistance(tt.s1, tt.s2)
-			if result != tt.expected {
-				t.Errorf("levenshteinDistance(%q, %q) = %d, want %d", tt.s1, tt.s2, result, tt.expected)
-			}
-		})
-	}
-}
-
-func TestMinInt(t *testing.T) {
-	tests := []struct {
-		name     string
-		a        int
-		b        int
-		c        int
-		expected int
-	}{
-		{
-			name:     "a is minimum",
-			a:        1,
-			b:        2,
-			c:        3,
-			expected: 1,
-		},
-		{
-			name:     "b is minimum",
-			a:        3,
-			b:        1,
-			c:        2,
-			expected: 1,
-		},
-		{
-			name:     "c is minimum",
-			a:        3,
-			b:        2,
-			c:        1,
-			expected: 1,
-		},
-		{
-			name:     "All equal",
-			a:        5,
-			b:        5,
-			c:        5,
-			expected: 5,
-		},
-		{
-			name:     "Negative numbers",
-			a:        -5,
-			b:        -2,
-			c:        -10,
-			expected: -10,
-		},
-		{
-			name:     "Zero included",
-			a:        0,
-			b:        1,
-			c:        2,
-			expected: 0,
-		},
-	}
-
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			result := minInt(tt.a, tt.b, tt.c)
-			if result != tt.expected {
-				t.Errorf("minInt(%d, %d, %d) = %d, want %d", tt.a, tt.b, tt.c, result, tt.expected)
-			}
-		})
-	}
-}
-
 func TestIsProperlyCapitalized(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -278,3 +173,67 @@ func TestGenerateBandKey(t *testing.T) {
 		})
 	}
 }
+
+func writeTestDatabase(t *testing.T, db model.Database) string {
+	t.Helper()
+	content, err := json.Marshal(db)
+	if err != nil {
+		t.Fatalf("failed to marshal database: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "db.json")
+	if err := os.WriteFile(path, content, 0600); err != nil {
+		t.Fatalf("failed to write database: %v", err)
+	}
+	return path
+}
+
+func TestRun(t *testing.T) {
+	db := model.Database{
+		Bands: []model.Band{
+			{Key: "metallica", Name: "Metallica", Genres: []string{"thrash metal"}},
+			{Key: "iron-maiden", Name: "Iron Maiden", Genres: []string{"Heavy Metal"}},
+		},
+	}
+
+	t.Run("Reports issues", func(t *testing.T) {
+		path := writeTestDatabase(t, db)
+		var out bytes.Buffer
+		result, err := Run(path, Options{Out: &out})
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		if result.Errors != 1 || len(result.Issues) != 1 {
+			t.Fatalf("Run() = %+v, want one error", result)
+		}
+		if issue := result.Issues[0]; issue.Check != "MUSIC GENRE CAPITALIZATION" || issue.Severity != "error" {
+			t.Errorf("unexpected issue: %+v", issue)
+		}
+		if strings.Contains(out.String(), ColorEnd) {
+			t.Error("Run() should not color the output unless asked to")
+		}
+	})
+
+	t.Run("Fixes issues", func(t *testing.T) {
+		path := writeTestDatabase(t, db)
+		if _, err := Run(path, Options{Fix: true}); err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		result, err := Run(path, Options{})
+		if err != nil {
+			t.Fatalf("Run() failed: %v", err)
+		}
+		if result.Errors != 0 {
+			t.Errorf("Run() after fixing = %+v, want no errors", result)
+		}
+	})
+
+	t.Run("Invalid JSON", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "db.json")
+		if err := os.WriteFile(path, []byte("{"), 0600); err != nil {
+			t.Fatalf("failed to write database: %v", err)
+		}
+		if _, err := Run(path, Options{}); err == nil {
+			t.Error("Run() should fail on invalid JSON")
+		}
+	})
+}
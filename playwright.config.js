@@ -25,7 +25,7 @@ export default defineConfig({
   ],
 
   webServer: {
-    command: "go run server.go",
+    command: "go run ./cmd/metal-fests serve",
     url: "http://localhost:8000",
     reuseExistingServer: !process.env.CI,
     timeout: 120000,
@@ -30,6 +30,8 @@ import BandManager from "./js/band-manager.js";
 import HeaderManager from "./js/header-manager.js";
 import ClientRouter from "./js/router.js";
 import Notification from "./admin/js/notification.js";
+import ApiAuth from "./admin/js/api-auth.js";
+import ProvenanceBadge from "./admin/js/provenance-badge.js";
 import MultiselectDropdown from "./admin/js/multiselect-dropdown.js";
 import BandReviewManager from "./admin/js/band-review-manager.js";
 import BandReviewedManager from "./admin/js/band-reviewed-manager.js";
@@ -39,6 +41,7 @@ import AdminNav from "./components/admin-nav/admin-nav.js";
 import AdminList from "./components/admin-list/admin-list.js";
 import FestivalManager from "./admin/js/managers/FestivalManager.js";
 import AdminBandManager from "./admin/js/managers/BandManager.js";
+import AdminBandEditForm from "./admin/js/managers/BandEditForm.js";
 import AdminPageLoader from "./admin/js/admin-page-loader.js";
 
 // Make classes globally available
@@ -52,6 +55,8 @@ globalThis.BandManager = BandManager;
 globalThis.HeaderManager = HeaderManager;
 globalThis.ClientRouter = ClientRouter;
 globalThis.Notification = Notification;
+globalThis.ApiAuth = ApiAuth;
+globalThis.ProvenanceBadge = ProvenanceBadge;
 globalThis.MultiselectDropdown = MultiselectDropdown;
 globalThis.BandReviewManager = BandReviewManager;
 globalThis.BandReviewedManager = BandReviewedManager;
@@ -61,4 +66,5 @@ globalThis.AdminNav = AdminNav;
 globalThis.AdminList = AdminList;
 globalThis.FestivalManager = FestivalManager;
 globalThis.AdminBandManager = AdminBandManager;
+globalThis.AdminBandEditForm = AdminBandEditForm;
 globalThis.AdminPageLoader = AdminPageLoader;